@@ -1,22 +1,24 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/bernos/ecso/pkg/ecso/ui"
 	"github.com/bernos/ecso/pkg/ecso/util"
 )
 
-func LoadContainerList(tasks []*ecs.Task, ecsAPI ecsiface.ECSAPI) (ContainerList, error) {
+func LoadContainerList(ctx context.Context, tasks []*ecs.Task, ecsAPI ecsiface.ECSAPI) (ContainerList, error) {
 	result := make([]*Container, 0)
 
 	for _, task := range tasks {
 
-		taskDefinition, err := ecsAPI.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{
+		taskDefinition, err := ecsAPI.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
 			TaskDefinition: task.TaskDefinitionArn,
 		})
 
@@ -28,12 +30,7 @@ func LoadContainerList(tasks []*ecs.Task, ecsAPI ecsiface.ECSAPI) (ContainerList
 
 			for _, containerDefinition := range taskDefinition.TaskDefinition.ContainerDefinitions {
 				if *containerDefinition.Name == *container.Name {
-					result = append(result, &Container{
-						task:                task,
-						container:           container,
-						containerDefinition: containerDefinition,
-					})
-
+					result = append(result, newContainer(task, container, containerDefinition))
 					break
 				}
 			}
@@ -43,10 +40,46 @@ func LoadContainerList(tasks []*ecs.Task, ecsAPI ecsiface.ECSAPI) (ContainerList
 	return result, nil
 }
 
+func newContainer(task *ecs.Task, container *ecs.Container, containerDefinition *ecs.ContainerDefinition) *Container {
+	c := &Container{
+		Name:                 aws.StringValue(containerDefinition.Name),
+		Image:                aws.StringValue(containerDefinition.Image),
+		Group:                aws.StringValue(task.Group),
+		Status:               aws.StringValue(container.LastStatus),
+		ContainerArn:         aws.StringValue(container.ContainerArn),
+		TaskArn:              aws.StringValue(task.TaskArn),
+		TaskDefinitionArn:    aws.StringValue(task.TaskDefinitionArn),
+		ContainerInstanceArn: aws.StringValue(task.ContainerInstanceArn),
+		Ports:                make([]*ContainerPort, 0),
+	}
+
+	for _, b := range container.NetworkBindings {
+		c.Ports = append(c.Ports, &ContainerPort{
+			ContainerPort: aws.Int64Value(b.ContainerPort),
+			HostPort:      aws.Int64Value(b.HostPort),
+			Protocol:      aws.StringValue(b.Protocol),
+		})
+	}
+
+	return c
+}
+
 type Container struct {
-	task                *ecs.Task
-	container           *ecs.Container
-	containerDefinition *ecs.ContainerDefinition
+	Name                 string
+	Image                string
+	Group                string
+	Status               string
+	ContainerArn         string
+	TaskArn              string
+	TaskDefinitionArn    string
+	ContainerInstanceArn string
+	Ports                []*ContainerPort
+}
+
+type ContainerPort struct {
+	ContainerPort int64
+	HostPort      int64
+	Protocol      string
 }
 
 type ContainerList []*Container
@@ -59,27 +92,21 @@ func (cs ContainerList) WriteTo(w io.Writer) (int64, error) {
 	}
 
 	for _, c := range cs {
-		port := ""
-
-		if len(c.container.NetworkBindings) > 0 {
-			ports := make([]string, 0)
-
-			for _, b := range c.container.NetworkBindings {
-				ports = append(ports, fmt.Sprintf("%d:%d/%s", *b.ContainerPort, *b.HostPort, *b.Protocol))
-			}
+		ports := make([]string, 0)
 
-			port = strings.Join(ports, ",")
+		for _, p := range c.Ports {
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", p.ContainerPort, p.HostPort, p.Protocol))
 		}
 
 		row := fmt.Sprintf(
 			"%s|%s|%s|%s|%s|%s|%s",
-			*c.containerDefinition.Name,
-			*c.containerDefinition.Image,
-			*c.task.Group,
-			*c.container.LastStatus,
-			util.GetIDFromArn(*c.task.TaskDefinitionArn),
-			util.GetIDFromArn(*c.task.ContainerInstanceArn),
-			port)
+			c.Name,
+			c.Image,
+			c.Group,
+			c.Status,
+			util.GetIDFromArn(c.TaskDefinitionArn),
+			util.GetIDFromArn(c.ContainerInstanceArn),
+			strings.Join(ports, ","))
 
 		if n, err := tw.Write([]byte(row)); err != nil {
 			return int64(n), err
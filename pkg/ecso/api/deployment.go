@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+// deployment tracks a single deployment to an environment. It sends the
+// deployment's events to the environment's notifiers, and records its
+// outcome in the environment's history. A failure to notify is written as a
+// warning, and never fails the deployment itself
+type deployment struct {
+	ctx     context.Context
+	envAPI  EnvironmentAPI
+	env     *ecso.Environment
+	event   helpers.DeploymentEvent
+	history *historyEntry
+	w       io.Writer
+}
+
+// startDeployment sends the started event for a deployment, and returns a
+// deployment that records its outcome
+func startDeployment(ctx context.Context, envAPI EnvironmentAPI, project *ecso.Project, env *ecso.Environment, event helpers.DeploymentEvent, w io.Writer) *deployment {
+	history := startHistoryEntry(envAPI, project, env, event.Service, historyAction(&event), event.Version, w)
+
+	event.Actor = history.record.Actor
+
+	d := &deployment{
+		ctx:     ctx,
+		envAPI:  envAPI,
+		env:     env,
+		event:   event,
+		history: history,
+		w:       w,
+	}
+
+	d.send(helpers.DeploymentStarted, nil)
+
+	return d
+}
+
+// succeeded records that the deployment succeeded
+func (d *deployment) succeeded() {
+	d.send(helpers.DeploymentSucceeded, nil)
+	d.history.finish(nil)
+}
+
+// failed records that the deployment failed, and returns err so that it can
+// be used in return statements
+func (d *deployment) failed(err error) error {
+	d.send(helpers.DeploymentFailed, err)
+	return d.history.finish(err)
+}
+
+func (d *deployment) send(eventType helpers.DeploymentEventType, deployErr error) {
+	event := d.event
+	event.Type = eventType
+	event.Timestamp = time.Now().UTC()
+
+	if eventType != helpers.DeploymentStarted {
+		event.Duration = time.Since(d.history.started).Round(time.Second).Seconds()
+	}
+
+	if deployErr != nil {
+		event.Error = deployErr.Error()
+	}
+
+	ctx := d.ctx
+
+	// The outcome of a deployment must be reported even if the command was
+	// interrupted, so it is not bound to the command's context, in the same
+	// way that locks are released
+	if eventType != helpers.DeploymentStarted {
+		ctx = context.Background()
+	}
+
+	if err := d.envAPI.Notify(ctx, d.env, &event); err != nil {
+		fmt.Fprintf(d.w, "WARNING Failed to send %s %s notification. %s\n", event.Action, eventType, err.Error())
+	}
+}
+
+// deploymentEvent creates the event for a deployment of a service
+func deploymentEvent(project *ecso.Project, env *ecso.Environment, service *ecso.Service, action helpers.DeploymentAction, version string) helpers.DeploymentEvent {
+	return helpers.DeploymentEvent{
+		Action:      action,
+		Project:     project.Name,
+		Environment: env.Name,
+		Service:     service.Name,
+		Version:     version,
+	}
+}
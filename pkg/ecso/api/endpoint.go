@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+	"github.com/bernos/ecso/pkg/ecso/util"
+)
+
+// Endpoint is a host and port that a service is registered at for service
+// discovery, and the running container that it belongs to, if any
+type Endpoint struct {
+	Name                 string
+	Target               string
+	Port                 int64
+	IP                   string
+	Container            string
+	ContainerArn         string
+	TaskArn              string
+	ContainerInstanceArn string
+
+	// Stale is true if no running container is listening at the endpoint
+	Stale bool
+
+	record *helpers.RecordSet
+}
+
+// Address returns the host:port of the endpoint, using the IP address of the
+// target if it is known
+func (e *Endpoint) Address() string {
+	host := e.IP
+
+	if host == "" {
+		host = strings.TrimSuffix(e.Target, ".")
+	}
+
+	return fmt.Sprintf("%s:%d", host, e.Port)
+}
+
+// EndpointList is the list of endpoints registered for a service
+type EndpointList []*Endpoint
+
+// Stale returns the stale endpoints in the list
+func (l EndpointList) Stale() EndpointList {
+	result := make(EndpointList, 0)
+
+	for _, e := range l {
+		if e.Stale {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// Live returns the endpoints in the list that are not stale
+func (l EndpointList) Live() EndpointList {
+	result := make(EndpointList, 0)
+
+	for _, e := range l {
+		if !e.Stale {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// staleRecordSets returns the record sets whose endpoints are all stale
+func (l EndpointList) staleRecordSets() []*helpers.RecordSet {
+	var (
+		result = make([]*helpers.RecordSet, 0)
+		live   = make(map[*helpers.RecordSet]bool)
+		seen   = make(map[*helpers.RecordSet]bool)
+	)
+
+	for _, e := range l {
+		if !e.Stale {
+			live[e.record] = true
+		}
+	}
+
+	for _, e := range l {
+		if e.record != nil && !live[e.record] && !seen[e.record] {
+			result = append(result, e.record)
+			seen[e.record] = true
+		}
+	}
+
+	return result
+}
+
+func (l EndpointList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+
+	tw.WriteHeader([]byte("ADDRESS|TARGET|CONTAINER|TASK|CONTAINER INSTANCE|STATUS"))
+
+	for _, e := range l {
+		status := "running"
+
+		if e.Stale {
+			status = "stale"
+		}
+
+		tw.Write([]byte(fmt.Sprintf(
+			"%s|%s|%s|%s|%s|%s",
+			e.Address(),
+			strings.TrimSuffix(e.Target, "."),
+			e.Container,
+			util.GetIDFromArn(e.TaskArn),
+			util.GetIDFromArn(e.ContainerInstanceArn),
+			status)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+// containerInstance is the EC2 instance that a container instance runs on
+type containerInstance struct {
+	PrivateIP      string
+	PrivateDNSName string
+}
+
+// loadContainerInstances returns the EC2 instance of each container instance
+// that the containers run on, keyed by container instance ARN
+func loadContainerInstances(ctx context.Context, ecsAPI ecsiface.ECSAPI, ec2API ec2iface.EC2API, cluster string, containers ContainerList) (map[string]*containerInstance, error) {
+	var (
+		result = make(map[string]*containerInstance)
+		arns   = make([]*string, 0)
+		seen   = make(map[string]bool)
+	)
+
+	for _, c := range containers {
+		if c.ContainerInstanceArn != "" && !seen[c.ContainerInstanceArn] {
+			arns = append(arns, aws.String(c.ContainerInstanceArn))
+			seen[c.ContainerInstanceArn] = true
+		}
+	}
+
+	instanceArns := make(map[string]string)
+	instanceIDs := make([]*string, 0)
+
+	// DescribeContainerInstances accepts at most 100 ARNs per request
+	for i := 0; i < len(arns); i += 100 {
+		end := i + 100
+
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		resp, err := ecsAPI.DescribeContainerInstancesWithContext(ctx, &ecs.DescribeContainerInstancesInput{
+			Cluster:            aws.String(cluster),
+			ContainerInstances: arns[i:end],
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ci := range resp.ContainerInstances {
+			instanceArns[aws.StringValue(ci.Ec2InstanceId)] = aws.StringValue(ci.ContainerInstanceArn)
+			instanceIDs = append(instanceIDs, ci.Ec2InstanceId)
+		}
+	}
+
+	if len(instanceIDs) == 0 {
+		return result, nil
+	}
+
+	err := ec2API.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIDs}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				result[instanceArns[aws.StringValue(instance.InstanceId)]] = &containerInstance{
+					PrivateIP:      aws.StringValue(instance.PrivateIpAddress),
+					PrivateDNSName: aws.StringValue(instance.PrivateDnsName),
+				}
+			}
+		}
+
+		return true
+	})
+
+	return result, err
+}
+
+// matchEndpoints builds the endpoint list for the SRV records named name,
+// matching each against the running containers by the IP of their container
+// instance and their host port. The service-discovery lambda also identifies
+// its records by container ARN, and Cloud Map by task ID
+func matchEndpoints(name string, records []*helpers.RecordSet, containers ContainerList, instances map[string]*containerInstance) EndpointList {
+	var (
+		result = make(EndpointList, 0)
+		ips    = make(map[string]string)
+	)
+
+	// The service-discovery lambda's SRV records point at the private DNS
+	// name of the container instance
+	for _, instance := range instances {
+		if instance.PrivateDNSName != "" {
+			ips[instance.PrivateDNSName] = instance.PrivateIP
+		}
+	}
+
+	// Cloud Map SRV records point at A records in the same zone
+	for _, record := range records {
+		if *record.Type == "A" && len(record.ResourceRecords) > 0 {
+			ips[strings.TrimSuffix(*record.Name, ".")] = *record.ResourceRecords[0].Value
+		}
+	}
+
+	for _, record := range records {
+		if *record.Name != name || *record.Type != "SRV" {
+			continue
+		}
+
+		for _, rr := range record.ResourceRecords {
+			target, port, ok := parseSRVValue(*rr.Value)
+			if !ok {
+				continue
+			}
+
+			endpoint := &Endpoint{
+				Name:   name,
+				Target: target,
+				Port:   port,
+				IP:     ips[strings.TrimSuffix(target, ".")],
+				Stale:  true,
+				record: record,
+			}
+
+			if c := findEndpointContainer(record, endpoint, containers, instances); c != nil {
+				endpoint.Container = c.Name
+				endpoint.ContainerArn = c.ContainerArn
+				endpoint.TaskArn = c.TaskArn
+				endpoint.ContainerInstanceArn = c.ContainerInstanceArn
+				endpoint.Stale = false
+			}
+
+			result = append(result, endpoint)
+		}
+	}
+
+	return result
+}
+
+// findEndpointContainer returns the running container listening at the
+// endpoint, or nil if there is none. Containers on other instances are never
+// matched, as the same host port is used on every instance
+func findEndpointContainer(record *helpers.RecordSet, endpoint *Endpoint, containers ContainerList, instances map[string]*containerInstance) *Container {
+	if endpoint.IP == "" {
+		return nil
+	}
+
+	id := ""
+
+	if record.SetIdentifier != nil {
+		id = *record.SetIdentifier
+	}
+
+	for _, c := range containers {
+		if c.Status != "RUNNING" {
+			continue
+		}
+
+		if id != "" && id != c.ContainerArn && id != util.GetIDFromArn(c.TaskArn) {
+			continue
+		}
+
+		if instance, ok := instances[c.ContainerInstanceArn]; !ok || instance.PrivateIP != endpoint.IP {
+			continue
+		}
+
+		for _, p := range c.Ports {
+			if p.HostPort == endpoint.Port {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseSRVValue parses the target and port from an SRV record value in the
+// form "priority weight port target"
+func parseSRVValue(value string) (string, int64, bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return "", 0, false
+	}
+
+	port, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return fields[3], port, true
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/bernos/ecso/pkg/ecso/api/mocks"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+func makeTestRecordSet(name, recordType, id string, values ...string) *helpers.RecordSet {
+	record := &route53.ResourceRecordSet{
+		Name: aws.String(name),
+		Type: aws.String(recordType),
+	}
+
+	if id != "" {
+		record.SetIdentifier = aws.String(id)
+	}
+
+	for _, v := range values {
+		record.ResourceRecords = append(record.ResourceRecords, &route53.ResourceRecord{Value: aws.String(v)})
+	}
+
+	return &helpers.RecordSet{ResourceRecordSet: record, HostedZoneID: "Z1"}
+}
+
+func TestMatchEndpoints(t *testing.T) {
+	name := "web.my-project-dev.example.com."
+
+	records := []*helpers.RecordSet{
+		makeTestRecordSet(name, "SRV", "arn:aws:ecs:ap-southeast-2:123:container/live", "1 1 32768 ip-10-0-1-23.ap-southeast-2.compute.internal"),
+		makeTestRecordSet(name, "SRV", "arn:aws:ecs:ap-southeast-2:123:container/gone", "1 1 32769 ip-10-0-1-24.ap-southeast-2.compute.internal"),
+		makeTestRecordSet(name, "SRV", "task-1", "1 1 32770 task-1.web.my-project-dev.local"),
+		makeTestRecordSet("task-1.web.my-project-dev.local.", "A", "", "10.0.2.5"),
+		makeTestRecordSet(name, "SRV", "", "1 1 32771 ip-10-0-1-23.ap-southeast-2.compute.internal"),
+		makeTestRecordSet(name, "SRV", "", "1 1 32771 ip-10-0-1-24.ap-southeast-2.compute.internal"),
+	}
+
+	instances := map[string]*containerInstance{
+		"arn:aws:ecs:ap-southeast-2:123:container-instance/a": {PrivateIP: "10.0.1.23", PrivateDNSName: "ip-10-0-1-23.ap-southeast-2.compute.internal"},
+		"arn:aws:ecs:ap-southeast-2:123:container-instance/b": {PrivateIP: "10.0.2.5", PrivateDNSName: "ip-10-0-2-5.ap-southeast-2.compute.internal"},
+	}
+
+	containers := ContainerList{
+		{
+			Name:                 "web",
+			Status:               "RUNNING",
+			ContainerArn:         "arn:aws:ecs:ap-southeast-2:123:container/live",
+			TaskArn:              "arn:aws:ecs:ap-southeast-2:123:task/task-0",
+			ContainerInstanceArn: "arn:aws:ecs:ap-southeast-2:123:container-instance/a",
+			Ports:                []*ContainerPort{{ContainerPort: 80, HostPort: 32768, Protocol: "tcp"}},
+		},
+		{
+			Name:                 "web",
+			Status:               "RUNNING",
+			ContainerArn:         "arn:aws:ecs:ap-southeast-2:123:container/other",
+			TaskArn:              "arn:aws:ecs:ap-southeast-2:123:task/task-1",
+			ContainerInstanceArn: "arn:aws:ecs:ap-southeast-2:123:container-instance/b",
+			Ports:                []*ContainerPort{{ContainerPort: 80, HostPort: 32770, Protocol: "tcp"}},
+		},
+		{
+			Name:                 "web",
+			Status:               "RUNNING",
+			ContainerArn:         "arn:aws:ecs:ap-southeast-2:123:container/unidentified",
+			TaskArn:              "arn:aws:ecs:ap-southeast-2:123:task/task-2",
+			ContainerInstanceArn: "arn:aws:ecs:ap-southeast-2:123:container-instance/a",
+			Ports:                []*ContainerPort{{ContainerPort: 80, HostPort: 32771, Protocol: "tcp"}},
+		},
+	}
+
+	endpoints := matchEndpoints(name, records, containers, instances)
+
+	if len(endpoints) != 5 {
+		t.Fatalf("Want 5 endpoints, got %d", len(endpoints))
+	}
+
+	for i, want := range []struct {
+		address string
+		stale   bool
+	}{
+		{"10.0.1.23:32768", false},
+		{"ip-10-0-1-24.ap-southeast-2.compute.internal:32769", true},
+		{"10.0.2.5:32770", false},
+		{"10.0.1.23:32771", false},
+		// The same host port on an instance that the container is not on
+		{"ip-10-0-1-24.ap-southeast-2.compute.internal:32771", true},
+	} {
+		if got := endpoints[i].Address(); got != want.address {
+			t.Errorf("Want address %s, got %s", want.address, got)
+		}
+
+		if endpoints[i].Stale != want.stale {
+			t.Errorf("Want %s stale to be %t", endpoints[i].Address(), want.stale)
+		}
+	}
+
+	stale := endpoints.staleRecordSets()
+
+	if len(stale) != 2 || stale[0] != records[1] || stale[1] != records[5] {
+		t.Errorf("Want only the records without a running container to be stale, got %v", stale)
+	}
+}
+
+func TestLoadContainerInstances(t *testing.T) {
+	var (
+		ecsAPI = &mocks.ECSAPIMock{}
+		ec2API = &mocks.EC2APIMock{}
+		arn    = "arn:aws:ecs:ap-southeast-2:123:container-instance/a"
+	)
+
+	ecsAPI.DescribeContainerInstancesReturns(&ecs.DescribeContainerInstancesOutput{
+		ContainerInstances: []*ecs.ContainerInstance{
+			{ContainerInstanceArn: aws.String(arn), Ec2InstanceId: aws.String("i-123")},
+		},
+	}, nil)
+
+	ec2API.DescribeInstancesReturns(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{
+			Instances: []*ec2.Instance{{
+				InstanceId:       aws.String("i-123"),
+				PrivateIpAddress: aws.String("10.0.1.23"),
+				PrivateDnsName:   aws.String("ip-10-0-1-23.ap-southeast-2.compute.internal"),
+			}},
+		}},
+	}, nil)
+
+	containers := ContainerList{
+		{Name: "web", ContainerInstanceArn: arn},
+		{Name: "sidecar", ContainerInstanceArn: arn},
+	}
+
+	instances, err := loadContainerInstances(context.Background(), ecsAPI, ec2API, "dev", containers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(instances) != 1 || instances[arn] == nil || instances[arn].PrivateIP != "10.0.1.23" {
+		t.Errorf("Want the instance behind %s, got %v", arn, instances)
+	}
+}
+
+func TestParseSRVValue(t *testing.T) {
+	target, port, ok := parseSRVValue("1 1 8080 host.example.com.")
+
+	if !ok || target != "host.example.com." || port != 8080 {
+		t.Errorf("Unexpected result %s %d %t", target, port, ok)
+	}
+
+	if _, _, ok := parseSRVValue("10.0.0.1"); ok {
+		t.Errorf("Want non-SRV values to be rejected")
+	}
+}
@@ -1,39 +1,53 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
-	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/resources"
 	"github.com/bernos/ecso/pkg/ecso/ui"
 	"github.com/bernos/ecso/pkg/ecso/util"
 )
 
 type EnvironmentAPI interface {
 	DescribeEnvironment(env *ecso.Environment) (*EnvironmentDescription, error)
-	EnvironmentUp(p *ecso.Project, env *ecso.Environment, dryRun bool, w io.Writer) error
-	EnvironmentDown(p *ecso.Project, env *ecso.Environment, w io.Writer) error
+	EnvironmentUp(ctx context.Context, p *ecso.Project, env *ecso.Environment, overrides *StackOverrides, pruneResources, dryRun bool, w io.Writer) error
+	EnvironmentDown(ctx context.Context, p *ecso.Project, env *ecso.Environment, w io.Writer) error
 	IsEnvironmentUp(env *ecso.Environment) (bool, error)
 	GetCurrentAWSAccount() (string, error)
+	GetCurrentAWSPrincipal() (string, error)
 	GetEcsoBucket(env *ecso.Environment) (string, error)
-	GetECSServices(env *ecso.Environment) ([]*ecs.Service, error)
-	GetECSTasks(env *ecso.Environment) ([]*ecs.Task, error)
-	GetECSContainers(env *ecso.Environment) (ContainerList, error)
-	SendNotification(env *ecso.Environment, msg string) error
+	CheckEcsoBucket(env *ecso.Environment) (helpers.BucketCheckList, error)
+	GetHistoryStore(env *ecso.Environment) (helpers.HistoryStore, error)
+	GetLockStore(env *ecso.Environment) (helpers.LockStore, error)
+	GetECSServices(ctx context.Context, env *ecso.Environment) ([]*ecs.Service, error)
+	GetECSTasks(ctx context.Context, env *ecso.Environment) ([]*ecs.Task, error)
+	GetECSContainers(ctx context.Context, env *ecso.Environment) (ContainerList, error)
+	Notify(ctx context.Context, env *ecso.Environment, event *helpers.DeploymentEvent) error
+	GetAvailableVersions(ctx context.Context, env *ecso.Environment) (PackageVersionList, error)
+	PruneVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, keep int, dryRun bool, w io.Writer) (PackageVersionList, error)
+	EnvironmentDrift(ctx context.Context, env *ecso.Environment, w io.Writer) (*helpers.StackDrift, error)
+	SetEnvironmentProtection(env *ecso.Environment, enabled bool) error
 }
 
 // New creates a new API
@@ -45,6 +59,8 @@ func NewEnvironmentAPI(
 	s3API s3iface.S3API,
 	snsAPI snsiface.SNSAPI,
 	stsAPI stsiface.STSAPI,
+	ecrAPI ecriface.ECRAPI,
+	ec2API ec2iface.EC2API,
 ) EnvironmentAPI {
 	return &environmentAPI{
 		cloudformationAPI: cloudformationAPI,
@@ -54,6 +70,8 @@ func NewEnvironmentAPI(
 		s3API:             s3API,
 		snsAPI:            snsAPI,
 		stsAPI:            stsAPI,
+		ecrAPI:            ecrAPI,
+		ec2API:            ec2API,
 	}
 }
 
@@ -65,6 +83,8 @@ type environmentAPI struct {
 	s3API             s3iface.S3API
 	snsAPI            snsiface.SNSAPI
 	stsAPI            stsiface.STSAPI
+	ecrAPI            ecriface.ECRAPI
+	ec2API            ec2iface.EC2API
 }
 
 func (api *environmentAPI) GetCurrentAWSAccount() (string, error) {
@@ -76,7 +96,26 @@ func (api *environmentAPI) GetCurrentAWSAccount() (string, error) {
 	return *resp.Account, nil
 }
 
+// GetCurrentAWSPrincipal returns the ARN of the IAM principal whose
+// credentials are being used
+func (api *environmentAPI) GetCurrentAWSPrincipal() (string, error) {
+	resp, err := api.stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+
+	return *resp.Arn, nil
+}
+
+// GetEcsoBucket returns the name of the bucket that the environment's
+// deployment packages and resources are stored in. The name can be set in the
+// project or environment's bucket configuration, and defaults to
+// ecso-<region>-<account>
 func (api *environmentAPI) GetEcsoBucket(env *ecso.Environment) (string, error) {
+	if name := env.GetBucketConfiguration().Name; name != "" {
+		return name, nil
+	}
+
 	resp, err := api.stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
 	if err != nil {
 		return "", err
@@ -85,6 +124,39 @@ func (api *environmentAPI) GetEcsoBucket(env *ecso.Environment) (string, error)
 	return fmt.Sprintf("ecso-%s-%s", env.Region, *resp.Account), nil
 }
 
+// CheckEcsoBucket compares the settings of the environment's ecso bucket with
+// its bucket configuration
+func (api *environmentAPI) CheckEcsoBucket(env *ecso.Environment) (helpers.BucketCheckList, error) {
+	bucket, err := api.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.NewS3Helper(api.s3API, env.Region).CheckBucket(bucket, env.GetBucketConfiguration())
+}
+
+// GetHistoryStore returns the store that the environment's deployment
+// history is kept in, in the environment's ecso bucket
+func (api *environmentAPI) GetHistoryStore(env *ecso.Environment) (helpers.HistoryStore, error) {
+	bucket, err := api.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.NewS3HistoryStore(api.s3API, env.Region, bucket, env.GetHistoryBucketPrefix()), nil
+}
+
+// GetLockStore returns the store that locks on the environment and its
+// services are kept in, in the environment's ecso bucket
+func (api *environmentAPI) GetLockStore(env *ecso.Environment) (helpers.LockStore, error) {
+	bucket, err := api.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.NewS3LockStore(api.s3API, env.Region, bucket, env.GetLockBucketPrefix()), nil
+}
+
 func (api *environmentAPI) DescribeEnvironment(env *ecso.Environment) (*EnvironmentDescription, error) {
 	var (
 		stack       = env.GetCloudFormationStackName()
@@ -118,16 +190,16 @@ func (api *environmentAPI) DescribeEnvironment(env *ecso.Environment) (*Environm
 	return description, nil
 }
 
-func (api *environmentAPI) GetECSContainers(env *ecso.Environment) (ContainerList, error) {
-	tasks, err := api.GetECSTasks(env)
+func (api *environmentAPI) GetECSContainers(ctx context.Context, env *ecso.Environment) (ContainerList, error) {
+	tasks, err := api.GetECSTasks(ctx, env)
 	if err != nil {
 		return nil, err
 	}
 
-	return LoadContainerList(tasks, api.ecsAPI)
+	return LoadContainerList(ctx, tasks, api.ecsAPI)
 }
 
-func (api *environmentAPI) GetECSServices(env *ecso.Environment) ([]*ecs.Service, error) {
+func (api *environmentAPI) GetECSServices(ctx context.Context, env *ecso.Environment) ([]*ecs.Service, error) {
 	var (
 		count     = 0
 		batchSize = 10
@@ -140,7 +212,7 @@ func (api *environmentAPI) GetECSServices(env *ecso.Environment) ([]*ecs.Service
 	}
 
 	// TODO handle pages concurrently
-	if err := api.ecsAPI.ListServicesPages(params, func(o *ecs.ListServicesOutput, last bool) bool {
+	if err := api.ecsAPI.ListServicesPagesWithContext(ctx, params, func(o *ecs.ListServicesOutput, last bool) bool {
 		if count%batchSize == 0 {
 			batches = append(batches, make([]*string, 0))
 		}
@@ -161,7 +233,7 @@ func (api *environmentAPI) GetECSServices(env *ecso.Environment) ([]*ecs.Service
 			continue
 		}
 
-		desc, err := api.ecsAPI.DescribeServices(&ecs.DescribeServicesInput{
+		desc, err := api.ecsAPI.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
 			Services: batch,
 			Cluster:  aws.String(env.GetClusterName()),
 		})
@@ -178,21 +250,21 @@ func (api *environmentAPI) GetECSServices(env *ecso.Environment) ([]*ecs.Service
 	return services, nil
 }
 
-func (api *environmentAPI) GetECSTasks(env *ecso.Environment) ([]*ecs.Task, error) {
+func (api *environmentAPI) GetECSTasks(ctx context.Context, env *ecso.Environment) ([]*ecs.Task, error) {
 	taskArns := make([]*string, 0)
 
 	params := &ecs.ListTasksInput{
 		Cluster: aws.String(env.GetClusterName()),
 	}
 
-	if err := api.ecsAPI.ListTasksPages(params, func(o *ecs.ListTasksOutput, lastPage bool) bool {
+	if err := api.ecsAPI.ListTasksPagesWithContext(ctx, params, func(o *ecs.ListTasksOutput, lastPage bool) bool {
 		taskArns = append(taskArns, o.TaskArns...)
 		return !lastPage
 	}); err != nil {
 		return nil, err
 	}
 
-	resp, err := api.ecsAPI.DescribeTasks(&ecs.DescribeTasksInput{
+	resp, err := api.ecsAPI.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
 		Cluster: aws.String(env.GetClusterName()),
 		Tasks:   taskArns,
 	})
@@ -210,20 +282,31 @@ func (api *environmentAPI) IsEnvironmentUp(env *ecso.Environment) (bool, error)
 	return cfn.StackExists(env.GetCloudFormationStackName())
 }
 
-func (api *environmentAPI) EnvironmentDown(p *ecso.Project, env *ecso.Environment, w io.Writer) error {
+func (api *environmentAPI) EnvironmentDown(ctx context.Context, p *ecso.Project, env *ecso.Environment, w io.Writer) error {
 	var (
 		cfnHelper      = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
 		r53Helper      = helpers.NewRoute53Helper(api.route53API)
 		zone           = fmt.Sprintf("%s.", env.CloudFormationParameters["DNSZone"])
 		datadogDNSName = fmt.Sprintf("%s.%s.%s", "datadog", env.GetClusterName(), zone)
-		serviceAPI     = NewServiceAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI)
+		serviceAPI     = NewServiceAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
 		info           = ui.NewInfoWriter(w)
 	)
 
+	lock, err := acquireLock(ctx, api, env, "", HistoryActionEnvironmentDown)
+	if err != nil {
+		return err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	history := startHistoryEntry(api, p, env, "", HistoryActionEnvironmentDown, "", w)
+
 	// TODO do these concurrently
 	for _, service := range p.Services {
-		if err := serviceAPI.ServiceDown(p, env, service, w); err != nil {
-			return err
+		if err := serviceAPI.ServiceDown(ctx, p, env, service, w); err != nil {
+			return history.finish(err)
 		}
 
 		fmt.Fprint(w, "\n")
@@ -231,24 +314,53 @@ func (api *environmentAPI) EnvironmentDown(p *ecso.Project, env *ecso.Environmen
 
 	fmt.Fprintf(info, "Deleting environment Cloud Formation stack '%s'", env.GetCloudFormationStackName())
 
-	if err := cfnHelper.DeleteStack(env.GetCloudFormationStackName(), ui.NewPrefixWriter(w, "  ")); err != nil {
-		return err
+	if err := cfnHelper.DeleteStack(ctx, env.GetCloudFormationStackName(), ui.NewPrefixWriter(w, "  ")); err != nil {
+		return history.finish(err)
 	}
 
 	fmt.Fprint(w, "\n")
 	fmt.Fprintf(info, "Deleting %s SRV records", datadogDNSName)
 
-	return r53Helper.DeleteResourceRecordSetsByName(
+	return history.finish(r53Helper.DeleteResourceRecordSetsByName(
+		ctx,
 		datadogDNSName,
 		zone,
 		"Deleted by ecso environment rm",
-		ui.NewPrefixWriter(w, "  "))
+		ui.NewPrefixWriter(w, "  ")))
 }
 
-func (api *environmentAPI) EnvironmentUp(p *ecso.Project, env *ecso.Environment, dryRun bool, w io.Writer) error {
+// EnvironmentDrift detects drift in the environment stack and its nested
+// stacks, and compares them with the local environment templates
+func (api *environmentAPI) EnvironmentDrift(ctx context.Context, env *ecso.Environment, w io.Writer) (*helpers.StackDrift, error) {
+	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+
+	exists, err := cfn.StackExists(env.GetCloudFormationStackName())
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("The '%s' environment has not been deployed", env.Name)
+	}
+
+	return cfn.DetectDrift(ctx, env.GetCloudFormationStackName(), env.GetCloudFormationTemplateFile(), w)
+}
+
+func (api *environmentAPI) EnvironmentUp(ctx context.Context, p *ecso.Project, env *ecso.Environment, overrides *StackOverrides, pruneResources, dryRun bool, w io.Writer) error {
 	info := ui.NewInfoWriter(w)
 	version := util.VersionFromTime(time.Now())
 
+	if !overrides.IsEmpty() {
+		template, err := ioutil.ReadFile(env.GetCloudFormationTemplateFile())
+		if err != nil {
+			return err
+		}
+
+		if err := overrides.validate(template, reservedEnvironmentParams, reservedEnvironmentTags); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(info, "Updating environment to version %s", version)
 
 	bucket, err := api.GetEcsoBucket(env)
@@ -256,69 +368,329 @@ func (api *environmentAPI) EnvironmentUp(p *ecso.Project, env *ecso.Environment,
 		return err
 	}
 
-	if err := api.uploadEnvironmentResources(bucket, env, version, w); err != nil {
+	if dryRun {
+		// A dry run changes nothing in the bucket, so lambda functions are
+		// only packaged to find the keys they would be uploaded to
+		lambdaKeys, _, err := api.packageEnvironmentLambdas(p, env, w)
+		if err != nil {
+			return err
+		}
+
+		return api.previewEnvironmentStack(ctx, bucket, p, env, version, lambdaKeys, overrides, w)
+	}
+
+	lock, err := acquireLock(ctx, api, env, "", HistoryActionEnvironmentUp)
+	if err != nil {
 		return err
 	}
 
-	result, err := api.deployEnvironmentStack(bucket, p, env, version, dryRun, w)
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	deployment := startDeployment(ctx, api, p, env, helpers.DeploymentEvent{
+		Action:      helpers.DeploymentActionDeploy,
+		Project:     p.Name,
+		Environment: env.Name,
+		Version:     version,
+	}, w)
+
+	lambdaKeys, err := api.uploadEnvironment(ctx, bucket, p, env, pruneResources, w)
+	if err != nil {
+		return deployment.failed(err)
+	}
+
+	if _, err := api.deployEnvironmentStack(ctx, bucket, p, env, version, lambdaKeys, overrides, false, w); err != nil {
+		return deployment.failed(err)
+	}
+
+	if env.Protected {
+		if err := api.SetEnvironmentProtection(env, true); err != nil {
+			return deployment.failed(err)
+		}
+	}
+
+	deployment.succeeded()
+
+	if p.RetentionPolicy != nil && p.RetentionPolicy.Keep > 0 {
+		if _, err := api.PruneVersions(ctx, p, env, p.RetentionPolicy.Keep, false, ui.NewPrefixWriter(w, "  ")); err != nil {
+			fmt.Fprintf(w, "WARNING Failed to prune old environment versions. %s\n", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// setServiceDiscoveryParameter sets the ServiceDiscovery parameter of the
+// environment template. Templates created by earlier versions of ecso don't
+// declare it, and only support srv-lambda service discovery
+func setServiceDiscoveryParameter(env *ecso.Environment, template string, params map[string]string) error {
+	mode := env.GetServiceDiscovery()
+
+	if mode != ecso.ServiceDiscoverySRVLambda && mode != ecso.ServiceDiscoveryCloudMap {
+		return fmt.Errorf("Unknown ServiceDiscovery '%s' for the '%s' environment. Use %s or %s", mode, env.Name, ecso.ServiceDiscoverySRVLambda, ecso.ServiceDiscoveryCloudMap)
+	}
+
+	declared, err := helpers.TemplateFileDeclaresParameter(template, "ServiceDiscovery")
 	if err != nil {
 		return err
 	}
 
-	if dryRun {
-		resp, err := api.cloudformationAPI.DescribeChangeSet(&cloudformation.DescribeChangeSetInput{
-			ChangeSetName: aws.String(result.ChangeSetID),
-			StackName:     aws.String(result.StackID),
-		})
+	if declared {
+		params["ServiceDiscovery"] = mode
+		return nil
+	}
 
-		if err != nil {
-			return err
+	if mode != ecso.ServiceDiscoverySRVLambda {
+		return fmt.Errorf("The environment template does not support %s service discovery. Run `ecso environment upgrade-templates %s` to update it", mode, env.Name)
+	}
+
+	return nil
+}
+
+// uploadEnvironment ensures that the ecso bucket is configured, and uploads
+// the environment's resources and lambda functions to it. The keys of the
+// uploaded lambda bundles are returned by template parameter name
+func (api *environmentAPI) uploadEnvironment(ctx context.Context, bucket string, p *ecso.Project, env *ecso.Environment, pruneResources bool, w io.Writer) (map[string]string, error) {
+	s3Helper := helpers.NewS3Helper(api.s3API, env.Region)
+
+	if err := s3Helper.EnsureBucket(bucket, env.GetBucketConfiguration(), ui.NewPrefixWriter(w, "  ")); err != nil {
+		return nil, err
+	}
+
+	if err := api.uploadEnvironmentResources(ctx, bucket, env, pruneResources, w); err != nil {
+		return nil, err
+	}
+
+	return api.uploadEnvironmentLambdas(ctx, bucket, p, env, w)
+}
+
+// previewEnvironmentStack creates a change set for the environment stack and
+// writes the changes it contains, then deletes it without executing it
+func (api *environmentAPI) previewEnvironmentStack(ctx context.Context, bucket string, p *ecso.Project, env *ecso.Environment, version string, lambdaKeys map[string]string, overrides *StackOverrides, w io.Writer) error {
+	info := ui.NewInfoWriter(w)
+
+	// The change set needs the template in S3, but it is removed again once
+	// the change set has been created
+	defer func() {
+		s3Helper := helpers.NewS3Helper(api.s3API, env.Region)
+
+		if err := s3Helper.DeletePrefix(context.Background(), bucket, env.GetDryRunBucketPrefix(version)+"/", ioutil.Discard); err != nil {
+			fmt.Fprintf(w, "WARNING Failed to delete the dry run package. %s\n", err.Error())
 		}
+	}()
 
-		fmt.Fprintf(info, "\n%s", "The following changes were detected:")
-		fmt.Fprintf(w, "\n%s\n", resp)
+	result, err := api.deployEnvironmentStack(ctx, bucket, p, env, version, lambdaKeys, overrides, true, w)
+	if err != nil {
+		return err
 	}
 
+	if !result.DidRequireUpdating {
+		fmt.Fprintf(info, "\n%s", "No changes were detected")
+		return nil
+	}
+
+	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+
+	changes, describeErr := cfn.DescribeChangeSet(result.ChangeSetID)
+
+	// The change set was only created to preview changes, so delete it
+	// rather than leave it on the stack
+	if err := cfn.DeleteChangeSet(result.ChangeSetID); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to delete changeset %s. %s\n", result.ChangeSetID, err.Error())
+	}
+
+	if describeErr != nil {
+		return describeErr
+	}
+
+	fmt.Fprintf(info, "\n%s", "The following changes were detected:")
+	changes.WriteTo(ui.NewPrefixWriter(w, "  "))
+
 	return nil
 }
 
-func (api *environmentAPI) SendNotification(env *ecso.Environment, msg string) error {
+// SetEnvironmentProtection enables or disables termination protection on the
+// environment stack. Nothing is done if the environment has not been deployed
+func (api *environmentAPI) SetEnvironmentProtection(env *ecso.Environment, enabled bool) error {
+	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+
+	exists, err := cfn.StackExists(env.GetCloudFormationStackName())
+	if err != nil || !exists {
+		return err
+	}
+
+	return cfn.SetTerminationProtection(env.GetCloudFormationStackName(), enabled)
+}
+
+func (api *environmentAPI) GetAvailableVersions(ctx context.Context, env *ecso.Environment) (PackageVersionList, error) {
+	bucket, err := api.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := api.getCurrentVersion(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadPackageVersions(ctx, api.s3API, env.Region, bucket, env.GetVersionsBucketPrefix(), current)
+}
+
+func (api *environmentAPI) PruneVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, keep int, dryRun bool, w io.Writer) (PackageVersionList, error) {
+	bucket, err := api.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := api.getCurrentVersion(env)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := env.GetVersionsBucketPrefix()
+
+	versions, err := loadPackageVersions(ctx, api.s3API, env.Region, bucket, prefix, current)
+	if err != nil {
+		return nil, err
+	}
+
+	prunable := versions.Prunable(keep)
+
+	if !dryRun {
+		if err := deletePackageVersions(ctx, api.s3API, env.Region, bucket, prefix, prunable, w); err != nil {
+			return nil, err
+		}
+
+		if err := pruneNestedTemplates(ctx, api.s3API, env.Region, bucket, prefix, env.GetTemplatesBucketPrefix(), versions.Except(prunable), w); err != nil {
+			return nil, err
+		}
+
+		if len(prunable) > 0 {
+			if err := writeVersioningNote(ctx, api.s3API, bucket, w); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return prunable, nil
+}
+
+// getCurrentVersion returns the version of the environment that is currently
+// deployed, according to the version tag of the environment's cloudformation
+// stack. If the stack does not exist an empty string is returned
+func (api *environmentAPI) getCurrentVersion(env *ecso.Environment) (string, error) {
 	var (
 		stack = env.GetCloudFormationStackName()
+		cfn   = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
 	)
 
-	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+	exists, err := cfn.StackExists(stack)
+	if err != nil || !exists {
+		return "", err
+	}
 
-	outputs, err := cfn.GetStackOutputs(stack)
+	tags, err := cfn.GetStackTags(stack)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if topic, ok := outputs["NotificationsTopic"]; ok {
-		_, err := api.snsAPI.Publish(&sns.PublishInput{
-			Message: aws.String(msg),
-			MessageAttributes: map[string]*sns.MessageAttributeValue{
-				"Environment": {
-					DataType:    aws.String("String"),
-					StringValue: aws.String(env.Name),
-				},
-			},
-			TopicArn: aws.String(topic),
-		})
+	return tags["version"], nil
+}
 
+// Notify sends a deployment event to each of the environment's notifiers.
+// All notifiers are tried, and the failures of any that could not be
+// notified are returned together
+func (api *environmentAPI) Notify(ctx context.Context, env *ecso.Environment, event *helpers.DeploymentEvent) error {
+	notifiers, err := api.getNotifiers(env, event)
+	if err != nil {
 		return err
 	}
 
+	var failures []string
+
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, event); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
 	return nil
 }
 
-func (api *environmentAPI) deployEnvironmentStack(bucket string, project *ecso.Project, env *ecso.Environment, version string, dryRun bool, w io.Writer) (*helpers.DeploymentResult, error) {
+// getNotifiers returns the notifiers of the environment that accept the
+// event. Environments without any configured notifiers publish to the
+// NotificationsTopic output of their stack
+func (api *environmentAPI) getNotifiers(env *ecso.Environment, event *helpers.DeploymentEvent) ([]helpers.Notifier, error) {
+	var (
+		configs   = env.Notifiers
+		notifiers []helpers.Notifier
+	)
+
+	if len(configs) == 0 {
+		configs = []*ecso.NotifierConfiguration{{Type: ecso.NotifierTypeSNS}}
+	}
+
+	for _, config := range configs {
+		if !config.Accepts(string(event.Type)) {
+			continue
+		}
+
+		switch config.Type {
+		case ecso.NotifierTypeSNS:
+			topic := config.TopicArn
+
+			if topic == "" {
+				cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+
+				// The topic doesn't exist until the environment has been
+				// deployed for the first time
+				exists, err := cfn.StackExists(env.GetCloudFormationStackName())
+				if err != nil {
+					return nil, err
+				}
+
+				if !exists {
+					continue
+				}
+
+				outputs, err := cfn.GetStackOutputs(env.GetCloudFormationStackName())
+				if err != nil {
+					return nil, err
+				}
+
+				if topic = outputs["NotificationsTopic"]; topic == "" {
+					continue
+				}
+			}
+
+			notifiers = append(notifiers, helpers.NewSNSNotifier(api.snsAPI, topic))
+
+		case ecso.NotifierTypeWebhook:
+			notifiers = append(notifiers, helpers.NewWebhookNotifier(config.GetURL(), config.GetHeaders()))
+
+		case ecso.NotifierTypeSlack:
+			notifiers = append(notifiers, helpers.NewSlackNotifier(config.GetURL()))
+
+		default:
+			return nil, fmt.Errorf("Unknown notifier type '%s' in the %s environment", config.Type, env.Name)
+		}
+	}
+
+	return notifiers, nil
+}
+
+func (api *environmentAPI) deployEnvironmentStack(ctx context.Context, bucket string, project *ecso.Project, env *ecso.Environment, version string, lambdaKeys map[string]string, overrides *StackOverrides, dryRun bool, w io.Writer) (*helpers.DeploymentResult, error) {
 	var (
 		stackName = env.GetCloudFormationStackName()
 		prefix    = env.GetDeploymentBucketPrefix(version)
 		template  = env.GetCloudFormationTemplateFile()
-		tags      = env.CloudFormationTags
-		params    = env.CloudFormationParameters
+		tags      = make(map[string]string)
+		params    = make(map[string]string)
 		info      = ui.NewInfoWriter(w)
 	)
 
@@ -330,10 +702,18 @@ func (api *environmentAPI) deployEnvironmentStack(bucket string, project *ecso.P
 		api.s3API,
 		api.stsAPI)
 
-	if tags == nil {
-		tags = make(map[string]string)
+	// Copy the environment's params and tags, so that the values set below
+	// are not saved to project.json
+	for k, v := range env.CloudFormationTags {
+		tags[k] = v
+	}
+
+	for k, v := range env.CloudFormationParameters {
+		params[k] = v
 	}
 
+	overrides.Apply(params, tags)
+
 	tags["ecso-cli-version"] = project.EcsoVersion
 	tags["version"] = version
 
@@ -341,20 +721,146 @@ func (api *environmentAPI) deployEnvironmentStack(bucket string, project *ecso.P
 	params["Version"] = version
 	params["S3KeyPrefix"] = env.GetBaseBucketPrefix()
 
-	pkg, err := cfn.Package(template, bucket, prefix, tags, params, ui.NewPrefixWriter(w, "  "))
+	for k, v := range lambdaKeys {
+		params[k] = v
+	}
+
+	if err := setServiceDiscoveryParameter(env, template, params); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		prefix = env.GetDryRunBucketPrefix(version)
+	}
+
+	pkg, err := cfn.Package(ctx, template, bucket, prefix, env.GetTemplatesBucketPrefix(), tags, params, ui.NewPrefixWriter(w, "  "))
 	if err != nil {
 		return nil, err
 	}
 
-	return cfn.Deploy(pkg, stackName, dryRun, ui.NewPrefixWriter(w, "  "))
+	// A dry run is not a version of the environment, so it has no manifest
+	if dryRun {
+		return cfn.Deploy(ctx, pkg, stackName, true, ui.NewPrefixWriter(w, "  "))
+	}
+
+	manifest, err := createManifest(api.stsAPI, project, version)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "  Uploading deployment manifest to %s\n", pkg.GetManifestBucketKey())
+
+	if err := uploadManifest(ctx, api.s3API, env.Region, pkg, manifest, ui.NewPrefixWriter(w, "    ")); err != nil {
+		return nil, err
+	}
+
+	result, deployErr := cfn.Deploy(ctx, pkg, stackName, false, ui.NewPrefixWriter(w, "  "))
+
+	if deployErr != nil {
+		manifest.Status = helpers.ManifestStatusFailed
+	} else {
+		manifest.Status = helpers.ManifestStatusDeployed
+	}
+
+	if err := uploadManifest(ctx, api.s3API, env.Region, pkg, manifest, ui.NewPrefixWriter(w, "    ")); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to update deployment manifest status. %s\n", err.Error())
+	}
+
+	return result, deployErr
 }
 
-func (api *environmentAPI) uploadEnvironmentResources(bucket string, env *ecso.Environment, version string, w io.Writer) error {
+// uploadEnvironmentResources syncs the environment's resource dir to S3. Only
+// files that have changed since the last deployment are uploaded. If prune is
+// true, objects with no matching file in the resource dir are deleted
+func (api *environmentAPI) uploadEnvironmentResources(ctx context.Context, bucket string, env *ecso.Environment, prune bool, w io.Writer) error {
 	info := ui.NewInfoWriter(w)
 
-	fmt.Fprintf(info, "Uploading resources for the '%s' environment to S3", env.Name)
+	fmt.Fprintf(info, "Syncing resources for the '%s' environment to S3", env.Name)
 
 	s3Helper := helpers.NewS3Helper(api.s3API, env.Region)
 
-	return s3Helper.UploadDir(env.GetResourceDir(), bucket, env.GetResourceBucketPrefix(), ui.NewPrefixWriter(w, "  "))
+	result, err := s3Helper.SyncDir(ctx, env.GetResourceDir(), bucket, env.GetResourceBucketPrefix(), &helpers.SyncOptions{Delete: prune}, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "  Resources synced: %s\n", result)
+
+	return nil
+}
+
+// uploadEnvironmentLambdas packages each of the environment's lambda functions
+// and uploads them to S3. The S3 key of each bundle is returned, keyed by the
+// name of its stack parameter, for each parameter the stack template declares
+func (api *environmentAPI) uploadEnvironmentLambdas(ctx context.Context, bucket string, project *ecso.Project, env *ecso.Environment, w io.Writer) (map[string]string, error) {
+	info := ui.NewInfoWriter(w)
+
+	keys, objects, err := api.packageEnvironmentLambdas(project, env, w)
+	if err != nil || len(objects) == 0 {
+		return keys, err
+	}
+
+	fmt.Fprintf(info, "Uploading lambda functions for the '%s' environment to S3", env.Name)
+
+	// Bundles are named by their content, so only new bundles are uploaded
+	s3Helper := helpers.NewS3Helper(api.s3API, env.Region)
+
+	result, err := s3Helper.Sync(ctx, objects, bucket, nil, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "  Lambda functions synced: %s\n", result)
+
+	return keys, nil
+}
+
+// packageEnvironmentLambdas packages each of the environment's lambda
+// functions. The bundles are returned as objects to upload, along with the S3
+// key of each bundle keyed by the name of its stack parameter, for each
+// parameter the stack template declares
+func (api *environmentAPI) packageEnvironmentLambdas(project *ecso.Project, env *ecso.Environment, w io.Writer) (map[string]string, []*helpers.SyncObject, error) {
+	bundles, err := resources.PackageLambdas(filepath.Join(project.Dir(), resources.LambdaDir))
+	if err != nil || len(bundles) == 0 {
+		return nil, nil, err
+	}
+
+	template, err := ioutil.ReadFile(env.GetCloudFormationTemplateFile())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params, err := helpers.GetTemplateParameters(template)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	declared := make(map[string]bool)
+
+	for _, param := range params {
+		declared[param] = true
+	}
+
+	var (
+		keys    = make(map[string]string)
+		objects = make([]*helpers.SyncObject, 0, len(bundles))
+	)
+
+	for _, bundle := range bundles {
+		key := path.Join(env.GetLambdaBucketPrefix(), bundle.Filename)
+
+		objects = append(objects, &helpers.SyncObject{
+			Key:    key,
+			Body:   bundle.Body,
+			Source: bundle.Name,
+		})
+
+		if declared[bundle.ParameterName()] {
+			keys[bundle.ParameterName()] = key
+		} else {
+			fmt.Fprintf(w, "  WARNING The environment template does not declare a %s parameter, so the '%s' lambda function is not used\n", bundle.ParameterName(), bundle.Name)
+		}
+	}
+
+	return keys, objects, nil
 }
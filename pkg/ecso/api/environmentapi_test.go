@@ -64,3 +64,21 @@ func TestGetEcsoBucket(t *testing.T) {
 		t.Errorf("Want '%s', got '%s'.", expect, result)
 	}
 }
+
+func TestGetEcsoBucketWithOverride(t *testing.T) {
+	env := &ecso.Environment{
+		Region: "a-region",
+		Bucket: &ecso.BucketConfiguration{Name: "my-bucket"},
+	}
+
+	api := NewEnvironmentAPIWithMockAWSServices()
+
+	result, err := api.GetEcsoBucket(env)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if result != "my-bucket" {
+		t.Errorf("Want 'my-bucket', got '%s'.", result)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+const (
+	HistoryActionEnvironmentUp   = "environment up"
+	HistoryActionEnvironmentDown = "environment down"
+	HistoryActionServiceUp       = "service up"
+	HistoryActionServiceRollback = "service rollback"
+	HistoryActionServicePromote  = "service promote"
+	HistoryActionServiceDown     = "service down"
+)
+
+// HistoryRecordList is a list of history records. Sorting a HistoryRecordList
+// orders it from newest to oldest
+type HistoryRecordList []*helpers.HistoryRecord
+
+func (l HistoryRecordList) Len() int {
+	return len(l)
+}
+
+func (l HistoryRecordList) Less(i, j int) bool {
+	return l[i].Timestamp.After(l[j].Timestamp)
+}
+
+func (l HistoryRecordList) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+
+func (l HistoryRecordList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("TIME|ENVIRONMENT|SERVICE|ACTION|VERSION|ACTOR|COMMIT|DURATION|OUTCOME"))
+
+	for _, r := range l {
+		commit := ""
+
+		if r.Git != nil {
+			commit = shortCommit(r.Git)
+		}
+
+		tw.Write([]byte(fmt.Sprintf(
+			"%s|%s|%s|%s|%s|%s|%s|%s|%s",
+			r.Timestamp.Local().Format(time.RFC3339),
+			r.Environment,
+			r.Service,
+			r.Action,
+			r.Version,
+			r.Actor,
+			commit,
+			time.Duration(r.Duration)*time.Second,
+			r.Outcome)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+// historyEntry records the outcome of an operation in an environment's
+// history store once the operation finishes. A failure to record history
+// is written as a warning, and never fails the operation itself
+type historyEntry struct {
+	envAPI  EnvironmentAPI
+	env     *ecso.Environment
+	record  helpers.HistoryRecord
+	started time.Time
+	w       io.Writer
+}
+
+// startHistoryEntry begins recording an operation on an environment, or on
+// one of its services if service is not empty
+func startHistoryEntry(envAPI EnvironmentAPI, project *ecso.Project, env *ecso.Environment, service, action, version string, w io.Writer) *historyEntry {
+	h := &historyEntry{
+		envAPI: envAPI,
+		env:    env,
+		record: helpers.HistoryRecord{
+			Timestamp:   time.Now().UTC(),
+			Project:     project.Name,
+			Environment: env.Name,
+			Service:     service,
+			Action:      action,
+			Version:     version,
+			Git:         helpers.GetGitInfo(project.Dir()),
+		},
+		started: time.Now(),
+		w:       w,
+	}
+
+	// The actor is looked up before the operation starts, so that it is
+	// known even if the operation fails because of bad credentials
+	if actor, err := envAPI.GetCurrentAWSPrincipal(); err == nil {
+		h.record.Actor = actor
+	}
+
+	return h
+}
+
+// finish appends the record of the operation to the history store, and
+// returns err so that it can be used in return statements
+func (h *historyEntry) finish(err error) error {
+	record := h.record
+	record.Duration = time.Since(h.started).Round(time.Second).Seconds()
+	record.Outcome = helpers.HistoryOutcomeSucceeded
+
+	if err != nil {
+		record.Outcome = helpers.HistoryOutcomeFailed
+		record.Error = err.Error()
+	}
+
+	// The record is written even if the operation was cancelled, so it does
+	// not use the operation's context
+	store, storeErr := h.envAPI.GetHistoryStore(h.env)
+	if storeErr == nil {
+		storeErr = store.Append(context.Background(), &record)
+	}
+
+	if storeErr != nil {
+		fmt.Fprintf(h.w, "WARNING Failed to record %s in the deployment history. %s\n", record.Action, storeErr.Error())
+	}
+
+	return err
+}
+
+// historyAction returns the history action for a deployment event
+func historyAction(event *helpers.DeploymentEvent) string {
+	if event.Service == "" {
+		return HistoryActionEnvironmentUp
+	}
+
+	switch event.Action {
+	case helpers.DeploymentActionRollback:
+		return HistoryActionServiceRollback
+	case helpers.DeploymentActionPromote:
+		return HistoryActionServicePromote
+	default:
+		return HistoryActionServiceUp
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// lockActionServicePlan is the action recorded on the service lock while a
+// plan is created. Applying the plan is recorded as a service up
+const lockActionServicePlan = "service plan"
+
+// LockList is a list of locks held on environments and services
+type LockList []*helpers.Lock
+
+func (l LockList) WriteTo(w io.Writer) (int64, error) {
+	var (
+		tw  = ui.NewTableWriter(w, "|")
+		now = time.Now()
+	)
+
+	tw.WriteHeader([]byte("ENVIRONMENT|LOCK|HELD BY|ACTION|HOST|ACQUIRED|EXPIRES|STATUS"))
+
+	for _, lock := range l {
+		status := "held"
+
+		if lock.Expired(now) {
+			status = "expired"
+		}
+
+		tw.Write([]byte(fmt.Sprintf(
+			"%s|%s|%s|%s|%s|%s|%s|%s",
+			lock.Environment,
+			lock.Name,
+			lock.Holder,
+			lock.Action,
+			lock.Host,
+			lock.AcquiredAt.Local().Format(time.RFC3339),
+			lock.ExpiresAt.Local().Format(time.RFC3339),
+			status)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+// LockName returns the name of the lock on a service, or on the environment
+// itself if service is empty
+func LockName(service string) string {
+	if service == "" {
+		return "environment"
+	}
+
+	return "service/" + service
+}
+
+// acquireLock locks an environment, or one of its services if service is not
+// empty, so that no other ecso process can change it until the lock is
+// released. Changes made while holding the lock should use the lock's
+// context, which is cancelled if the lock is broken by another process
+func acquireLock(ctx context.Context, envAPI EnvironmentAPI, env *ecso.Environment, service, action string) (*helpers.HeldLock, error) {
+	store, err := envAPI.GetLockStore(env)
+	if err != nil {
+		return nil, err
+	}
+
+	holder, err := envAPI.GetCurrentAWSPrincipal()
+	if err != nil {
+		return nil, err
+	}
+
+	return lockEnvironmentOrService(ctx, store, env, service, holder, action)
+}
+
+// lockEnvironmentOrService takes the lock on an environment or one of its
+// services from store. As a change to the environment can affect all of its
+// services, a service can't be locked while the environment lock is held
+func lockEnvironmentOrService(ctx context.Context, store helpers.LockStore, env *ecso.Environment, service, holder, action string) (*helpers.HeldLock, error) {
+	lock, err := helpers.NewLocker(store).Acquire(ctx, LockName(service), env.Name, holder, action)
+	if err != nil || service == "" {
+		return lock, err
+	}
+
+	existing, err := store.Get(LockName(""))
+	if err == nil && existing != nil && !existing.Expired(time.Now()) {
+		err = &helpers.LockHeldError{Lock: existing}
+	}
+
+	if err != nil {
+		lock.Release()
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// releaseLock releases a lock acquired by acquireLock. Failing to release a
+// lock is only a warning, as the lock will expire on its own
+func releaseLock(lock *helpers.HeldLock, w io.Writer) {
+	if err := lock.Release(); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to release lock. %s\n", err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+func TestServiceLockFailsWhileEnvironmentIsLocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	var (
+		store = helpers.NewFileLockStore(dir)
+		env   = &ecso.Environment{Name: "prod"}
+	)
+
+	envLock, err := lockEnvironmentOrService(context.Background(), store, env, "", "arn:aws:iam::123456789012:user/alice", HistoryActionEnvironmentUp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = lockEnvironmentOrService(context.Background(), store, env, "web", "arn:aws:iam::123456789012:user/bob", HistoryActionServiceUp)
+	if !helpers.IsLockHeldError(err) {
+		t.Fatalf("Want LockHeldError, got %v", err)
+	}
+
+	if name := err.(*helpers.LockHeldError).Lock.Name; name != LockName("") {
+		t.Errorf("Want the environment lock to be reported, got %s", name)
+	}
+
+	if l, _ := store.Get(LockName("web")); l != nil {
+		t.Errorf("Want the service lock to be released when the environment is locked")
+	}
+
+	if err := envLock.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	serviceLock, err := lockEnvironmentOrService(context.Background(), store, env, "web", "arn:aws:iam::123456789012:user/bob", HistoryActionServiceUp)
+	if err != nil {
+		t.Fatalf("Unexpected error locking service: %s", err)
+	}
+
+	serviceLock.Release()
+}
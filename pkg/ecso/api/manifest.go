@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+// createManifest creates a pending manifest for a new deployment package,
+// recording the IAM principal that is deploying, and the state of the
+// project's git working copy
+func createManifest(stsAPI stsiface.STSAPI, project *ecso.Project, version string) (*helpers.Manifest, error) {
+	resp, err := stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := helpers.NewManifest(version, *resp.Arn, time.Now())
+	manifest.Git = helpers.GetGitInfo(project.Dir())
+
+	return manifest, nil
+}
+
+func uploadManifest(ctx context.Context, s3API s3iface.S3API, region string, pkg *helpers.Package, manifest *helpers.Manifest, w io.Writer) error {
+	s3Helper := helpers.NewS3Helper(s3API, region)
+
+	return s3Helper.UploadObjectJSON(ctx, manifest, pkg.GetBucket(), pkg.GetManifestBucketKey(), w)
+}
+
+// downloadManifest fetches the manifest for a package. Packages created by
+// earlier versions of ecso have no manifest, in which case nil is returned
+func downloadManifest(ctx context.Context, s3API s3iface.S3API, region string, pkg *helpers.Package) (*helpers.Manifest, error) {
+	var (
+		s3Helper = helpers.NewS3Helper(s3API, region)
+		manifest = &helpers.Manifest{}
+	)
+
+	if err := s3Helper.DownloadObjectJSON(ctx, manifest, pkg.GetBucket(), pkg.GetManifestBucketKey()); err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchKey" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return manifest, nil
+}
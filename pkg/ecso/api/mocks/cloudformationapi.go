@@ -3,6 +3,8 @@ package mocks
 import (
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 )
@@ -10,7 +12,8 @@ import (
 type CloudFormationAPIMock struct {
 	cloudformationiface.CloudFormationAPI
 
-	describeStacks func(*cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
+	describeStacks      func(*cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
+	describeStackEvents map[string]*cloudformation.DescribeStackEventsOutput
 }
 
 func (mock *CloudFormationAPIMock) DescribeStacksReturns(output *cloudformation.DescribeStacksOutput, err error) {
@@ -25,3 +28,31 @@ func (mock *CloudFormationAPIMock) DescribeStacks(input *cloudformation.Describe
 	}
 	return nil, fmt.Errorf("Not implemented")
 }
+
+func (mock *CloudFormationAPIMock) DescribeStacksWithContext(ctx aws.Context, input *cloudformation.DescribeStacksInput, opts ...request.Option) (*cloudformation.DescribeStacksOutput, error) {
+	return mock.DescribeStacks(input)
+}
+
+// DescribeStackEventsReturns sets the events returned for the named stack
+func (mock *CloudFormationAPIMock) DescribeStackEventsReturns(stackName string, output *cloudformation.DescribeStackEventsOutput) {
+	if mock.describeStackEvents == nil {
+		mock.describeStackEvents = make(map[string]*cloudformation.DescribeStackEventsOutput)
+	}
+	mock.describeStackEvents[stackName] = output
+}
+
+func (mock *CloudFormationAPIMock) DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	if output, ok := mock.describeStackEvents[*input.StackName]; ok {
+		return output, nil
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *CloudFormationAPIMock) DescribeStackEventsPages(input *cloudformation.DescribeStackEventsInput, fn func(*cloudformation.DescribeStackEventsOutput, bool) bool) error {
+	output, err := mock.DescribeStackEvents(input)
+	if err != nil {
+		return err
+	}
+	fn(output, true)
+	return nil
+}
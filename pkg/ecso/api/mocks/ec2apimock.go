@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+type EC2APIMock struct {
+	ec2iface.EC2API
+
+	describeInstances func(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+}
+
+func (mock *EC2APIMock) DescribeInstancesReturns(output *ec2.DescribeInstancesOutput, err error) {
+	mock.describeInstances = func(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *EC2APIMock) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	if mock.describeInstances != nil {
+		return mock.describeInstances(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *EC2APIMock) DescribeInstancesPagesWithContext(ctx aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, opts ...request.Option) error {
+	output, err := mock.DescribeInstances(input)
+	if err != nil {
+		return err
+	}
+
+	fn(output, true)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package mocks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+)
+
+type ECRAPIMock struct {
+	ecriface.ECRAPI
+
+	batchGetImage         func(*ecr.BatchGetImageInput) (*ecr.BatchGetImageOutput, error)
+	createRepository      func(*ecr.CreateRepositoryInput) (*ecr.CreateRepositoryOutput, error)
+	describeImages        func(*ecr.DescribeImagesInput) (*ecr.DescribeImagesOutput, error)
+	describeRepositories  func(*ecr.DescribeRepositoriesInput) (*ecr.DescribeRepositoriesOutput, error)
+	getAuthorizationToken func(*ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error)
+	putLifecyclePolicy    func(*ecr.PutLifecyclePolicyInput) (*ecr.PutLifecyclePolicyOutput, error)
+}
+
+func (mock *ECRAPIMock) BatchGetImageReturns(output *ecr.BatchGetImageOutput, err error) {
+	mock.batchGetImage = func(input *ecr.BatchGetImageInput) (*ecr.BatchGetImageOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) BatchGetImage(input *ecr.BatchGetImageInput) (*ecr.BatchGetImageOutput, error) {
+	if mock.batchGetImage != nil {
+		return mock.batchGetImage(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECRAPIMock) BatchGetImageWithContext(ctx aws.Context, input *ecr.BatchGetImageInput, opts ...request.Option) (*ecr.BatchGetImageOutput, error) {
+	return mock.BatchGetImage(input)
+}
+
+func (mock *ECRAPIMock) CreateRepositoryReturns(output *ecr.CreateRepositoryOutput, err error) {
+	mock.createRepository = func(input *ecr.CreateRepositoryInput) (*ecr.CreateRepositoryOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) CreateRepository(input *ecr.CreateRepositoryInput) (*ecr.CreateRepositoryOutput, error) {
+	if mock.createRepository != nil {
+		return mock.createRepository(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECRAPIMock) DescribeImagesReturns(output *ecr.DescribeImagesOutput, err error) {
+	mock.describeImages = func(input *ecr.DescribeImagesInput) (*ecr.DescribeImagesOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) DescribeImages(input *ecr.DescribeImagesInput) (*ecr.DescribeImagesOutput, error) {
+	if mock.describeImages != nil {
+		return mock.describeImages(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECRAPIMock) DescribeRepositoriesReturns(output *ecr.DescribeRepositoriesOutput, err error) {
+	mock.describeRepositories = func(input *ecr.DescribeRepositoriesInput) (*ecr.DescribeRepositoriesOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) DescribeRepositories(input *ecr.DescribeRepositoriesInput) (*ecr.DescribeRepositoriesOutput, error) {
+	if mock.describeRepositories != nil {
+		return mock.describeRepositories(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECRAPIMock) GetAuthorizationTokenReturns(output *ecr.GetAuthorizationTokenOutput, err error) {
+	mock.getAuthorizationToken = func(input *ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) GetAuthorizationToken(input *ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error) {
+	if mock.getAuthorizationToken != nil {
+		return mock.getAuthorizationToken(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECRAPIMock) PutLifecyclePolicyReturns(output *ecr.PutLifecyclePolicyOutput, err error) {
+	mock.putLifecyclePolicy = func(input *ecr.PutLifecyclePolicyInput) (*ecr.PutLifecyclePolicyOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECRAPIMock) PutLifecyclePolicy(input *ecr.PutLifecyclePolicyInput) (*ecr.PutLifecyclePolicyOutput, error) {
+	if mock.putLifecyclePolicy != nil {
+		return mock.putLifecyclePolicy(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
@@ -1,7 +1,51 @@
 package mocks
 
-import "github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
 
 type ECSAPIMock struct {
 	ecsiface.ECSAPI
+
+	describeServices           func(*ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error)
+	describeContainerInstances func(*ecs.DescribeContainerInstancesInput) (*ecs.DescribeContainerInstancesOutput, error)
+}
+
+func (mock *ECSAPIMock) DescribeServicesReturns(output *ecs.DescribeServicesOutput, err error) {
+	mock.describeServices = func(input *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECSAPIMock) DescribeServices(input *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+	if mock.describeServices != nil {
+		return mock.describeServices(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECSAPIMock) DescribeServicesWithContext(ctx aws.Context, input *ecs.DescribeServicesInput, opts ...request.Option) (*ecs.DescribeServicesOutput, error) {
+	return mock.DescribeServices(input)
+}
+
+func (mock *ECSAPIMock) DescribeContainerInstancesReturns(output *ecs.DescribeContainerInstancesOutput, err error) {
+	mock.describeContainerInstances = func(input *ecs.DescribeContainerInstancesInput) (*ecs.DescribeContainerInstancesOutput, error) {
+		return output, err
+	}
+}
+
+func (mock *ECSAPIMock) DescribeContainerInstances(input *ecs.DescribeContainerInstancesInput) (*ecs.DescribeContainerInstancesOutput, error) {
+	if mock.describeContainerInstances != nil {
+		return mock.describeContainerInstances(input)
+	}
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (mock *ECSAPIMock) DescribeContainerInstancesWithContext(ctx aws.Context, input *ecs.DescribeContainerInstancesInput, opts ...request.Option) (*ecs.DescribeContainerInstancesOutput, error) {
+	return mock.DescribeContainerInstances(input)
 }
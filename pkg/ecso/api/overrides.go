@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+var (
+	// Parameters and tags that ecso sets itself, and which cannot be
+	// overridden from the command line
+	reservedEnvironmentParams = []string{"S3BucketName", "S3KeyPrefix", "ServiceDiscovery", "Version"}
+	reservedEnvironmentTags   = []string{"ecso-cli-version", "version"}
+	reservedServiceParams     = []string{"ServiceDiscoveryNamespace", "TaskDefinition", "Version"}
+	reservedServiceTags       = []string{"ecso-cli-version", "environment", "project", "version"}
+)
+
+// StackOverrides are cloudformation parameters and tags given on the command
+// line for a single deployment. They take precedence over the parameters and
+// tags configured in project.json, which in turn take precedence over the
+// defaults that ecso calculates
+type StackOverrides struct {
+	Params map[string]string
+	Tags   map[string]string
+}
+
+// IsEmpty returns true if there is nothing to override
+func (o *StackOverrides) IsEmpty() bool {
+	return o == nil || (len(o.Params) == 0 && len(o.Tags) == 0)
+}
+
+// Apply copies the overrides over params and tags
+func (o *StackOverrides) Apply(params, tags map[string]string) {
+	if o == nil {
+		return
+	}
+
+	for k, v := range o.Params {
+		params[k] = v
+	}
+
+	for k, v := range o.Tags {
+		tags[k] = v
+	}
+}
+
+// validate ensures the overrides do not change any of the reserved
+// parameters or tags, and that each parameter is declared by the template
+func (o *StackOverrides) validate(template []byte, reservedParams, reservedTags []string) error {
+	if o.IsEmpty() {
+		return nil
+	}
+
+	if err := checkReserved("parameter", o.Params, reservedParams); err != nil {
+		return err
+	}
+
+	if err := checkReserved("tag", o.Tags, reservedTags); err != nil {
+		return err
+	}
+
+	return helpers.ValidateParameters(template, o.Params)
+}
+
+// validateServiceOverrides validates overrides against the service's
+// cloudformation template
+func validateServiceOverrides(service *ecso.Service, overrides *StackOverrides) error {
+	if overrides.IsEmpty() {
+		return nil
+	}
+
+	template, err := ioutil.ReadFile(service.GetCloudFormationTemplateFile())
+	if err != nil {
+		return err
+	}
+
+	return overrides.validate(template, reservedServiceParams, reservedServiceTags)
+}
+
+func checkReserved(kind string, values map[string]string, reserved []string) error {
+	found := make([]string, 0)
+
+	for _, key := range reserved {
+		if _, ok := values[key]; ok {
+			found = append(found, key)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	sort.Strings(found)
+
+	return fmt.Errorf("The %s(s) %s are set by ecso and cannot be overridden", kind, strings.Join(found, ", "))
+}
+
+// repackageWithOverrides copies a package that has already been uploaded to
+// s3 to a new package below it, with the overrides merged over its params and
+// tags. The original package is left unchanged, and the merged values are
+// recorded in the new package
+func repackageWithOverrides(ctx context.Context, cfn helpers.CloudFormationHelper, s3API s3iface.S3API, region string, pkg *helpers.Package, overrides *StackOverrides, w io.Writer) (*helpers.Package, error) {
+	var (
+		s3Helper = helpers.NewS3Helper(s3API, region)
+		params   = make(map[string]string)
+		tags     = make(map[string]string)
+		prefix   = path.Join(pkg.GetBucketPrefix(), "overrides", time.Now().UTC().Format("20060102T150405Z"))
+	)
+
+	template, err := cfn.GetPackageTemplate(ctx, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := overrides.validate(template, reservedServiceParams, reservedServiceTags); err != nil {
+		return nil, err
+	}
+
+	if err := s3Helper.DownloadObjectJSON(ctx, &params, pkg.GetBucket(), pkg.GetParamsBucketKey()); err != nil {
+		return nil, err
+	}
+
+	if err := s3Helper.DownloadObjectJSON(ctx, &tags, pkg.GetBucket(), pkg.GetTagsBucketKey()); err != nil {
+		return nil, err
+	}
+
+	overrides.Apply(params, tags)
+
+	return cfn.CopyPackage(ctx, pkg, prefix, tags, params, w)
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+func TestStackOverridesApply(t *testing.T) {
+	params := map[string]string{"DesiredCount": "1", "Cluster": "dev"}
+	tags := map[string]string{"team": "a"}
+
+	overrides := &StackOverrides{
+		Params: map[string]string{"DesiredCount": "3"},
+		Tags:   map[string]string{"cost-centre": "123"},
+	}
+
+	overrides.Apply(params, tags)
+
+	if want := map[string]string{"DesiredCount": "3", "Cluster": "dev"}; !reflect.DeepEqual(params, want) {
+		t.Errorf("Want params %v, got %v", want, params)
+	}
+
+	if want := map[string]string{"team": "a", "cost-centre": "123"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Want tags %v, got %v", want, tags)
+	}
+
+	var none *StackOverrides
+
+	if !none.IsEmpty() {
+		t.Errorf("Expected nil overrides to be empty")
+	}
+
+	none.Apply(params, tags)
+}
+
+func TestStackOverridesValidate(t *testing.T) {
+	template := []byte("Parameters:\n  DesiredCount:\n    Type: Number\n  Version:\n    Type: String\n")
+
+	tests := []struct {
+		overrides *StackOverrides
+		valid     bool
+	}{
+		{nil, true},
+		{&StackOverrides{Params: map[string]string{"DesiredCount": "2"}}, true},
+		{&StackOverrides{Params: map[string]string{"Unknown": "2"}}, false},
+		{&StackOverrides{Params: map[string]string{"Version": "1.0.0"}}, false},
+		{&StackOverrides{Tags: map[string]string{"version": "1.0.0"}}, false},
+		{&StackOverrides{Tags: map[string]string{"owner": "me"}}, true},
+	}
+
+	for i, test := range tests {
+		err := test.overrides.validate(template, reservedServiceParams, reservedServiceTags)
+
+		if test.valid && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i, err)
+		}
+
+		if !test.valid && err == nil {
+			t.Errorf("Test %d: expected an error", i)
+		}
+	}
+}
+
+type overridesS3Mock struct {
+	s3iface.S3API
+
+	objects map[string][]byte
+}
+
+func (m *overridesS3Mock) HeadBucketWithContext(aws.Context, *s3.HeadBucketInput, ...request.Option) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (m *overridesS3Mock) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	body, ok := m.objects[*input.Key]
+	if !ok {
+		return nil, awserr.New("NoSuchKey", "not found", nil)
+	}
+
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+}
+
+// PutObjectRequest returns a request that stores the object in the mock when
+// it is sent, as the s3manager uploader sends single part uploads this way
+func (m *overridesS3Mock) PutObjectRequest(input *s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput) {
+	output := &s3.PutObjectOutput{}
+	req := request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil, &request.Operation{Name: "PutObject"}, input, output)
+
+	req.Handlers.Send.PushBack(func(r *request.Request) {
+		body, err := ioutil.ReadAll(input.Body)
+		if err != nil {
+			r.Error = err
+			return
+		}
+
+		m.objects[*input.Key] = body
+	})
+
+	return req, output
+}
+
+func (m *overridesS3Mock) CopyObjectWithContext(ctx aws.Context, input *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	source := strings.TrimPrefix(*input.CopySource, *input.Bucket+"/")
+
+	body, ok := m.objects[source]
+	if !ok {
+		return nil, awserr.New("NoSuchKey", "not found", nil)
+	}
+
+	m.objects[*input.Key] = body
+
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func TestRepackageWithOverrides(t *testing.T) {
+	var (
+		pkg    = helpers.NewPackage("bucket", "services/api/v1", "test-region")
+		params = map[string]string{"DesiredCount": "1", "Version": "v1"}
+		tags   = map[string]string{"version": "v1"}
+		mock   = &overridesS3Mock{objects: make(map[string][]byte)}
+		cfn    = helpers.NewCloudFormationHelper("test-region", nil, mock, nil)
+	)
+
+	mock.objects[pkg.GetTemplateBucketKey()] = []byte("Parameters:\n  DesiredCount:\n    Type: Number\n  Version:\n    Type: String\n")
+
+	for key, o := range map[string]interface{}{pkg.GetParamsBucketKey(): params, pkg.GetTagsBucketKey(): tags} {
+		b, _ := json.Marshal(o)
+		mock.objects[key] = b
+	}
+
+	overrides := &StackOverrides{
+		Params: map[string]string{"DesiredCount": "3"},
+		Tags:   map[string]string{"owner": "me"},
+	}
+
+	copied, err := repackageWithOverrides(context.Background(), cfn, mock, "test-region", pkg, overrides, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(copied.GetBucketPrefix(), pkg.GetBucketPrefix()+"/overrides/") {
+		t.Errorf("Want the copy below %s, got %s", pkg.GetBucketPrefix(), copied.GetBucketPrefix())
+	}
+
+	if !bytes.Equal(mock.objects[copied.GetTemplateBucketKey()], mock.objects[pkg.GetTemplateBucketKey()]) {
+		t.Errorf("Expected the template to be copied to %s", copied.GetTemplateBucketKey())
+	}
+
+	for key, want := range map[string]map[string]string{
+		copied.GetParamsBucketKey(): {"DesiredCount": "3", "Version": "v1"},
+		copied.GetTagsBucketKey():   {"version": "v1", "owner": "me"},
+		pkg.GetParamsBucketKey():    params,
+		pkg.GetTagsBucketKey():      tags,
+	} {
+		got := make(map[string]string)
+
+		if err := json.Unmarshal(mock.objects[key], &got); err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Want %v at %s, got %v", want, key, got)
+		}
+	}
+
+	invalid := &StackOverrides{Params: map[string]string{"Version": "v2"}}
+
+	if _, err := repackageWithOverrides(context.Background(), cfn, mock, "test-region", pkg, invalid, ioutil.Discard); err == nil {
+		t.Errorf("Expected overriding a reserved parameter to fail")
+	}
+}
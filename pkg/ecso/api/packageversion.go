@@ -0,0 +1,401 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// PackageVersion is a deployment package that has been uploaded to the ecso
+// bucket
+type PackageVersion struct {
+	Label     string
+	Timestamp time.Time
+	Current   bool
+	Manifest  *helpers.Manifest `json:",omitempty" yaml:",omitempty"`
+
+	// Abandoned is true for planned versions that were never applied, and
+	// that have been superseded by the current version
+	Abandoned bool `json:",omitempty" yaml:",omitempty"`
+}
+
+// Status returns the deployment status recorded in the version's manifest
+func (v *PackageVersion) Status() string {
+	if v.Manifest == nil {
+		return "unknown"
+	}
+
+	if v.Abandoned {
+		return helpers.ManifestStatusAbandoned
+	}
+
+	return v.Manifest.Status
+}
+
+// Planned returns true if the version was created by a plan that has not
+// been applied
+func (v *PackageVersion) Planned() bool {
+	return v.Manifest != nil && v.Manifest.Status == helpers.ManifestStatusPlanned
+}
+
+// Failed returns true if deploying the version failed
+func (v *PackageVersion) Failed() bool {
+	return v.Status() == helpers.ManifestStatusFailed
+}
+
+func (v *PackageVersion) row() string {
+	var (
+		deployedBy = ""
+		commit     = ""
+		current    = ""
+	)
+
+	if v.Manifest != nil {
+		deployedBy = v.Manifest.DeployedBy
+
+		if v.Manifest.Git != nil {
+			commit = shortCommit(v.Manifest.Git)
+		}
+	}
+
+	if v.Current {
+		current = "*"
+	}
+
+	return fmt.Sprintf(
+		"%s|%s|%s|%s|%s|%s",
+		v.Label,
+		v.Timestamp.Format(time.RFC3339),
+		deployedBy,
+		commit,
+		v.Status(),
+		current)
+}
+
+// PackageVersionList is a list of deployment package versions. Sorting a
+// PackageVersionList orders it from newest to oldest
+type PackageVersionList []*PackageVersion
+
+func (l PackageVersionList) Len() int {
+	return len(l)
+}
+
+func (l PackageVersionList) Less(i, j int) bool {
+	if l[i].Timestamp.Equal(l[j].Timestamp) {
+		return l[i].Label > l[j].Label
+	}
+
+	return l[i].Timestamp.After(l[j].Timestamp)
+}
+
+func (l PackageVersionList) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+
+func (l PackageVersionList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("VERSION|CREATED|DEPLOYED BY|COMMIT|STATUS|CURRENT"))
+
+	for _, v := range l {
+		tw.Write([]byte(v.row()))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+// Except returns the versions in the list that are not in other
+func (l PackageVersionList) Except(other PackageVersionList) PackageVersionList {
+	var (
+		result  = make(PackageVersionList, 0)
+		exclude = make(map[string]bool)
+	)
+
+	for _, v := range other {
+		exclude[v.Label] = true
+	}
+
+	for _, v := range l {
+		if !exclude[v.Label] {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// Prunable returns the versions in the list that fall outside a retention
+// policy keeping the newest keep versions. The currently deployed version,
+// and the successfully deployed version before it, are never returned so
+// that a rollback is always possible. Plans that are yet to be applied are
+// neither returned nor counted towards keep, while abandoned plans are
+// always returned. The list must be sorted
+func (l PackageVersionList) Prunable(keep int) PackageVersionList {
+	var (
+		result    = make(PackageVersionList, 0)
+		protected = make(map[string]bool)
+		current   = -1
+		kept      = 0
+	)
+
+	for i, v := range l {
+		if v.Current {
+			current = i
+			protected[v.Label] = true
+			break
+		}
+	}
+
+	if current >= 0 {
+		for _, v := range l[current+1:] {
+			if !v.Failed() {
+				protected[v.Label] = true
+				break
+			}
+		}
+	}
+
+	for _, v := range l {
+		switch {
+		case v.Abandoned:
+			result = append(result, v)
+		case v.Planned():
+			continue
+		case kept >= keep && !protected[v.Label]:
+			result = append(result, v)
+		default:
+			kept++
+		}
+	}
+
+	return result
+}
+
+// markAbandonedPlans marks the planned versions that are older than the
+// current version as abandoned. A plan can only be applied to the stack it
+// was created against, so once a later version is deployed it is dead. The
+// list must be sorted
+func (l PackageVersionList) markAbandonedPlans() {
+	superseded := false
+
+	for _, v := range l {
+		if v.Current {
+			superseded = true
+		}
+
+		if superseded && v.Planned() {
+			v.Abandoned = true
+		}
+	}
+}
+
+func shortCommit(git *helpers.GitInfo) string {
+	commit := git.Commit
+
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	if git.Dirty {
+		commit = commit + "-dirty"
+	}
+
+	return commit
+}
+
+// loadPackageVersions finds all deployment packages stored under prefix in
+// the bucket, along with their manifests. The version matching current is
+// marked as the current version. The returned list is sorted newest first
+func loadPackageVersions(ctx context.Context, s3API s3iface.S3API, region, bucket, prefix, current string) (PackageVersionList, error) {
+	s3Helper := helpers.NewS3Helper(s3API, region)
+
+	objects, err := s3Helper.ListObjects(ctx, bucket, prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(PackageVersionList, 0)
+	labels := make(map[string]time.Time)
+
+	for _, o := range objects {
+		suffix := strings.TrimPrefix(*o.Key, prefix+"/")
+		tokens := strings.Split(suffix, "/")
+
+		if _, ok := labels[tokens[0]]; !ok || (o.LastModified != nil && o.LastModified.After(labels[tokens[0]])) {
+			labels[tokens[0]] = timeValue(o.LastModified)
+		}
+	}
+
+	for label, lastModified := range labels {
+		pkg := helpers.NewPackage(bucket, path.Join(prefix, label), region)
+
+		manifest, err := downloadManifest(ctx, s3API, region, pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		version := &PackageVersion{
+			Label:     label,
+			Timestamp: lastModified,
+			Current:   label == current,
+			Manifest:  manifest,
+		}
+
+		if manifest != nil {
+			version.Timestamp = manifest.Timestamp
+		}
+
+		versions = append(versions, version)
+	}
+
+	sort.Sort(versions)
+	versions.markAbandonedPlans()
+
+	return versions, nil
+}
+
+// deletePackageVersions removes the deployment packages for each version
+// stored under prefix in the bucket
+func deletePackageVersions(ctx context.Context, s3API s3iface.S3API, region, bucket, prefix string, versions PackageVersionList, w io.Writer) error {
+	s3Helper := helpers.NewS3Helper(s3API, region)
+
+	for _, v := range versions {
+		if err := s3Helper.DeletePrefix(ctx, bucket, path.Join(prefix, v.Label)+"/", w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeVersioningNote explains that deleted objects are not removed from a
+// versioned bucket straight away, as s3 keeps their earlier versions
+func writeVersioningNote(ctx context.Context, s3API s3iface.S3API, bucket string, w io.Writer) error {
+	resp, err := s3API.GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if aws.StringValue(resp.Status) == s3.BucketVersioningStatusEnabled {
+		fmt.Fprintf(w, "Versioning is enabled on bucket '%s', so deleted objects are kept as noncurrent versions until the bucket's lifecycle rule expires them\n", bucket)
+	}
+
+	return nil
+}
+
+// nestedTemplateGracePeriod is how long an unreferenced nested template is
+// kept for, as it may belong to a package that is still being created
+const nestedTemplateGracePeriod = time.Hour
+
+// pruneNestedTemplates deletes the nested templates stored under
+// templatesPrefix that are not referenced by the root template of any of the
+// retained versions stored under prefix
+func pruneNestedTemplates(ctx context.Context, s3API s3iface.S3API, region, bucket, prefix, templatesPrefix string, retained PackageVersionList, w io.Writer) error {
+	var (
+		s3Helper   = helpers.NewS3Helper(s3API, region)
+		referenced = make(map[string]bool)
+	)
+
+	for _, v := range retained {
+		pkg := helpers.NewPackage(bucket, path.Join(prefix, v.Label), region)
+
+		body, err := downloadPackageTemplate(ctx, s3API, pkg)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range helpers.GetNestedTemplateKeys(string(body), bucket) {
+			referenced[path.Dir(key)] = true
+		}
+	}
+
+	objects, err := s3Helper.ListObjects(ctx, bucket, templatesPrefix+"/")
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range unusedNestedTemplates(objects, referenced, time.Now()) {
+		if err := s3Helper.DeletePrefix(ctx, bucket, dir+"/", w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unusedNestedTemplates returns the directories holding nested templates
+// that are not referenced, and that have not been uploaded to within the
+// grace period. Each directory holds the templates for a single hash
+func unusedNestedTemplates(objects []*s3.Object, referenced map[string]bool, now time.Time) []string {
+	var (
+		dirs   = make([]string, 0)
+		newest = make(map[string]time.Time)
+	)
+
+	for _, o := range objects {
+		dir := path.Dir(aws.StringValue(o.Key))
+
+		if _, ok := newest[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+
+		if t := timeValue(o.LastModified); t.After(newest[dir]) {
+			newest[dir] = t
+		}
+	}
+
+	unused := make([]string, 0)
+
+	for _, dir := range dirs {
+		if !referenced[dir] && now.Sub(newest[dir]) > nestedTemplateGracePeriod {
+			unused = append(unused, dir)
+		}
+	}
+
+	return unused
+}
+
+// downloadPackageTemplate downloads the root template of a package, or
+// returns nil if the package has no template
+func downloadPackageTemplate(ctx context.Context, s3API s3iface.S3API, pkg *helpers.Package) ([]byte, error) {
+	resp, err := s3API.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(pkg.GetBucket()),
+		Key:    aws.String(pkg.GetTemplateBucketKey()),
+	})
+
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchKey" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
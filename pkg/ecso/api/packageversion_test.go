@@ -0,0 +1,153 @@
+package api
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+func TestPackageVersionListSort(t *testing.T) {
+	now := time.Now()
+
+	versions := PackageVersionList{
+		{Label: "b", Timestamp: now.Add(-time.Hour)},
+		{Label: "c", Timestamp: now},
+		{Label: "a", Timestamp: now.Add(-time.Hour * 2)},
+	}
+
+	sort.Sort(versions)
+
+	for i, want := range []string{"c", "b", "a"} {
+		if versions[i].Label != want {
+			t.Errorf("Want version %s at position %d, got %s", want, i, versions[i].Label)
+		}
+	}
+}
+
+func TestPackageVersionListPrunable(t *testing.T) {
+	var (
+		deployed = &helpers.Manifest{Status: helpers.ManifestStatusDeployed}
+		failed   = &helpers.Manifest{Status: helpers.ManifestStatusFailed}
+	)
+
+	makeVersions := func(current string) PackageVersionList {
+		versions := PackageVersionList{
+			{Label: "v6", Manifest: failed},
+			{Label: "v5", Manifest: deployed},
+			{Label: "v4", Manifest: failed},
+			{Label: "v3", Manifest: deployed},
+			{Label: "v2", Manifest: deployed},
+			{Label: "v1", Manifest: deployed},
+		}
+
+		for _, v := range versions {
+			v.Current = v.Label == current
+		}
+
+		return versions
+	}
+
+	cases := []struct {
+		name    string
+		current string
+		keep    int
+		want    []string
+	}{
+		{"current is newest", "v5", 2, []string{"v4", "v2", "v1"}},
+		{"rollback to old version", "v2", 1, []string{"v5", "v4", "v3"}},
+		{"keep everything", "v5", 10, []string{}},
+		{"no current version", "", 3, []string{"v3", "v2", "v1"}},
+	}
+
+	for _, c := range cases {
+		prunable := makeVersions(c.current).Prunable(c.keep)
+
+		if len(prunable) != len(c.want) {
+			t.Errorf("%s: want %d prunable versions, got %d", c.name, len(c.want), len(prunable))
+			continue
+		}
+
+		for i, want := range c.want {
+			if prunable[i].Label != want {
+				t.Errorf("%s: want %s at position %d, got %s", c.name, want, i, prunable[i].Label)
+			}
+		}
+	}
+}
+
+func TestPackageVersionListPrunablePlans(t *testing.T) {
+	var (
+		deployed = &helpers.Manifest{Status: helpers.ManifestStatusDeployed}
+		planned  = &helpers.Manifest{Status: helpers.ManifestStatusPlanned}
+	)
+
+	versions := PackageVersionList{
+		{Label: "v5", Manifest: planned},
+		{Label: "v4", Manifest: deployed, Current: true},
+		{Label: "v3", Manifest: planned},
+		{Label: "v2", Manifest: deployed},
+		{Label: "v1", Manifest: deployed},
+	}
+
+	versions.markAbandonedPlans()
+
+	if versions[0].Abandoned || !versions[2].Abandoned {
+		t.Errorf("Want only plans older than the current version to be abandoned")
+	}
+
+	if status := versions[2].Status(); status != helpers.ManifestStatusAbandoned {
+		t.Errorf("Want status %s, got %s", helpers.ManifestStatusAbandoned, status)
+	}
+
+	prunable := versions.Prunable(2)
+
+	for i, want := range []string{"v3", "v1"} {
+		if i >= len(prunable) || prunable[i].Label != want {
+			t.Fatalf("Want %s at position %d of %v", want, i, prunable)
+		}
+	}
+
+	if len(prunable) != 2 {
+		t.Errorf("Want 2 prunable versions, got %d", len(prunable))
+	}
+}
+
+func TestPackageVersionListExcept(t *testing.T) {
+	versions := PackageVersionList{{Label: "v3"}, {Label: "v2"}, {Label: "v1"}}
+
+	retained := versions.Except(PackageVersionList{{Label: "v2"}})
+
+	if len(retained) != 2 || retained[0].Label != "v3" || retained[1].Label != "v1" {
+		t.Errorf("Want v3 and v1 to be retained, got %v", retained)
+	}
+}
+
+func TestUnusedNestedTemplates(t *testing.T) {
+	var (
+		now    = time.Now()
+		old    = now.Add(-nestedTemplateGracePeriod * 2)
+		object = func(key string, modified time.Time) *s3.Object {
+			return &s3.Object{Key: aws.String(key), LastModified: aws.Time(modified)}
+		}
+	)
+
+	objects := []*s3.Object{
+		object("p-e/templates/environment/aaaa/alarms.yaml", old),
+		object("p-e/templates/environment/aaaa/network.yaml", old),
+		object("p-e/templates/environment/bbbb/alarms.yaml", old),
+		object("p-e/templates/environment/cccc/alarms.yaml", old),
+		object("p-e/templates/environment/cccc/network.yaml", now),
+	}
+
+	referenced := map[string]bool{"p-e/templates/environment/bbbb": true}
+
+	got := unusedNestedTemplates(objects, referenced, now)
+
+	if len(got) != 1 || got[0] != "p-e/templates/environment/aaaa" {
+		t.Errorf("Want only the old unreferenced templates to be unused, got %v", got)
+	}
+}
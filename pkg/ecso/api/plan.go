@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Plan is a deployment that has been prepared, but not yet executed. The
+// plan's change set can be reviewed, and then executed with `ecso apply`
+type Plan struct {
+	Project     string
+	Environment string
+	Service     string
+	Version     string
+	Region      string
+
+	StackName        string
+	StackID          string
+	ChangeSetID      string
+	CreatesStack     bool
+	StackLastUpdated time.Time
+
+	Bucket        string
+	PackagePrefix string
+
+	CreatedAt time.Time
+	Changes   string
+}
+
+// LoadPlan reads a plan from a plan file
+func LoadPlan(file string) (*Plan, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("Failed to read plan file %s. %s", file, err.Error())
+	}
+
+	return plan, nil
+}
+
+// Save writes the plan to a plan file
+func (p *Plan) Save(file string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, os.FileMode(0644))
+}
+
+// HasChanges returns false if the stack was already up to date when the
+// plan was made, in which case there is nothing to apply
+func (p *Plan) HasChanges() bool {
+	return p.ChangeSetID != ""
+}
+
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "Version %s of service %s in the %s environment\n", p.Version, p.Service, p.Environment)
+	fmt.Fprintf(&buf, "Change set %s\n\n", p.ChangeSetID)
+
+	if p.HasChanges() {
+		fmt.Fprintf(&buf, "%s", p.Changes)
+	} else {
+		fmt.Fprintf(&buf, "No changes\n")
+	}
+
+	n, err := w.Write(buf.Bytes())
+
+	return int64(n), err
+}
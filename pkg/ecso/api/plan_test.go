@@ -0,0 +1,50 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlanSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "plan.json")
+
+	plan := &Plan{
+		Project:          "my-project",
+		Environment:      "prod",
+		Service:          "web",
+		Version:          "v1",
+		ChangeSetID:      "arn:aws:cloudformation:ap-southeast-2:123:changeSet/web-1/abc",
+		StackLastUpdated: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	if err := plan.Save(file); err != nil {
+		t.Fatalf("Unexpected error saving plan: %s", err)
+	}
+
+	loaded, err := LoadPlan(file)
+	if err != nil {
+		t.Fatalf("Unexpected error loading plan: %s", err)
+	}
+
+	if loaded.ChangeSetID != plan.ChangeSetID {
+		t.Errorf("Want change set %s, got %s", plan.ChangeSetID, loaded.ChangeSetID)
+	}
+
+	if !loaded.StackLastUpdated.Equal(plan.StackLastUpdated) {
+		t.Errorf("Want stack last updated %s, got %s", plan.StackLastUpdated, loaded.StackLastUpdated)
+	}
+
+	if !loaded.HasChanges() {
+		t.Errorf("Want plan to have changes")
+	}
+}
@@ -1,22 +1,27 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/helpers"
@@ -26,16 +31,26 @@ import (
 
 type ServiceAPI interface {
 	DescribeService(env *ecso.Environment, service *ecso.Service) (*ServiceDescription, error)
-	ServiceUp(p *ecso.Project, env *ecso.Environment, s *ecso.Service, w io.Writer) (*ServiceDescription, error)
-	ServiceDown(p *ecso.Project, env *ecso.Environment, s *ecso.Service, w io.Writer) error
-	ServiceEvents(p *ecso.Project, env *ecso.Environment, s *ecso.Service, f func(*ecs.ServiceEvent, error)) (cancel func(), err error)
+	ServiceUp(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string, skipBuild bool, overrides *StackOverrides, w io.Writer) (*ServiceDescription, error)
+	ServiceDown(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, w io.Writer) error
+	ServiceEvents(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, f func(*ecs.ServiceEvent, error)) (cancel func(), err error)
 	ServiceLogs(p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*cloudwatchlogs.FilteredLogEvent, error)
-	ServiceRollback(p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string, w io.Writer) (*ServiceDescription, error)
-	GetECSContainers(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ContainerList, error)
-	GetECSService(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (*ecs.Service, error)
-	GetECSTasks(p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*ecs.Task, error)
+	ServiceRollback(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string, overrides *StackOverrides, w io.Writer) (*ServiceDescription, error)
+	ServicePromote(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, from *ecso.Environment, source *ServiceVersion, w io.Writer) (*ServiceDescription, error)
+	ServicePlan(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string, skipBuild bool, overrides *StackOverrides, w io.Writer) (*Plan, error)
+	ServiceApply(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, plan *Plan, w io.Writer) (*ServiceDescription, error)
+	GetECSContainers(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ContainerList, error)
+	GetServiceEndpoints(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (EndpointList, error)
+	CleanServiceEndpoints(ctx context.Context, env *ecso.Environment, s *ecso.Service, endpoints EndpointList, w io.Writer) error
+	GetECSService(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (*ecs.Service, error)
+	GetECSTasks(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*ecs.Task, error)
 	GetECSContainerImage(taskDefinitionArn, containerName string, env *ecso.Environment) (string, error)
-	GetAvailableVersions(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ServiceVersionList, error)
+	GetAvailableVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ServiceVersionList, error)
+	GetCurrentAWSPrincipal() (string, error)
+	GetVersion(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string) (*ServiceVersion, error)
+	PruneVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, keep int, dryRun bool, w io.Writer) (ServiceVersionList, error)
+	WaitUntilServiceStable(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, timeout time.Duration) error
+	ServiceDrift(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, w io.Writer) (*helpers.StackDrift, error)
 }
 
 // New creates a new API
@@ -47,6 +62,8 @@ func NewServiceAPI(
 	s3API s3iface.S3API,
 	snsAPI snsiface.SNSAPI,
 	stsAPI stsiface.STSAPI,
+	ecrAPI ecriface.ECRAPI,
+	ec2API ec2iface.EC2API,
 ) ServiceAPI {
 	return &serviceAPI{
 		cloudformationAPI: cloudformationAPI,
@@ -56,6 +73,8 @@ func NewServiceAPI(
 		s3API:             s3API,
 		snsAPI:            snsAPI,
 		stsAPI:            stsAPI,
+		ecrAPI:            ecrAPI,
+		ec2API:            ec2API,
 	}
 }
 
@@ -67,56 +86,214 @@ type serviceAPI struct {
 	s3API             s3iface.S3API
 	snsAPI            snsiface.SNSAPI
 	stsAPI            stsiface.STSAPI
+	ecrAPI            ecriface.ECRAPI
+	ec2API            ec2iface.EC2API
 }
 
-func (api *serviceAPI) GetECSContainers(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ContainerList, error) {
-	tasks, err := api.GetECSTasks(p, env, s)
+func (api *serviceAPI) GetECSContainers(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ContainerList, error) {
+	tasks, err := api.GetECSTasks(ctx, p, env, s)
 	if err != nil {
 		return nil, err
 	}
 
-	return LoadContainerList(tasks, api.ecsAPI)
+	return LoadContainerList(ctx, tasks, api.ecsAPI)
 }
 
-func (api *serviceAPI) GetAvailableVersions(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ServiceVersionList, error) {
-	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI)
+// GetServiceEndpoints returns the SRV records registered for the service,
+// matched against its running containers
+func (api *serviceAPI) GetServiceEndpoints(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (EndpointList, error) {
+	containers, err := api.GetECSContainers(ctx, p, env, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		r53Helper = helpers.NewRoute53Helper(api.route53API)
+		name      = fmt.Sprintf("%s.", s.GetDiscoveryName(env))
+	)
+
+	records, err := r53Helper.ListRecordSets(ctx, name, serviceDiscoveryZone(env))
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := loadContainerInstances(ctx, api.ecsAPI, api.ec2API, env.GetClusterName(), containers)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchEndpoints(name, records, containers, instances), nil
+}
+
+// CleanServiceEndpoints deletes the SRV records of stale endpoints. Records
+// in Cloud Map namespaces are owned by Cloud Map, so are never deleted
+func (api *serviceAPI) CleanServiceEndpoints(ctx context.Context, env *ecso.Environment, s *ecso.Service, endpoints EndpointList, w io.Writer) error {
+	if env.GetServiceDiscovery() != ecso.ServiceDiscoverySRVLambda {
+		return fmt.Errorf("Service discovery records in the '%s' environment are managed by Cloud Map, and cannot be cleaned by ecso", env.Name)
+	}
+
+	records := endpoints.staleRecordSets()
+
+	if len(records) == 0 {
+		fmt.Fprint(w, "No stale records found\n")
+		return nil
+	}
+
+	r53Helper := helpers.NewRoute53Helper(api.route53API)
+
+	return r53Helper.DeleteRecordSets(ctx, records, "Deleted by ecso service endpoints", w)
+}
+
+// GetCurrentAWSPrincipal returns the ARN of the IAM principal whose
+// credentials are being used
+func (api *serviceAPI) GetCurrentAWSPrincipal() (string, error) {
+	resp, err := api.stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+
+	return *resp.Arn, nil
+}
+
+func (api *serviceAPI) GetAvailableVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (ServiceVersionList, error) {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
 
 	bucket, err := envAPI.GetEcsoBucket(env)
 	if err != nil {
 		return nil, err
 	}
 
-	prefix := s.GetDeploymentBucketPrefix(env)
+	current, err := api.getCurrentVersion(env, s)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := api.s3API.ListObjects(&s3.ListObjectsInput{
-		Bucket: aws.String(bucket),
-		Prefix: aws.String(prefix),
-	})
+	versions, err := loadPackageVersions(ctx, api.s3API, env.Region, bucket, s.GetDeploymentBucketPrefix(env), current)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServiceVersionList(s.Name, versions), nil
+}
 
+func (api *serviceAPI) GetVersion(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, version string) (*ServiceVersion, error) {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+
+	bucket, err := envAPI.GetEcsoBucket(env)
 	if err != nil {
 		return nil, err
 	}
 
-	versions := make([]*ServiceVersion, 0)
-	labels := make(map[string]bool)
+	current, err := api.getCurrentVersion(env, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == "" {
+		if current == "" {
+			return nil, fmt.Errorf("Service %s is not deployed to the %s environment", s.Name, env.Name)
+		}
 
-	for _, o := range resp.Contents {
-		suffix := strings.TrimPrefix(*o.Key, prefix+"/")
-		tokens := strings.Split(suffix, "/")
-		labels[tokens[0]] = true
+		version = current
 	}
 
-	for k := range labels {
-		versions = append(versions, &ServiceVersion{
-			Service: s.Name,
-			Label:   k,
-		})
+	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+	pkg := helpers.NewPackage(bucket, s.GetDeploymentBucketPrefixForVersion(env, version), env.Region)
+
+	exists, err := cfn.PackageIsUploadedToS3(ctx, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("Version %s of service %s not found in the %s environment", version, s.Name, env.Name)
+	}
+
+	manifest, err := downloadManifest(ctx, api.s3API, env.Region, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ServiceVersion{
+		Service: s.Name,
+		PackageVersion: PackageVersion{
+			Label:    version,
+			Current:  version == current,
+			Manifest: manifest,
+		},
+	}
+
+	if manifest != nil {
+		result.Timestamp = manifest.Timestamp
+	}
+
+	return result, nil
+}
+
+func (api *serviceAPI) PruneVersions(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, keep int, dryRun bool, w io.Writer) (ServiceVersionList, error) {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+
+	bucket, err := envAPI.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := api.getCurrentVersion(env, s)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := s.GetDeploymentBucketPrefix(env)
+
+	versions, err := loadPackageVersions(ctx, api.s3API, env.Region, bucket, prefix, current)
+	if err != nil {
+		return nil, err
+	}
+
+	prunable := versions.Prunable(keep)
+
+	if !dryRun {
+		if err := deletePackageVersions(ctx, api.s3API, env.Region, bucket, prefix, prunable, w); err != nil {
+			return nil, err
+		}
+
+		if err := pruneNestedTemplates(ctx, api.s3API, env.Region, bucket, prefix, s.GetTemplatesBucketPrefix(env), versions.Except(prunable), w); err != nil {
+			return nil, err
+		}
+
+		if len(prunable) > 0 {
+			if err := writeVersioningNote(ctx, api.s3API, bucket, w); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return NewServiceVersionList(s.Name, prunable), nil
+}
+
+// getCurrentVersion returns the version of the service that is currently
+// deployed, according to the version tag of the service's cloudformation
+// stack. If the stack does not exist an empty string is returned
+func (api *serviceAPI) getCurrentVersion(env *ecso.Environment, s *ecso.Service) (string, error) {
+	var (
+		stack = s.GetCloudFormationStackName(env)
+		cfn   = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+	)
+
+	exists, err := cfn.StackExists(stack)
+	if err != nil || !exists {
+		return "", err
 	}
 
-	return ServiceVersionList(versions), nil
+	tags, err := cfn.GetStackTags(stack)
+	if err != nil {
+		return "", err
+	}
+
+	return tags["version"], nil
 }
 
-func (api *serviceAPI) GetECSService(p *ecso.Project, env *ecso.Environment, s *ecso.Service) (*ecs.Service, error) {
+func (api *serviceAPI) GetECSService(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) (*ecs.Service, error) {
 	var (
 		cfn = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
 	)
@@ -128,7 +305,7 @@ func (api *serviceAPI) GetECSService(p *ecso.Project, env *ecso.Environment, s *
 	}
 
 	if serviceName, ok := outputs["Service"]; ok {
-		resp, err := api.ecsAPI.DescribeServices(&ecs.DescribeServicesInput{
+		resp, err := api.ecsAPI.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
 			Cluster: aws.String(env.GetClusterName()),
 			Services: []*string{
 				aws.String(serviceName),
@@ -167,16 +344,16 @@ func (api *serviceAPI) GetECSContainerImage(taskDefinitionArn, containerName str
 	return "", nil
 }
 
-func (api *serviceAPI) GetECSTasks(p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*ecs.Task, error) {
+func (api *serviceAPI) GetECSTasks(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*ecs.Task, error) {
 	result := make([]*ecs.Task, 0)
 
-	runningService, err := api.GetECSService(p, env, s)
+	runningService, err := api.GetECSService(ctx, p, env, s)
 
 	if err != nil || runningService == nil {
 		return result, err
 	}
 
-	tasks, err := api.ecsAPI.ListTasks(&ecs.ListTasksInput{
+	tasks, err := api.ecsAPI.ListTasksWithContext(ctx, &ecs.ListTasksInput{
 		Cluster:     aws.String(env.GetClusterName()),
 		ServiceName: runningService.ServiceName,
 	})
@@ -185,7 +362,7 @@ func (api *serviceAPI) GetECSTasks(p *ecso.Project, env *ecso.Environment, s *ec
 		return result, err
 	}
 
-	resp, err := api.ecsAPI.DescribeTasks(&ecs.DescribeTasksInput{
+	resp, err := api.ecsAPI.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
 		Cluster: aws.String(env.GetClusterName()),
 		Tasks:   tasks.TaskArns,
 	})
@@ -222,6 +399,12 @@ func (api *serviceAPI) DescribeService(env *ecso.Environment, service *ecso.Serv
 		desc.URL = fmt.Sprintf("http://%s%s", envOutputs["RecordSet"], service.Route)
 	}
 
+	// Services are only registered in Cloud Map if their template has a
+	// DiscoveryService
+	if _, ok := serviceOutputs["DiscoveryService"]; ok || env.GetServiceDiscovery() == ecso.ServiceDiscoverySRVLambda {
+		desc.DiscoveryName = service.GetDiscoveryName(env)
+	}
+
 	for k, v := range serviceOutputs {
 		desc.CloudFormationOutputs[k] = v
 	}
@@ -229,22 +412,58 @@ func (api *serviceAPI) DescribeService(env *ecso.Environment, service *ecso.Serv
 	return desc, nil
 }
 
-func (api *serviceAPI) ServiceDown(project *ecso.Project, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
-	if err := api.deleteServiceStack(env, service, w); err != nil {
+// ServiceDrift detects drift in the service stack and any nested stacks,
+// and compares them with the service's local templates
+func (api *serviceAPI) ServiceDrift(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, w io.Writer) (*helpers.StackDrift, error) {
+	var (
+		stackName = s.GetCloudFormationStackName(env)
+		cfn       = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+	)
+
+	exists, err := cfn.StackExists(stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("Service '%s' has not been deployed to the '%s' environment", s.Name, env.Name)
+	}
+
+	return cfn.DetectDrift(ctx, stackName, s.GetCloudFormationTemplateFile(), w)
+}
+
+func (api *serviceAPI) ServiceDown(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, HistoryActionServiceDown)
+	if err != nil {
 		return err
 	}
 
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	history := startHistoryEntry(envAPI, project, env, service.Name, HistoryActionServiceDown, "", w)
+
+	if err := api.deleteServiceStack(ctx, env, service, w); err != nil {
+		return history.finish(err)
+	}
+
 	fmt.Fprint(w, "\n")
 
-	if err := api.clearServiceDNSRecords(env, service, w); err != nil {
-		return err
+	// Cloud Map records are removed along with the service stack
+	if env.GetServiceDiscovery() == ecso.ServiceDiscoverySRVLambda {
+		if err := api.clearServiceDNSRecords(ctx, env, service, w); err != nil {
+			return history.finish(err)
+		}
 	}
 
-	return nil
+	return history.finish(nil)
 }
 
-func (api *serviceAPI) ServiceEvents(p *ecso.Project, env *ecso.Environment, s *ecso.Service, f func(*ecs.ServiceEvent, error)) (cancel func(), err error) {
-	runningService, err := api.GetECSService(p, env, s)
+func (api *serviceAPI) ServiceEvents(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, f func(*ecs.ServiceEvent, error)) (cancel func(), err error) {
+	runningService, err := api.GetECSService(ctx, p, env, s)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +474,22 @@ func (api *serviceAPI) ServiceEvents(p *ecso.Project, env *ecso.Environment, s *
 
 	h := helpers.NewECSHelper(api.ecsAPI)
 
-	return h.LogServiceEvents(*runningService.ServiceArn, env.GetClusterName(), f), nil
+	return h.LogServiceEvents(ctx, *runningService.ServiceArn, env.GetClusterName(), f), nil
+}
+
+func (api *serviceAPI) WaitUntilServiceStable(ctx context.Context, p *ecso.Project, env *ecso.Environment, s *ecso.Service, timeout time.Duration) error {
+	runningService, err := api.GetECSService(ctx, p, env, s)
+	if err != nil {
+		return err
+	}
+
+	if runningService == nil {
+		return fmt.Errorf("No service named %s is running", s.Name)
+	}
+
+	h := helpers.NewECSHelper(api.ecsAPI)
+
+	return h.WaitUntilServiceStable(ctx, *runningService.ServiceArn, env.GetClusterName(), timeout)
 }
 
 func (api *serviceAPI) ServiceLogs(p *ecso.Project, env *ecso.Environment, s *ecso.Service) ([]*cloudwatchlogs.FilteredLogEvent, error) {
@@ -287,8 +521,17 @@ func (api *serviceAPI) ServiceLogs(p *ecso.Project, env *ecso.Environment, s *ec
 	return resp.Events, nil
 }
 
-func (api *serviceAPI) ServiceRollback(project *ecso.Project, env *ecso.Environment, service *ecso.Service, version string, w io.Writer) (*ServiceDescription, error) {
-	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI)
+func (api *serviceAPI) ServiceRollback(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, version string, overrides *StackOverrides, w io.Writer) (*ServiceDescription, error) {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, HistoryActionServiceRollback)
+	if err != nil {
+		return nil, err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
 
 	bucket, err := envAPI.GetEcsoBucket(env)
 	if err != nil {
@@ -298,7 +541,7 @@ func (api *serviceAPI) ServiceRollback(project *ecso.Project, env *ecso.Environm
 	cfn := helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
 	pkg := helpers.NewPackage(bucket, service.GetDeploymentBucketPrefixForVersion(env, version), env.Region)
 
-	exists, err := cfn.PackageIsUploadedToS3(pkg)
+	exists, err := cfn.PackageIsUploadedToS3(ctx, pkg)
 	if err != nil {
 		return nil, err
 	}
@@ -307,63 +550,360 @@ func (api *serviceAPI) ServiceRollback(project *ecso.Project, env *ecso.Environm
 		return nil, fmt.Errorf("Version %s of service %s not found", version, service.Name)
 	}
 
-	if err := envAPI.SendNotification(env, fmt.Sprintf("Commenced rollback of %s version %s to %s", service.Name, version, env.Name)); err != nil {
-		fmt.Fprintf(w, "WARNING Failed to send rollback commencing notification to sns. %s", err.Error())
+	// Overrides are deployed from a copy of the package, so that the version
+	// itself still deploys with the params and tags it was packaged with
+	if !overrides.IsEmpty() {
+		if pkg, err = repackageWithOverrides(ctx, cfn, api.s3API, env.Region, pkg, overrides, ui.NewPrefixWriter(w, "  ")); err != nil {
+			return nil, err
+		}
 	}
 
+	deployment := startDeployment(ctx, envAPI, project, env, deploymentEvent(project, env, service, helpers.DeploymentActionRollback, version), w)
+
 	// deploy the service cfn stack
-	if err := api.deployServiceStack(pkg, env, service, w); err != nil {
-		if err := envAPI.SendNotification(env, fmt.Sprintf("Failed to deploy %s to %s", service.Name, env.Name)); err != nil {
-			fmt.Fprintf(w, "WARNING Failed to send deployment failure notification to sns. %s", err.Error())
-		}
+	if err := api.deployServiceStack(ctx, pkg, env, service, w); err != nil {
+		return nil, deployment.failed(err)
+	}
+
+	deployment.succeeded()
+
+	return api.DescribeService(env, service)
+}
+
+func (api *serviceAPI) ServiceUp(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, version string, skipBuild bool, overrides *StackOverrides, w io.Writer) (*ServiceDescription, error) {
+	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, HistoryActionServiceUp)
+	if err != nil {
+		return nil, err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	bucket, err := envAPI.GetEcsoBucket(env)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := envAPI.SendNotification(env, fmt.Sprintf("Completed rollback of %s version %s to %s", service.Name, version, env.Name)); err != nil {
-		fmt.Fprintf(w, "WARNING Failed to send deployment completed notification to sns. %s", err.Error())
+	if err := api.ensureVersionIsNew(ctx, bucket, env, service, version); err != nil {
+		return nil, err
+	}
+
+	if err := validateServiceOverrides(service, overrides); err != nil {
+		return nil, err
+	}
+
+	manifest, err := createManifest(api.stsAPI, project, version)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := startDeployment(ctx, envAPI, project, env, deploymentEvent(project, env, service, helpers.DeploymentActionDeploy, version), w)
+
+	images, err := api.publishServiceImages(ctx, env, service, skipBuild, w)
+	if err != nil {
+		return nil, deployment.failed(err)
+	}
+
+	// register task
+	taskDefinition, err := api.registerECSTaskDefinition(ctx, project, env, service, nil, images, w)
+	if err != nil {
+		return nil, deployment.failed(err)
 	}
 
+	// deploy the service cfn stack
+	if err := api.packageAndDeployServiceStack(ctx, bucket, project, env, service, taskDefinition, manifest, overrides, w); err != nil {
+		return nil, deployment.failed(err)
+	}
+
+	api.enforceRetentionPolicy(ctx, project, env, service, w)
+
+	deployment.succeeded()
+
 	return api.DescribeService(env, service)
 }
 
-func (api *serviceAPI) ServiceUp(project *ecso.Project, env *ecso.Environment, service *ecso.Service, w io.Writer) (*ServiceDescription, error) {
-	version := util.VersionFromTime(time.Now())
-	envAPI := NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI)
+func (api *serviceAPI) ServicePromote(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, from *ecso.Environment, source *ServiceVersion, w io.Writer) (*ServiceDescription, error) {
+	var (
+		version  = source.Label
+		info     = ui.NewInfoWriter(w)
+		envAPI   = NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+		promoted = fmt.Sprintf("%s@%s", from.Name, version)
+	)
+
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, HistoryActionServicePromote)
+	if err != nil {
+		return nil, err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	if source.Manifest == nil {
+		return nil, fmt.Errorf("Version %s of service %s in the %s environment has no deployment manifest, so its images cannot be promoted", version, service.Name, from.Name)
+	}
+
+	if source.Manifest.Status != helpers.ManifestStatusDeployed {
+		return nil, fmt.Errorf("Version %s of service %s was never successfully deployed to the %s environment (status is '%s')", version, service.Name, from.Name, source.Status())
+	}
 
 	bucket, err := envAPI.GetEcsoBucket(env)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := envAPI.SendNotification(env, fmt.Sprintf("Commenced deployment of %s to %s", service.Name, env.Name)); err != nil {
-		fmt.Fprintf(w, "WARNING Failed to send deployment commencing notification to sns. %s", err.Error())
+	if err := api.ensureVersionIsNew(ctx, bucket, env, service, version); err != nil {
+		return nil, err
 	}
 
-	// register task
-	taskDefinition, err := api.registerECSTaskDefinition(project, env, service, w)
+	principal, err := api.GetCurrentAWSPrincipal()
 	if err != nil {
-		if err := envAPI.SendNotification(env, fmt.Sprintf("Failed to deploy %s to %s", service.Name, env.Name)); err != nil {
-			fmt.Fprintf(w, "WARNING Failed to send deployment failure notification to sns. %s", err.Error())
-		}
 		return nil, err
 	}
 
-	// deploy the service cfn stack
-	if err := api.packageAndDeployServiceStack(bucket, project, env, service, taskDefinition, version, w); err != nil {
-		if err := envAPI.SendNotification(env, fmt.Sprintf("Failed to deploy %s to %s", service.Name, env.Name)); err != nil {
-			fmt.Fprintf(w, "WARNING Failed to send deployment failure notification to sns. %s", err.Error())
-		}
+	manifest := helpers.NewPromotedManifest(source.Manifest, promoted, principal, time.Now())
+
+	event := deploymentEvent(project, env, service, helpers.DeploymentActionPromote, version)
+	event.Source = from.Name
+
+	deployment := startDeployment(ctx, envAPI, project, env, event, w)
+
+	fmt.Fprintf(info, "Promoting images from %s", promoted)
+
+	taskDefinition, err := api.registerECSTaskDefinition(ctx, project, env, service, source.Manifest, nil, w)
+	if err != nil {
+		return nil, deployment.failed(err)
+	}
+
+	if err := api.packageAndDeployServiceStack(ctx, bucket, project, env, service, taskDefinition, manifest, nil, w); err != nil {
+		return nil, deployment.failed(err)
+	}
+
+	api.enforceRetentionPolicy(ctx, project, env, service, w)
+
+	deployment.succeeded()
+
+	return api.DescribeService(env, service)
+}
+
+// ServicePlan packages a new version of the service and creates a change set
+// for it, without executing it. The returned plan can be saved and later
+// executed using ServiceApply
+func (api *serviceAPI) ServicePlan(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, version string, skipBuild bool, overrides *StackOverrides, w io.Writer) (*Plan, error) {
+	var (
+		stackName = service.GetCloudFormationStackName(env)
+		info      = ui.NewInfoWriter(w)
+		cfn       = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+		envAPI    = NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+	)
+
+	// Creating a change set for a new stack creates the stack, so the
+	// service is locked just as it is for `up`
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, lockActionServicePlan)
+	if err != nil {
+		return nil, err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	bucket, err := envAPI.GetEcsoBucket(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := api.ensureVersionIsNew(ctx, bucket, env, service, version); err != nil {
+		return nil, err
+	}
+
+	if err := validateServiceOverrides(service, overrides); err != nil {
+		return nil, err
+	}
+
+	manifest, err := createManifest(api.stsAPI, project, version)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Status = helpers.ManifestStatusPlanned
+
+	images, err := api.publishServiceImages(ctx, env, service, skipBuild, w)
+	if err != nil {
+		return nil, err
+	}
+
+	taskDefinition, err := api.registerECSTaskDefinition(ctx, project, env, service, nil, images, w)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := api.packageServiceStack(ctx, bucket, project, env, service, taskDefinition, manifest, overrides, w)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(info, "Creating changeset for service cloudformation stack '%s'...", stackName)
+
+	result, err := cfn.CreateChangeSet(ctx, pkg, stackName, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Project:       project.Name,
+		Environment:   env.Name,
+		Service:       service.Name,
+		Version:       version,
+		Region:        env.Region,
+		StackName:     stackName,
+		StackID:       result.StackID,
+		CreatesStack:  result.IsCreate,
+		Bucket:        bucket,
+		PackagePrefix: pkg.GetBucketPrefix(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if !result.DidRequireUpdating {
+		return plan, nil
+	}
+
+	plan.ChangeSetID = result.ChangeSetID
+
+	// Record the stack's last update after the change set has been created,
+	// as creating a change set for a new stack creates the stack itself
+	if plan.StackLastUpdated, err = cfn.GetStackLastUpdated(stackName); err != nil {
+		return nil, err
+	}
+
+	changes, err := cfn.DescribeChangeSet(result.ChangeSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Changes = changes.String()
+
+	if err := cfn.DeleteStaleChangeSets(stackName, result.ChangeSetID, ui.NewPrefixWriter(w, "  ")); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to delete stale changesets. %s\n", err.Error())
+	}
+
+	return plan, nil
+}
+
+// ServiceApply executes the change set of a plan created by ServicePlan. The
+// plan is refused if the service stack has been updated since the plan was
+// made, or if its change set can no longer be executed
+func (api *serviceAPI) ServiceApply(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, plan *Plan, w io.Writer) (*ServiceDescription, error) {
+	var (
+		version = plan.Version
+		info    = ui.NewInfoWriter(w)
+		cfn     = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+		envAPI  = NewEnvironmentAPI(api.cloudformationAPI, api.cloudwatchlogsAPI, api.ecsAPI, api.route53API, api.s3API, api.snsAPI, api.stsAPI, api.ecrAPI, api.ec2API)
+		pkg     = helpers.NewPackage(plan.Bucket, plan.PackagePrefix, env.Region)
+	)
+
+	lock, err := acquireLock(ctx, envAPI, env, service.Name, HistoryActionServiceUp)
+	if err != nil {
+		return nil, err
+	}
+
+	defer releaseLock(lock, w)
+
+	ctx = lock.Context()
+
+	if !plan.HasChanges() {
+		return nil, fmt.Errorf("The plan for version %s of service %s contains no changes", version, service.Name)
+	}
+
+	changeSet, err := cfn.GetChangeSet(plan.ChangeSetID)
+	if err != nil {
+		return nil, fmt.Errorf("Changeset %s could not be found. Create a new plan using `ecso service plan`. %s", plan.ChangeSetID, err.Error())
+	}
+
+	if aws.StringValue(changeSet.Status) != cloudformation.ChangeSetStatusCreateComplete || aws.StringValue(changeSet.ExecutionStatus) != cloudformation.ExecutionStatusAvailable {
+		return nil, fmt.Errorf("Changeset %s can no longer be executed (status is %s, execution status is %s). Create a new plan using `ecso service plan`", plan.ChangeSetID, aws.StringValue(changeSet.Status), aws.StringValue(changeSet.ExecutionStatus))
+	}
+
+	lastUpdated, err := cfn.GetStackLastUpdated(plan.StackName)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := envAPI.SendNotification(env, fmt.Sprintf("Completed deployment of %s to %s", service.Name, env.Name)); err != nil {
-		fmt.Fprintf(w, "WARNING Failed to send deployment completed notification to sns. %s", err.Error())
+	if !lastUpdated.Equal(plan.StackLastUpdated) {
+		return nil, fmt.Errorf("Stack '%s' has been updated since the plan was made at %s. Create a new plan using `ecso service plan`", plan.StackName, plan.CreatedAt.Format(time.RFC3339))
+	}
+
+	manifest, err := downloadManifest(ctx, api.s3API, env.Region, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := startDeployment(ctx, envAPI, project, env, deploymentEvent(project, env, service, helpers.DeploymentActionDeploy, version), w)
+
+	fmt.Fprintf(info, "Executing changeset %s...", plan.ChangeSetID)
+
+	deployErr := cfn.ExecuteChangeSet(ctx, &helpers.DeploymentResult{
+		StackID:            plan.StackID,
+		ChangeSetID:        plan.ChangeSetID,
+		DidRequireUpdating: true,
+		IsCreate:           plan.CreatesStack,
+	}, ui.NewPrefixWriter(w, "  "))
+
+	api.updateManifestStatus(env, pkg, manifest, deployErr, w)
+
+	if deployErr != nil {
+		return nil, deployment.failed(deployErr)
 	}
 
+	if err := cfn.DeleteStaleChangeSets(plan.StackName, plan.ChangeSetID, ui.NewPrefixWriter(w, "  ")); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to delete stale changesets. %s\n", err.Error())
+	}
+
+	api.enforceRetentionPolicy(ctx, project, env, service, w)
+
+	deployment.succeeded()
+
 	return api.DescribeService(env, service)
 }
 
-func (api *serviceAPI) deployServiceStack(pkg *helpers.Package, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
+// ensureVersionIsNew returns an error if a package for the version of the
+// service has already been uploaded, so that versions are never silently
+// overwritten
+func (api *serviceAPI) ensureVersionIsNew(ctx context.Context, bucket string, env *ecso.Environment, service *ecso.Service, version string) error {
+	var (
+		cfn = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
+		pkg = helpers.NewPackage(bucket, service.GetDeploymentBucketPrefixForVersion(env, version), env.Region)
+	)
+
+	exists, err := cfn.PackageIsUploadedToS3(ctx, pkg)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("Version %s of service %s already exists in the %s environment. Choose a different version label, or use `ecso service rollback` to redeploy it", version, service.Name, env.Name)
+	}
+
+	return nil
+}
+
+// enforceRetentionPolicy prunes old versions of the service according to
+// the project's retention policy, if it has one. Failing to prune does not
+// fail the deployment
+func (api *serviceAPI) enforceRetentionPolicy(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, w io.Writer) {
+	if project.RetentionPolicy == nil || project.RetentionPolicy.Keep < 1 {
+		return
+	}
+
+	if _, err := api.PruneVersions(ctx, project, env, service, project.RetentionPolicy.Keep, false, ui.NewPrefixWriter(w, "  ")); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to prune old service versions. %s\n", err.Error())
+	}
+}
+
+func (api *serviceAPI) deployServiceStack(ctx context.Context, pkg *helpers.Package, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
 	var (
 		stackName = service.GetCloudFormationStackName(env)
 		info      = ui.NewInfoWriter(w)
@@ -372,7 +912,7 @@ func (api *serviceAPI) deployServiceStack(pkg *helpers.Package, env *ecso.Enviro
 
 	fmt.Fprintf(info, "Deploying service cloudformation stack '%s'...", stackName)
 
-	result, err := cfn.Deploy(pkg, stackName, false, ui.NewPrefixWriter(w, "  "))
+	result, err := cfn.Deploy(ctx, pkg, stackName, false, ui.NewPrefixWriter(w, "  "))
 	if err != nil {
 		return err
 	}
@@ -384,8 +924,25 @@ func (api *serviceAPI) deployServiceStack(pkg *helpers.Package, env *ecso.Enviro
 	return nil
 }
 
-func (api *serviceAPI) packageAndDeployServiceStack(bucket string, project *ecso.Project, env *ecso.Environment, service *ecso.Service, taskDefinition *ecs.TaskDefinition, version string, w io.Writer) error {
+func (api *serviceAPI) packageAndDeployServiceStack(ctx context.Context, bucket string, project *ecso.Project, env *ecso.Environment, service *ecso.Service, taskDefinition *ecs.TaskDefinition, manifest *helpers.Manifest, overrides *StackOverrides, w io.Writer) error {
+	pkg, err := api.packageServiceStack(ctx, bucket, project, env, service, taskDefinition, manifest, overrides, w)
+	if err != nil {
+		return err
+	}
+
+	deployErr := api.deployServiceStack(ctx, pkg, env, service, w)
+
+	api.updateManifestStatus(env, pkg, manifest, deployErr, w)
+
+	return deployErr
+}
+
+// packageServiceStack uploads the service's cloudformation templates, params
+// and tags for the version described by manifest, along with the manifest
+// itself. Any overrides are applied over the params and tags from project.json
+func (api *serviceAPI) packageServiceStack(ctx context.Context, bucket string, project *ecso.Project, env *ecso.Environment, service *ecso.Service, taskDefinition *ecs.TaskDefinition, manifest *helpers.Manifest, overrides *StackOverrides, w io.Writer) (*helpers.Package, error) {
 	var (
+		version  = manifest.Version
 		prefix   = service.GetDeploymentBucketPrefixForVersion(env, version)
 		template = service.GetCloudFormationTemplateFile()
 		cfn      = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
@@ -394,17 +951,78 @@ func (api *serviceAPI) packageAndDeployServiceStack(bucket string, project *ecso
 	params, err := getServiceStackParameters(cfn, project, env, service, taskDefinition, version)
 
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if _, ok := params["ServiceDiscoveryNamespace"]; ok {
+		// Services with a route or a port mapping are the ones that can be
+		// discovered, so only they are warned about old templates
+		_, hasPort := params["DiscoveryContainerName"]
+		discoverable := service.Route != "" || hasPort
+
+		for _, name := range []string{"ServiceDiscoveryNamespace", "DiscoveryContainerName", "DiscoveryContainerPort"} {
+			if _, ok := params[name]; !ok {
+				continue
+			}
+
+			declared, err := helpers.TemplateFileDeclaresParameter(template, name)
+			if err != nil {
+				return nil, err
+			}
+
+			if !declared {
+				delete(params, name)
+
+				if discoverable {
+					fmt.Fprintf(w, "WARNING The service template does not declare a %s parameter, so the service will not be registered for service discovery. Run `ecso service upgrade-templates %s` to update it\n", name, service.Name)
+					discoverable = false
+				}
+			}
+		}
 	}
 
 	tags := getServiceStackTags(project, env, service, version)
 
-	pkg, err := cfn.Package(template, bucket, prefix, tags, params, ui.NewPrefixWriter(w, "  "))
+	overrides.Apply(params, tags)
+
+	pkg, err := cfn.Package(ctx, template, bucket, prefix, service.GetTemplatesBucketPrefix(env), tags, params, ui.NewPrefixWriter(w, "  "))
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Promoted manifests keep the images and task definition of the version
+	// they were promoted from
+	if manifest.PromotedFrom == "" {
+		manifest.TaskDefinitionArn = *taskDefinition.TaskDefinitionArn
+
+		for _, container := range taskDefinition.ContainerDefinitions {
+			manifest.AddImage(*container.Name, *container.Image)
+		}
+	}
+
+	fmt.Fprintf(w, "  Uploading deployment manifest to %s\n", pkg.GetManifestBucketKey())
+
+	if err := uploadManifest(ctx, api.s3API, env.Region, pkg, manifest, ui.NewPrefixWriter(w, "    ")); err != nil {
+		return nil, err
 	}
 
-	return api.deployServiceStack(pkg, env, service, w)
+	return pkg, nil
+}
+
+// updateManifestStatus records the outcome of deploying a package in its
+// manifest. Failing to update the manifest does not fail the deployment. The
+// outcome is recorded even if the deployment was cancelled, so the command's
+// context is not used
+func (api *serviceAPI) updateManifestStatus(env *ecso.Environment, pkg *helpers.Package, manifest *helpers.Manifest, deployErr error, w io.Writer) {
+	if deployErr != nil {
+		manifest.Status = helpers.ManifestStatusFailed
+	} else {
+		manifest.Status = helpers.ManifestStatusDeployed
+	}
+
+	if err := uploadManifest(context.Background(), api.s3API, env.Region, pkg, manifest, ui.NewPrefixWriter(w, "    ")); err != nil {
+		fmt.Fprintf(w, "WARNING Failed to update deployment manifest status. %s\n", err.Error())
+	}
 }
 
 func getServiceStackParameters(cfn helpers.CloudFormationHelper, project *ecso.Project, env *ecso.Environment, service *ecso.Service, taskDefinition *ecs.TaskDefinition, version string) (map[string]string, error) {
@@ -423,6 +1041,22 @@ func getServiceStackParameters(cfn helpers.CloudFormationHelper, project *ecso.P
 		"TaskDefinition": *taskDefinition.TaskDefinitionArn,
 	}
 
+	if env.GetServiceDiscovery() == ecso.ServiceDiscoveryCloudMap {
+		namespace, ok := outputs["ServiceDiscoveryNamespace"]
+		if !ok {
+			return nil, fmt.Errorf("The '%s' environment has not been deployed with cloudmap service discovery. Run `ecso environment up %s` first", env.Name, env.Name)
+		}
+
+		params["ServiceDiscoveryNamespace"] = namespace
+
+		// Services without a route register the first container with a
+		// port mapping, as Cloud Map registers a single port per service
+		if name, port, ok := discoveryContainer(taskDefinition); ok && len(service.Route) == 0 {
+			params["DiscoveryContainerName"] = name
+			params["DiscoveryContainerPort"] = fmt.Sprintf("%d", port)
+		}
+	}
+
 	if len(service.Route) > 0 {
 		params["VPC"] = outputs["VPC"]
 		params["Listener"] = outputs["Listener"]
@@ -438,6 +1072,20 @@ func getServiceStackParameters(cfn helpers.CloudFormationHelper, project *ecso.P
 	return params, nil
 }
 
+// discoveryContainer returns the name and container port of the first
+// container in the task definition with a port mapping
+func discoveryContainer(taskDefinition *ecs.TaskDefinition) (string, int64, bool) {
+	for _, c := range taskDefinition.ContainerDefinitions {
+		for _, p := range c.PortMappings {
+			if aws.Int64Value(p.ContainerPort) > 0 {
+				return aws.StringValue(c.Name), aws.Int64Value(p.ContainerPort), true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
 func getServiceStackTags(project *ecso.Project, env *ecso.Environment, service *ecso.Service, version string) map[string]string {
 	tags := map[string]string{
 		"project":          project.Name,
@@ -453,10 +1101,54 @@ func getServiceStackTags(project *ecso.Project, env *ecso.Environment, service *
 	return tags
 }
 
-func (api *serviceAPI) registerECSTaskDefinition(project *ecso.Project, env *ecso.Environment, service *ecso.Service, w io.Writer) (*ecs.TaskDefinition, error) {
+// publishServiceImages builds and pushes an image for each container with a
+// `build` section in the service's compose file, returning the pushed image
+// references keyed by container name
+func (api *serviceAPI) publishServiceImages(ctx context.Context, env *ecso.Environment, service *ecso.Service, skipBuild bool, w io.Writer) (map[string]string, error) {
+	var (
+		info      = ui.NewInfoWriter(w)
+		publisher = helpers.NewImagePublisher(helpers.NewDockerImageBuilder(), helpers.NewECRRegistry(api.ecrAPI))
+		images    = make(map[string]string)
+	)
+
+	builds, err := service.GetContainerBuilds(env)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]string, 0, len(builds))
+
+	for name := range builds {
+		containers = append(containers, name)
+	}
+
+	sort.Strings(containers)
+
+	for _, name := range containers {
+		fmt.Fprintf(info, "Publishing image for %s container...", name)
+
+		image, err := publisher.Publish(ctx, service.GetImageRepositoryName(name), builds[name], skipBuild, ui.NewPrefixWriter(w, "  "))
+		if err != nil {
+			return nil, err
+		}
+
+		images[name] = image
+	}
+
+	return images, nil
+}
+
+// registerECSTaskDefinition converts the service's compose file to a task
+// definition and registers it with ECS. If source is not nil, container images
+// are taken from the source manifest rather than the compose file. Images in
+// built replace those of the containers they are keyed by. All images are
+// pinned to the digest their tag currently refers to, so that later rollbacks
+// run exactly the same code
+func (api *serviceAPI) registerECSTaskDefinition(ctx context.Context, project *ecso.Project, env *ecso.Environment, service *ecso.Service, source *helpers.Manifest, built map[string]string, w io.Writer) (*ecs.TaskDefinition, error) {
 	var (
 		taskName = service.GetECSTaskDefinitionName(env)
 		info     = ui.NewInfoWriter(w)
+		resolver = helpers.NewImageResolver(api.ecrAPI, env.Region, nil)
 	)
 
 	// TODO: fully qualify the path to the service compose file
@@ -468,6 +1160,40 @@ func (api *serviceAPI) registerECSTaskDefinition(project *ecso.Project, env *ecs
 		return nil, err
 	}
 
+	for _, container := range taskDefinition.ContainerDefinitions {
+		if source != nil {
+			image, ok := source.ImageReference(*container.Name)
+			if !ok {
+				return nil, fmt.Errorf("No image for the %s container was found in version %s", *container.Name, source.Version)
+			}
+
+			if !strings.Contains(image, "@") {
+				fmt.Fprintf(w, "WARNING The image %s for the %s container was not pinned to a digest in version %s, and will be resolved again\n", image, *container.Name, source.Version)
+			}
+
+			fmt.Fprintf(info, "Using image %s for %s container\n", image, *container.Name)
+			container.Image = aws.String(image)
+		}
+
+		if image, ok := built[*container.Name]; ok {
+			container.Image = aws.String(image)
+		}
+
+		if aws.StringValue(container.Image) == "" {
+			return nil, fmt.Errorf("The %s container has no image. Add an image or build section to it in %s", *container.Name, service.ComposeFile)
+		}
+
+		image, err := resolver.Resolve(ctx, *container.Image)
+		if err != nil {
+			return nil, err
+		}
+
+		if image != *container.Image {
+			fmt.Fprintf(info, "Pinned %s container image %s to %s\n", *container.Name, *container.Image, image)
+			container.Image = aws.String(image)
+		}
+	}
+
 	for _, container := range taskDefinition.ContainerDefinitions {
 		fmt.Fprintf(info, "Configuring cloudwatch logs for %s container\n", *container.Name)
 		container.SetLogConfiguration(&ecs.LogConfiguration{
@@ -479,10 +1205,13 @@ func (api *serviceAPI) registerECSTaskDefinition(project *ecso.Project, env *ecs
 			},
 		})
 
-		// TODO probably don't automatically add service discovery env config
-		//      if people want to use service discover they can just add the
-		//      required env vars to the helpers docker-compose file. This is
-		//      less magic and more flexible
+		// The service-discovery lambda registers SRV records for containers
+		// that have these vars. Cloud Map registration is configured by the
+		// service template instead
+		if env.GetServiceDiscovery() != ecso.ServiceDiscoverySRVLambda {
+			continue
+		}
+
 		for _, p := range container.PortMappings {
 			fmt.Fprintf(info, "Adding service discovery env var SERVICE_%d_NAME to %s container\n", *p.ContainerPort, *container.Name)
 			container.Environment = append(container.Environment, &ecs.KeyValuePair{
@@ -493,7 +1222,7 @@ func (api *serviceAPI) registerECSTaskDefinition(project *ecso.Project, env *ecs
 	}
 
 	fmt.Fprintf(info, "Registering ECS task definition '%s'...", taskName)
-	resp, err := api.ecsAPI.RegisterTaskDefinition(&ecs.RegisterTaskDefinitionInput{
+	resp, err := api.ecsAPI.RegisterTaskDefinitionWithContext(ctx, &ecs.RegisterTaskDefinitionInput{
 		ContainerDefinitions: taskDefinition.ContainerDefinitions,
 		Family:               taskDefinition.Family,
 		NetworkMode:          taskDefinition.NetworkMode,
@@ -515,23 +1244,33 @@ func (api *serviceAPI) registerECSTaskDefinition(project *ecso.Project, env *ecs
 	return resp.TaskDefinition, nil
 }
 
-func (api *serviceAPI) clearServiceDNSRecords(env *ecso.Environment, service *ecso.Service, w io.Writer) error {
+func (api *serviceAPI) clearServiceDNSRecords(ctx context.Context, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
 	var (
 		r53Helper = helpers.NewRoute53Helper(api.route53API)
-		dnsName   = fmt.Sprintf("%s.%s.%s.", service.Name, env.GetClusterName(), env.CloudFormationParameters["DNSZone"])
+		dnsName   = fmt.Sprintf("%s.", service.GetDiscoveryName(env))
 		info      = ui.NewInfoWriter(w)
 	)
 
 	fmt.Fprintf(info, "Deleting any service SRV DNS records for %s...", dnsName)
 
-	if err := r53Helper.DeleteResourceRecordSetsByName(dnsName, env.CloudFormationParameters["DNSZone"], "Deleted by ecso service down", ui.NewPrefixWriter(w, "  ")); err != nil {
+	if err := r53Helper.DeleteResourceRecordSetsByName(ctx, dnsName, serviceDiscoveryZone(env), "Deleted by ecso service down", ui.NewPrefixWriter(w, "  ")); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (api *serviceAPI) deleteServiceStack(env *ecso.Environment, service *ecso.Service, w io.Writer) error {
+// serviceDiscoveryZone returns the name of the route 53 hosted zone that
+// holds the environment's service discovery records
+func serviceDiscoveryZone(env *ecso.Environment) string {
+	if env.GetServiceDiscovery() == ecso.ServiceDiscoveryCloudMap {
+		return env.GetServiceDiscoveryDomain()
+	}
+
+	return env.CloudFormationParameters["DNSZone"]
+}
+
+func (api *serviceAPI) deleteServiceStack(ctx context.Context, env *ecso.Environment, service *ecso.Service, w io.Writer) error {
 	var (
 		stack = service.GetCloudFormationStackName(env)
 		cfn   = helpers.NewCloudFormationHelper(env.Region, api.cloudformationAPI, api.s3API, api.stsAPI)
@@ -551,5 +1290,5 @@ func (api *serviceAPI) deleteServiceStack(env *ecso.Environment, service *ecso.S
 		return nil
 	}
 
-	return cfn.DeleteStack(stack, ui.NewPrefixWriter(w, "  "))
+	return cfn.DeleteStack(ctx, stack, ui.NewPrefixWriter(w, "  "))
 }
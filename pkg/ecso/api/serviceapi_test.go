@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+func TestDiscoveryContainer(t *testing.T) {
+	taskDefinition := &ecs.TaskDefinition{
+		ContainerDefinitions: []*ecs.ContainerDefinition{
+			{Name: aws.String("sidecar")},
+			{
+				Name: aws.String("worker"),
+				PortMappings: []*ecs.PortMapping{
+					{ContainerPort: aws.Int64(9000), HostPort: aws.Int64(0)},
+				},
+			},
+		},
+	}
+
+	name, port, ok := discoveryContainer(taskDefinition)
+
+	if !ok || name != "worker" || port != 9000 {
+		t.Errorf("Want worker:9000, got %s:%d %t", name, port, ok)
+	}
+
+	if _, _, ok := discoveryContainer(&ecs.TaskDefinition{}); ok {
+		t.Errorf("Want no discovery container for a task without port mappings")
+	}
+}
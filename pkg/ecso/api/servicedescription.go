@@ -11,6 +11,7 @@ import (
 type ServiceDescription struct {
 	Name                     string
 	URL                      string
+	DiscoveryName            string `json:",omitempty"`
 	CloudFormationConsoleURL string
 	CloudWatchLogsConsoleURL string
 	ECSConsoleURL            string
@@ -32,6 +33,10 @@ func (s *ServiceDescription) WriteTo(w io.Writer) (int64, error) {
 		fmt.Fprintf(dt, "Service URL:%s", s.URL)
 	}
 
+	if s.DiscoveryName != "" {
+		fmt.Fprintf(dt, "Discovery name:%s", s.DiscoveryName)
+	}
+
 	fmt.Fprintf(blue, "CloudFormation Outputs:")
 
 	for k, v := range s.CloudFormationOutputs {
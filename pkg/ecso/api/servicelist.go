@@ -0,0 +1,34 @@
+package api
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+	"github.com/bernos/ecso/pkg/ecso/util"
+)
+
+// ServiceList is a list of the ECS services running in an environment
+type ServiceList []*ecs.Service
+
+func (l ServiceList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("SERVICE|TASK|DESIRED|RUNNING|STATUS"))
+
+	for _, s := range l {
+		row := fmt.Sprintf(
+			"%s|%s|%d|%d|%s",
+			*s.ServiceName,
+			util.GetIDFromArn(*s.TaskDefinition),
+			*s.DesiredCount,
+			*s.RunningCount,
+			*s.Status)
+
+		tw.Write([]byte(row))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
@@ -8,19 +8,31 @@ import (
 )
 
 type ServiceVersion struct {
-	Service string
-	Label   string
+	Service        string
+	PackageVersion `yaml:",inline"`
 }
 
 type ServiceVersionList []*ServiceVersion
 
+func NewServiceVersionList(service string, versions PackageVersionList) ServiceVersionList {
+	result := make(ServiceVersionList, 0)
+
+	for _, v := range versions {
+		result = append(result, &ServiceVersion{
+			Service:        service,
+			PackageVersion: *v,
+		})
+	}
+
+	return result
+}
+
 func (l ServiceVersionList) WriteTo(w io.Writer) (int64, error) {
 	tw := ui.NewTableWriter(w, "|")
-	tw.WriteHeader([]byte("SERVICE|VERSION"))
+	tw.WriteHeader([]byte("SERVICE|VERSION|CREATED|DEPLOYED BY|COMMIT|STATUS|CURRENT"))
 
 	for _, v := range l {
-		row := fmt.Sprintf("%s|%s", v.Service, v.Label)
-		tw.Write([]byte(row))
+		tw.Write([]byte(fmt.Sprintf("%s|%s", v.Service, v.row())))
 	}
 
 	n, err := tw.Flush()
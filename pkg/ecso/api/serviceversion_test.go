@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+func TestServiceVersionListWriteTo(t *testing.T) {
+	versions := ServiceVersionList{
+		{
+			Service: "web",
+			PackageVersion: PackageVersion{
+				Label:   "v2",
+				Current: true,
+				Manifest: &helpers.Manifest{
+					Status:     helpers.ManifestStatusDeployed,
+					DeployedBy: "arn:aws:iam::123:user/bob",
+					Git:        &helpers.GitInfo{Commit: "0123456789abcdef", Dirty: true},
+				},
+			},
+		},
+		{
+			Service: "web",
+			PackageVersion: PackageVersion{
+				Label:    "v1",
+				Manifest: &helpers.Manifest{Status: helpers.ManifestStatusFailed},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := versions.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("Want 3 lines, got %d", len(lines))
+	}
+
+	for _, want := range []string{"v2", "0123456-dirty", "deployed", "*"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("Want '%s' in %s", want, lines[1])
+		}
+	}
+
+	if !versions[1].Failed() || !strings.Contains(lines[2], "failed") {
+		t.Errorf("Want v1 to be marked as failed, got %s", lines[2])
+	}
+}
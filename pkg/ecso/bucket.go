@@ -0,0 +1,62 @@
+package ecso
+
+const (
+	// DefaultNoncurrentVersionExpirationDays is the number of days that
+	// overwritten and deleted objects are kept in the ecso bucket, if the
+	// bucket configuration does not say otherwise
+	DefaultNoncurrentVersionExpirationDays = 90
+
+	// DefaultIncompleteUploadExpirationDays is the number of days after
+	// which incomplete multipart uploads to the ecso bucket are aborted
+	DefaultIncompleteUploadExpirationDays = 7
+)
+
+// BucketConfiguration controls the S3 bucket that ecso stores deployment
+// packages and environment resources in. It can be set for the whole project,
+// and for each environment. Settings on an environment take precedence
+type BucketConfiguration struct {
+	// Name overrides the default bucket name of ecso-<region>-<account>
+	Name string `json:",omitempty"`
+
+	// KMSKeyID is the ID or ARN of a KMS key used to encrypt objects in the
+	// bucket. If empty, objects are encrypted with S3 managed keys
+	KMSKeyID string `json:",omitempty"`
+
+	// NoncurrentVersionExpirationDays is the number of days that overwritten
+	// and deleted objects are kept for
+	NoncurrentVersionExpirationDays int `json:",omitempty"`
+}
+
+// GetBucketConfiguration returns the configuration of the environment's ecso
+// bucket, combining the project and environment settings with the defaults
+func (e *Environment) GetBucketConfiguration() *BucketConfiguration {
+	config := &BucketConfiguration{
+		NoncurrentVersionExpirationDays: DefaultNoncurrentVersionExpirationDays,
+	}
+
+	var project *BucketConfiguration
+
+	if e.project != nil {
+		project = e.project.Bucket
+	}
+
+	for _, c := range []*BucketConfiguration{project, e.Bucket} {
+		if c == nil {
+			continue
+		}
+
+		if c.Name != "" {
+			config.Name = c.Name
+		}
+
+		if c.KMSKeyID != "" {
+			config.KMSKeyID = c.KMSKeyID
+		}
+
+		if c.NoncurrentVersionExpirationDays > 0 {
+			config.NoncurrentVersionExpirationDays = c.NoncurrentVersionExpirationDays
+		}
+	}
+
+	return config
+}
@@ -4,9 +4,34 @@ import (
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/config"
 	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"github.com/bernos/ecso/pkg/ecso/ui"
 	"gopkg.in/urfave/cli.v1"
 )
 
+// GlobalFlags are available to all ecso commands, and must be given before
+// the command name
+var GlobalFlags = struct {
+	Output   cli.StringFlag
+	Template cli.StringFlag
+	Timeout  cli.DurationFlag
+}{
+	Output: cli.StringFlag{
+		Name:   "output",
+		Usage:  "The format to write command results in. One of table, json, yaml or template",
+		EnvVar: "ECSO_OUTPUT",
+		Value:  ui.OutputTable,
+	},
+	Template: cli.StringFlag{
+		Name:  "template",
+		Usage: "A go template used to render command results, for example '{{.URL}}'. Implies --output template",
+	},
+	Timeout: cli.DurationFlag{
+		Name:   "timeout",
+		Usage:  "The maximum time to allow a command to run for, for example 30m. Commands that time out exit with status 2. Defaults to no timeout",
+		EnvVar: "ECSO_TIMEOUT",
+	},
+}
+
 // NewApp creates a new `cli.App` interface for the ecso command line utility
 func NewApp(cfg *config.Config, project *ecso.Project, dispatcher dispatcher.Dispatcher) *cli.App {
 	app := cli.NewApp()
@@ -22,10 +47,21 @@ func NewApp(cfg *config.Config, project *ecso.Project, dispatcher dispatcher.Dis
 
 	cli.ErrWriter = cfg.ErrWriter()
 
+	app.Flags = []cli.Flag{
+		GlobalFlags.Output,
+		GlobalFlags.Template,
+		GlobalFlags.Timeout,
+	}
+
 	app.Commands = []cli.Command{
 		NewInitCliCommand(project, dispatcher),
 		NewEnvironmentCliCommand(project, dispatcher),
 		NewServiceCliCommand(project, dispatcher),
+		NewApplyCliCommand(project, dispatcher),
+		NewHistoryCliCommand(project, dispatcher),
+		NewLockCliCommand(project, dispatcher),
+		NewTemplatesCliCommand(project, dispatcher),
+		NewBucketCliCommand(project, dispatcher),
 		NewEnvCliCommand(project, dispatcher),
 	}
 
@@ -44,15 +80,43 @@ func MakeEcsoCommandFactory(ctx *cli.Context, fn CommandFactory) dispatcher.Comm
 
 // MakeAction is a factory func for generating wrapped ecso.Commands compatible
 // with the urfave/cli command line interface semantics and types
-func MakeAction(dispatcher dispatcher.Dispatcher, factory CommandFactory, options ...func(*dispatcher.DispatchOptions)) func(*cli.Context) error {
+func MakeAction(d dispatcher.Dispatcher, factory CommandFactory, options ...func(*dispatcher.DispatchOptions)) func(*cli.Context) error {
 	return func(ctx *cli.Context) error {
-		if err := dispatcher.Dispatch(MakeEcsoCommandFactory(ctx, factory), options...); err != nil {
+		opts := append([]func(*dispatcher.DispatchOptions){
+			dispatcher.WithOutputFormat(outputFormat(ctx), ctx.GlobalString(GlobalFlags.Template.Name)),
+			dispatcher.WithTimeout(ctx.GlobalDuration(GlobalFlags.Timeout.Name)),
+		}, options...)
+
+		if err := d.Dispatch(MakeEcsoCommandFactory(ctx, factory), opts...); err != nil {
 			if ecso.IsArgumentRequiredError(err) || ecso.IsOptionRequiredError(err) {
 				cli.ShowSubcommandHelp(ctx)
 			}
 
-			return cli.NewExitError(err.Error(), 1)
+			return cli.NewExitError(err.Error(), exitCode(err))
 		}
 		return nil
 	}
 }
+
+// outputFormat returns the output format requested by the user. Providing a
+// template without an explicit output format selects the template format
+func outputFormat(ctx *cli.Context) string {
+	if ctx.GlobalString(GlobalFlags.Template.Name) != "" && !ctx.GlobalIsSet(GlobalFlags.Output.Name) {
+		return ui.OutputTemplate
+	}
+
+	return ctx.GlobalString(GlobalFlags.Output.Name)
+}
+
+// exitCode maps an error returned from an ecso.Command to a process exit code
+func exitCode(err error) int {
+	if ecso.IsTimeoutError(err) {
+		return 2
+	}
+
+	if ecso.IsDeploymentFailedError(err) {
+		return 3
+	}
+
+	return 1
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewApplyCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Yes cli.BoolFlag
+	}{
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt if the environment is protected. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		file := ctx.Args().First()
+
+		if file == "" {
+			return nil, ecso.NewArgumentRequiredError("plan file")
+		}
+
+		plan, err := api.LoadPlan(file)
+		if err != nil {
+			return nil, err
+		}
+
+		return commands.NewApplyCommand(plan, cfg.ServiceAPI(plan.Region)).
+			WithYes(ctx.Bool(flags.Yes.Name)), nil
+	}
+
+	return cli.Command{
+		Name:        "apply",
+		Usage:       "Deploy a plan created by `ecso service plan`",
+		Description: "Executes exactly the change set recorded in the plan file. The plan is refused if the service stack has been updated since the plan was made, or if the change set is no longer available, in which case a new plan must be created.",
+		ArgsUsage:   "PLANFILE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Yes,
+		},
+	}
+}
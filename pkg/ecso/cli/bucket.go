@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewBucketCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	return cli.Command{
+		Name:  "bucket",
+		Usage: "Manage the S3 bucket that ecso stores deployments in",
+		Subcommands: []cli.Command{
+			NewBucketCheckCliCommand(project, dispatcher),
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewBucketCheckCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewBucketCheckCommand(env.Name, cfg.EnvironmentAPI(env.Region))
+		})
+	}
+
+	return cli.Command{
+		Name:        "check",
+		Usage:       "Audit the settings of the bucket used by an environment",
+		Description: "Checks that the ecso bucket used by the environment has default encryption, blocks public access, has versioning enabled, and expires old versions of objects, as set by the Bucket configuration in project.json. Exits with an error if any of the checks fail.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+	}
+}
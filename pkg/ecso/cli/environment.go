@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/config"
 	"github.com/bernos/ecso/pkg/ecso/dispatcher"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -19,7 +21,12 @@ func NewEnvironmentCliCommand(project *ecso.Project, dispatcher dispatcher.Dispa
 			NewEnvironmentUpCliCommand(project, dispatcher),
 			NewEnvironmentRmCliCommand(project, dispatcher),
 			NewEnvironmentDescribeCliCommand(project, dispatcher),
+			NewEnvironmentDriftCliCommand(project, dispatcher),
+			NewEnvironmentUpgradeTemplatesCliCommand(project, dispatcher),
 			NewEnvironmentDownCliCommand(project, dispatcher),
+			NewEnvironmentProtectCliCommand(project, dispatcher),
+			NewEnvironmentUnprotectCliCommand(project, dispatcher),
+			NewEnvironmentVersionsCliCommand(project, dispatcher),
 		},
 	}
 }
@@ -41,3 +48,22 @@ func makeEnvironmentCommand(c *cli.Context, project *ecso.Project, fn func(*ecso
 
 	return fn(project.Environments[name]), nil
 }
+
+// makeEnvironmentAPIs creates an api for each environment in the project,
+// keyed by environment name, or for just the named environment if name is not
+// empty
+func makeEnvironmentAPIs(project *ecso.Project, cfg *config.Config, name string) (map[string]api.EnvironmentAPI, error) {
+	environmentAPIs := make(map[string]api.EnvironmentAPI)
+
+	for _, env := range project.Environments {
+		if name == "" || env.Name == name {
+			environmentAPIs[env.Name] = cfg.EnvironmentAPI(env.Region)
+		}
+	}
+
+	if name != "" && len(environmentAPIs) == 0 {
+		return nil, fmt.Errorf("Environment '%s' does not exist in the project", name)
+	}
+
+	return environmentAPIs, nil
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewEnvironmentDriftCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewEnvironmentDriftCommand(env.Name, cfg.EnvironmentAPI(env.Region))
+		})
+	}
+
+	return cli.Command{
+		Name:        "drift",
+		Usage:       "Detect changes made to an environment outside of ecso",
+		Description: "Runs CloudFormation drift detection on the environment stack and all of its nested stacks, and lists the resources that have been modified or deleted since they were deployed, along with the properties that differ. Also reports any deployed templates that no longer match the local environment templates.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+	}
+}
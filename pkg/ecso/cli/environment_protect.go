@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewEnvironmentProtectCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewEnvironmentProtectCommand(env.Name, cfg.EnvironmentAPI(env.Region))
+		})
+	}
+
+	return cli.Command{
+		Name:        "protect",
+		Usage:       "Protects an ecso environment from being taken down",
+		Description: "Enables CloudFormation termination protection on the environment stack, and marks the environment as Protected in the .ecso/project.json file. Protected environments cannot be removed with `environment down`, `environment rm` or `service down`, and commands that change them require the environment name to be typed to confirm. The --yes option skips confirmation, but only for the IAM principals listed in the environment's AllowedPrincipals. Use `environment unprotect` to lift protection.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewEnvironmentUnprotectCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Yes cli.BoolFlag
+	}{
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewEnvironmentUnprotectCommand(env.Name, cfg.EnvironmentAPI(env.Region)).
+				WithYes(ctx.Bool(flags.Yes.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "unprotect",
+		Usage:       "Lifts protection from an ecso environment",
+		Description: "Disables CloudFormation termination protection on the environment stack, and clears the Protected flag in the .ecso/project.json file, so that the environment can be taken down.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Yes,
+		},
+	}
+}
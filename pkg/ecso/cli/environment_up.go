@@ -12,23 +12,48 @@ func NewEnvironmentUpCliCommand(project *ecso.Project, dispatcher dispatcher.Dis
 	flags := struct {
 		DryRun cli.BoolFlag
 		Force  cli.BoolFlag
+		Yes    cli.BoolFlag
+		Param  cli.StringSliceFlag
+		Tag    cli.StringSliceFlag
+		Save   cli.BoolFlag
+		Prune  cli.BoolFlag
 	}{
 
 		DryRun: cli.BoolFlag{
 			Name:  "dry-run",
-			Usage: "If set, list pending changes, but do not execute the updates.",
+			Usage: "If set, list pending changes, but do not execute the updates. Resources, lambda functions and the deployment manifest are not uploaded, and the stack template is removed from the ecso bucket once the changes are listed. Use `ecso service plan` to preview a service deployment",
 		},
 		Force: cli.BoolFlag{
 			Name:  "force",
 			Usage: "Override warnings about first time environment deployments if cloud formation stack already exists",
 		},
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt if the environment is protected. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+		Param: makeParamFlag(),
+		Tag:   makeTagFlag(),
+		Save:  makeSaveFlag(),
+		Prune: cli.BoolFlag{
+			Name:  "prune-resources",
+			Usage: "Delete resources from S3 that are no longer in the environment's resource dir. Ignored on a dry run",
+		},
 	}
 
 	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		params, tags, err := parseOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
 			return commands.NewEnvironmentUpCommand(env.Name, cfg.EnvironmentAPI(env.Region)).
 				WithDryRun(ctx.Bool(flags.DryRun.Name)).
-				WithForce(ctx.Bool(flags.Force.Name))
+				WithForce(ctx.Bool(flags.Force.Name)).
+				WithYes(ctx.Bool(flags.Yes.Name)).
+				WithOverrides(params, tags).
+				WithSave(ctx.Bool(flags.Save.Name)).
+				WithPruneResources(ctx.Bool(flags.Prune.Name))
 		})
 	}
 
@@ -41,6 +66,11 @@ func NewEnvironmentUpCliCommand(project *ecso.Project, dispatcher dispatcher.Dis
 		Flags: []cli.Flag{
 			flags.DryRun,
 			flags.Force,
+			flags.Yes,
+			flags.Param,
+			flags.Tag,
+			flags.Save,
+			flags.Prune,
 		},
 	}
 }
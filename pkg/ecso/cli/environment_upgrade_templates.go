@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewEnvironmentUpgradeTemplatesCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		DryRun cli.BoolFlag
+	}{
+		DryRun: cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Report how each file would be upgraded, without changing any files",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewEnvironmentUpgradeTemplatesCommand(env.Name, cfg.EnvironmentAPI(env.Region)).
+				WithDryRun(ctx.Bool(flags.DryRun.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "upgrade-templates",
+		Usage:       "Upgrade the environment templates to the versions in this release of ecso",
+		Description: "Merges the changes between the templates that ecso generated when the environment was created and the templates in this release of ecso in to the environment's templates, keeping any changes you have made. Files where both sets of changes overlap are written with conflict markers, which must be resolved before running `environment up`. Files generated by older releases of ecso can't be merged, so the new version is written alongside them with a .upgrade extension.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.DryRun,
+		},
+	}
+}
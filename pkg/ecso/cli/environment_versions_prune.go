@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewEnvironmentVersionsPruneCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Keep   cli.IntFlag
+		DryRun cli.BoolFlag
+	}{
+		Keep: cli.IntFlag{
+			Name:  "keep",
+			Usage: "The number of most recent versions to keep",
+		},
+		DryRun: cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "If set, list the versions that would be deleted, but do not delete them.",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewEnvironmentVersionsPruneCommand(env.Name, cfg.EnvironmentAPI(env.Region)).
+				WithKeep(ctx.Int(flags.Keep.Name)).
+				WithDryRun(ctx.Bool(flags.DryRun.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "prune",
+		Usage:       "Delete old versions of an environment from the ecso bucket",
+		Description: "Deletes the deployment packages of all but the newest --keep versions of an environment. The currently deployed version, and the last successfully deployed version before it, are never deleted.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Keep,
+			flags.DryRun,
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewHistoryCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+		Service     cli.StringFlag
+		Since       cli.StringFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:  "environment",
+			Usage: "Only show changes to this environment. Defaults to all environments",
+		},
+		Service: cli.StringFlag{
+			Name:  "service",
+			Usage: "Only show changes to this service",
+		},
+		Since: cli.StringFlag{
+			Name:  "since",
+			Usage: "Only show changes made since a time. Either a duration such as 24h, a date such as 2017-03-01, or an RFC3339 timestamp",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		since, err := parseSince(ctx.String(flags.Since.Name), time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		environmentAPIs, err := makeEnvironmentAPIs(project, cfg, ctx.String(flags.Environment.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		return commands.NewHistoryCommand(environmentAPIs).
+			WithService(ctx.String(flags.Service.Name)).
+			WithSince(since), nil
+	}
+
+	return cli.Command{
+		Name:        "history",
+		Usage:       "Show who deployed what, when, and whether it succeeded",
+		Description: "Lists the changes made by ecso service up, rollback, promote, apply and down, and by ecso environment up and down, newest first. Each change records the IAM principal that made it, the version and git commit deployed, how long it took, and its outcome. The history of each environment is kept in its ecso bucket.",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+			flags.Service,
+			flags.Since,
+		},
+	}
+}
+
+// parseSince parses the value of a --since flag, which may be a duration
+// before now, a date or an RFC3339 timestamp. An empty value returns the
+// zero time
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid --since value '%s'. Use a duration such as 24h, a date such as 2017-03-01, or an RFC3339 timestamp", value)
+}
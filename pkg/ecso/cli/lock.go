@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewLockCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	return cli.Command{
+		Name:  "lock",
+		Usage: "Manage the locks that prevent concurrent deployments",
+		Subcommands: []cli.Command{
+			NewLockLsCliCommand(project, dispatcher),
+			NewLockBreakCliCommand(project, dispatcher),
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewLockBreakCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Service cli.StringFlag
+		Force   cli.BoolFlag
+	}{
+		Service: cli.StringFlag{
+			Name:  "service",
+			Usage: "Break the lock on this service, rather than on the environment",
+		},
+		Force: cli.BoolFlag{
+			Name:  "force",
+			Usage: "Break the lock even if it has not expired",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeEnvironmentCommand(ctx, project, func(env *ecso.Environment) ecso.Command {
+			return commands.NewLockBreakCommand(env.Name, cfg.EnvironmentAPI(env.Region)).
+				WithService(ctx.String(flags.Service.Name)).
+				WithForce(ctx.Bool(flags.Force.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "break",
+		Usage:       "Remove a stale lock on an environment or service",
+		Description: "Removes the lock on the environment, or on a service in the environment if --service is given. Locks that are still being renewed by a running ecso process are only removed with --force.",
+		ArgsUsage:   "ENVIRONMENT",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Service,
+			flags.Force,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewLockLsCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:  "environment",
+			Usage: "Only show locks in this environment. Defaults to all environments",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		environmentAPIs, err := makeEnvironmentAPIs(project, cfg, ctx.String(flags.Environment.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		return commands.NewLockLsCommand(environmentAPIs), nil
+	}
+
+	return cli.Command{
+		Name:        "ls",
+		Usage:       "List the locks held on environments and services",
+		Description: "ecso environment up and down lock the environment, and ecso service up, rollback, promote, apply and down lock the service in the environment being changed, so that two people can't change the same thing at once. Services can't be changed while the environment is locked. Locks are renewed while the command runs, and released when it exits. The locks of a process that was killed expire after two minutes.",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+		},
+	}
+}
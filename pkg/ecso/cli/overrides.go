@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func makeParamFlag() cli.StringSliceFlag {
+	return cli.StringSliceFlag{
+		Name:  "param",
+		Usage: "Override a cloudformation parameter for this deployment, as Key=Value. Takes precedence over the parameters in project.json. May be repeated",
+	}
+}
+
+func makeTagFlag() cli.StringSliceFlag {
+	return cli.StringSliceFlag{
+		Name:  "tag",
+		Usage: "Override a cloudformation stack tag for this deployment, as Key=Value. Takes precedence over the tags in project.json. May be repeated",
+	}
+}
+
+func makeSaveFlag() cli.BoolFlag {
+	return cli.BoolFlag{
+		Name:  "save",
+		Usage: "If set, save the --param and --tag overrides to project.json once the deployment succeeds",
+	}
+}
+
+// parseKeyValues parses the Key=Value pairs given for a repeatable flag
+func parseKeyValues(flag string, values []string) (map[string]string, error) {
+	pairs := make(map[string]string)
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("Invalid value '%s' for --%s. Expected Key=Value", value, flag)
+		}
+
+		pairs[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	return pairs, nil
+}
+
+// parseOverrides parses the --param and --tag flags of a command
+func parseOverrides(ctx *cli.Context) (params, tags map[string]string, err error) {
+	if params, err = parseKeyValues("param", ctx.StringSlice("param")); err != nil {
+		return nil, nil, err
+	}
+
+	if tags, err = parseKeyValues("tag", ctx.StringSlice("tag")); err != nil {
+		return nil, nil, err
+	}
+
+	return params, tags, nil
+}
@@ -15,14 +15,20 @@ func NewServiceCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatche
 		Subcommands: []cli.Command{
 			NewServiceAddCliCommand(project, dispatcher),
 			NewServiceUpCliCommand(project, dispatcher),
+			NewServicePlanCliCommand(project, dispatcher),
 			NewServiceDownCliCommand(project, dispatcher),
 			NewServiceLsCliCommand(project, dispatcher),
 			NewServicePsCliCommand(project, dispatcher),
+			NewServiceEndpointsCliCommand(project, dispatcher),
 			NewServiceEventsCliCommand(project, dispatcher),
 			NewServiceLogsCliCommand(project, dispatcher),
 			NewServiceDescribeCliCommand(project, dispatcher),
+			NewServiceDriftCliCommand(project, dispatcher),
+			NewServiceUpgradeTemplatesCliCommand(project, dispatcher),
 			NewServiceRollbackCliCommand(project, dispatcher),
+			NewServicePromoteCliCommand(project, dispatcher),
 			NewServiceVersionsCliCommand(project, dispatcher),
+			NewServiceWaitCliCommand(project, dispatcher),
 		},
 	}
 }
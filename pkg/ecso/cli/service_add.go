@@ -8,6 +8,7 @@ import (
 	"github.com/bernos/ecso/pkg/ecso/commands"
 	"github.com/bernos/ecso/pkg/ecso/config"
 	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"github.com/bernos/ecso/pkg/ecso/resources"
 	"github.com/bernos/ecso/pkg/ecso/ui"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -16,6 +17,8 @@ var ServiceAddFlags = struct {
 	DesiredCount cli.IntFlag
 	Route        cli.StringFlag
 	Port         cli.IntFlag
+	Template     cli.StringFlag
+	Var          cli.StringSliceFlag
 }{
 
 	DesiredCount: cli.IntFlag{
@@ -30,6 +33,14 @@ var ServiceAddFlags = struct {
 		Name:  "port",
 		Usage: "If set, the loadbalancer will bind to this port of the web container in this service",
 	},
+	Template: cli.StringFlag{
+		Name:  "template",
+		Usage: "The template pack to create the service from. Either the path to a template pack dir, or the name of a built-in pack or a pack on the project's TemplatePath. Run `ecso templates ls` to list the available packs",
+	},
+	Var: cli.StringSliceFlag{
+		Name:  "var",
+		Usage: "Set a template pack variable, as Name=Value. Variables that are not set are prompted for. May be repeated",
+	},
 }
 
 func NewServiceAddCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
@@ -48,6 +59,8 @@ func NewServiceAddCliCommand(project *ecso.Project, dispatcher dispatcher.Dispat
 			ServiceAddFlags.DesiredCount,
 			ServiceAddFlags.Route,
 			ServiceAddFlags.Port,
+			ServiceAddFlags.Template,
+			ServiceAddFlags.Var,
 		},
 	}
 }
@@ -66,8 +79,14 @@ func (wrapper *serviceAddCommandWrapper) Execute(ctx *ecso.CommandContext, r io.
 		desiredCount = wrapper.cliCtx.Int(ServiceAddFlags.DesiredCount.Name)
 		route        = wrapper.cliCtx.String(ServiceAddFlags.Route.Name)
 		port         = wrapper.cliCtx.Int(ServiceAddFlags.Port.Name)
+		templateName = wrapper.cliCtx.String(ServiceAddFlags.Template.Name)
 	)
 
+	variables, err := parseKeyValues(ServiceAddFlags.Var.Name, wrapper.cliCtx.StringSlice(ServiceAddFlags.Var.Name))
+	if err != nil {
+		return err
+	}
+
 	var prompts = struct {
 		Name         string
 		DesiredCount string
@@ -90,12 +109,26 @@ func (wrapper *serviceAddCommandWrapper) Execute(ctx *ecso.CommandContext, r io.
 		return err
 	}
 
-	webChoice, err := ui.Choice(r, w, "Is this a web service?", []string{"Yes", "No"})
-	if err != nil {
-		return err
+	var pack *resources.TemplatePack
+
+	if templateName != "" {
+		if pack, err = resources.FindTemplatePack(templateName, ctx.Project.TemplateSearchPath()); err != nil {
+			return err
+		}
+	}
+
+	isWeb := pack != nil && pack.Web
+
+	if pack == nil {
+		webChoice, err := ui.Choice(r, w, "Is this a web service?", []string{"Yes", "No"})
+		if err != nil {
+			return err
+		}
+
+		isWeb = webChoice == 0
 	}
 
-	if webChoice == 0 {
+	if isWeb {
 		if err := ui.AskStringIfEmptyVar(r, w, &route, prompts.Route, "/"+serviceName, routeValidator()); err != nil {
 			return err
 		}
@@ -105,10 +138,36 @@ func (wrapper *serviceAddCommandWrapper) Execute(ctx *ecso.CommandContext, r io.
 		}
 	}
 
+	if pack != nil {
+		for _, v := range pack.Variables {
+			if _, ok := variables[v.Name]; ok {
+				continue
+			}
+
+			prompt := v.Prompt
+			if prompt == "" {
+				prompt = fmt.Sprintf("What is the value of %s?", v.Name)
+			}
+
+			value, err := ui.AskString(r, w, prompt, v.Default, ui.ValidateAny())
+			if err != nil {
+				return err
+			}
+
+			variables[v.Name] = value
+		}
+	}
+
 	cmd := commands.NewServiceAddCommand(serviceName).
 		WithDesiredCount(desiredCount).
 		WithRoute(route).
-		WithPort(port)
+		WithPort(port).
+		WithTemplate(pack).
+		WithVariables(variables)
+
+	if err := cmd.Validate(ctx); err != nil {
+		return err
+	}
 
 	if err := cmd.Execute(ctx, r, w); err != nil {
 		return err
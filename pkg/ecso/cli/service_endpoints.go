@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServiceEndpointsCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+		Clean       cli.BoolFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:   "environment",
+			Usage:  "The name of the environment",
+			EnvVar: "ECSO_ENVIRONMENT",
+		},
+		Clean: cli.BoolFlag{
+			Name:  "clean",
+			Usage: "If set, delete SRV records that have no matching running container",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
+			return commands.NewServiceEndpointsCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region)).
+				WithClean(ctx.Bool(flags.Clean.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "endpoints",
+		Usage:       "Show the discovered endpoints of a service",
+		Description: "Lists the host:port of each SRV record registered for the service, and the running container it belongs to. Records with no matching running container are flagged as stale, and can be deleted with --clean. Records in Cloud Map namespaces are managed by ECS and are never deleted.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+			flags.Clean,
+		},
+	}
+}
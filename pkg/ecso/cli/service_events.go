@@ -11,27 +11,35 @@ import (
 func NewServiceEventsCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
 	flags := struct {
 		Environment cli.StringFlag
+		UntilStable cli.BoolFlag
 	}{
 		Environment: cli.StringFlag{
 			Name:   "environment",
 			Usage:  "The name of the environment",
 			EnvVar: "ECSO_ENVIRONMENT",
 		},
+		UntilStable: cli.BoolFlag{
+			Name:  "until-stable",
+			Usage: "If set, exit once the service has a single PRIMARY deployment and its running count equals its desired count",
+		},
 	}
 
 	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
 		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
-			return commands.NewServiceEventsCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region))
+			return commands.NewServiceEventsCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region)).
+				WithUntilStable(ctx.Bool(flags.UntilStable.Name))
 		})
 	}
 
 	return cli.Command{
-		Name:      "events",
-		Usage:     "List ECS events for a service",
-		ArgsUsage: "SERVICE",
-		Action:    MakeAction(dispatcher, fn),
+		Name:        "events",
+		Usage:       "List ECS events for a service",
+		Description: "Events are streamed until the command is interrupted, or until the service is stable if --until-stable is set.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
 		Flags: []cli.Flag{
 			flags.Environment,
+			flags.UntilStable,
 		},
 	}
 }
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServicePlanCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+		Out         cli.StringFlag
+		Version     cli.StringFlag
+		SkipBuild   cli.BoolFlag
+		Param       cli.StringSliceFlag
+		Tag         cli.StringSliceFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:   "environment",
+			Usage:  "The name of the environment to plan the deployment for",
+			EnvVar: "ECSO_ENVIRONMENT",
+		},
+		Out: cli.StringFlag{
+			Name:  "out",
+			Usage: "The file to write the plan to",
+		},
+		Version: cli.StringFlag{
+			Name:  "version",
+			Usage: "The version label to deploy as. Defaults to a label generated by the project's VersionStrategy",
+		},
+		SkipBuild: cli.BoolFlag{
+			Name:  "skip-build",
+			Usage: "If set, do not build images for containers with a build section in the compose file. The image most recently pushed to ECR for each container is deployed instead",
+		},
+		Param: makeParamFlag(),
+		Tag:   makeTagFlag(),
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		if ctx.String(flags.Out.Name) == "" {
+			return nil, ecso.NewOptionRequiredError(flags.Out.Name)
+		}
+
+		params, tags, err := parseOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
+			return commands.NewServicePlanCommand(service.Name, env.Name, ctx.String(flags.Out.Name), cfg.ServiceAPI(env.Region)).
+				WithVersion(ctx.String(flags.Version.Name)).
+				WithSkipBuild(ctx.Bool(flags.SkipBuild.Name)).
+				WithOverrides(params, tags)
+		})
+	}
+
+	return cli.Command{
+		Name:        "plan",
+		Usage:       "Prepare a deployment of a service without executing it",
+		Description: "Builds and pushes images, registers the task definition, uploads the deployment package and creates a CloudFormation change set for the service stack, exactly as `ecso service up` would, including any --param and --tag overrides, but does not execute the change set. The change set ID, package location and a summary of the changes are written to the --out file, which can be reviewed and then deployed with `ecso apply`. If the service stack is already up to date, no plan file is written. Unexecuted change sets from earlier plans are deleted.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+			flags.Out,
+			flags.Version,
+			flags.SkipBuild,
+			flags.Param,
+			flags.Tag,
+		},
+	}
+}
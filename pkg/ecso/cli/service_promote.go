@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServicePromoteCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		From    cli.StringFlag
+		To      cli.StringFlag
+		Version cli.StringFlag
+		Yes     cli.BoolFlag
+	}{
+		From: cli.StringFlag{
+			Name:  "from",
+			Usage: "The name of the environment to promote from",
+		},
+		To: cli.StringFlag{
+			Name:  "to",
+			Usage: "The name of the environment to promote to",
+		},
+		Version: cli.StringFlag{
+			Name:  "version",
+			Usage: "The version to promote. Defaults to the version currently deployed to the --from environment",
+		},
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt if the environment is protected. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		var (
+			name = ctx.Args().First()
+			from = ctx.String(flags.From.Name)
+			to   = ctx.String(flags.To.Name)
+		)
+
+		if name == "" {
+			return nil, ecso.NewArgumentRequiredError("service")
+		}
+
+		if from == "" {
+			return nil, ecso.NewOptionRequiredError(flags.From.Name)
+		}
+
+		if to == "" {
+			return nil, ecso.NewOptionRequiredError(flags.To.Name)
+		}
+
+		if !project.HasService(name) {
+			return nil, fmt.Errorf("Service '%s' does not exist in the project", name)
+		}
+
+		for _, env := range []string{from, to} {
+			if !project.HasEnvironment(env) {
+				return nil, fmt.Errorf("Environment '%s' does not exist in the project", env)
+			}
+		}
+
+		return commands.NewServicePromoteCommand(
+			name,
+			from,
+			to,
+			cfg.ServiceAPI(project.Environments[from].Region),
+			cfg.ServiceAPI(project.Environments[to].Region)).
+			WithVersion(ctx.String(flags.Version.Name)).
+			WithYes(ctx.Bool(flags.Yes.Name)), nil
+	}
+
+	return cli.Command{
+		Name:        "promote",
+		Usage:       "Promote a tested version of a service from one environment to another",
+		Description: "Deploys exactly the same container images as a version that was successfully deployed to the --from environment. The images are taken from the source version's deployment manifest, pinned by digest where one was recorded, while the task definition environment and CloudFormation parameters come from the --to environment. The new version keeps the source version's label, and records which environment it was promoted from.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.From,
+			flags.To,
+			flags.Version,
+			flags.Yes,
+		},
+	}
+}
@@ -12,6 +12,10 @@ func NewServiceRollbackCliCommand(project *ecso.Project, dispatcher dispatcher.D
 	flags := struct {
 		Environment cli.StringFlag
 		Version     cli.StringFlag
+		Yes         cli.BoolFlag
+		Param       cli.StringSliceFlag
+		Tag         cli.StringSliceFlag
+		Save        cli.BoolFlag
 	}{
 		Environment: cli.StringFlag{
 			Name:   "environment",
@@ -22,27 +26,46 @@ func NewServiceRollbackCliCommand(project *ecso.Project, dispatcher dispatcher.D
 			Name:  "version",
 			Usage: "The version to rollback to",
 		},
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt if the environment is protected. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+		Param: makeParamFlag(),
+		Tag:   makeTagFlag(),
+		Save:  makeSaveFlag(),
 	}
 
 	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		params, tags, err := parseOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
 			return commands.NewServiceRollbackCommand(
 				service.Name,
 				env.Name,
 				ctx.String(flags.Version.Name),
-				cfg.ServiceAPI(env.Region))
+				cfg.ServiceAPI(env.Region)).
+				WithYes(ctx.Bool(flags.Yes.Name)).
+				WithOverrides(params, tags).
+				WithSave(ctx.Bool(flags.Save.Name))
 		})
 	}
 
 	return cli.Command{
 		Name:        "rollback",
 		Usage:       "Rollback a service to an earlier version",
-		Description: "Replace the currently running service with a previously deployed service version",
+		Description: "Replace the currently running service with a previously deployed service version. Any --param and --tag overrides are merged over the parameters and tags the version was packaged with, and deployed from a copy of the version's package",
 		ArgsUsage:   "SERVICE",
 		Action:      MakeAction(dispatcher, fn),
 		Flags: []cli.Flag{
 			flags.Environment,
 			flags.Version,
+			flags.Yes,
+			flags.Param,
+			flags.Tag,
+			flags.Save,
 		},
 	}
 }
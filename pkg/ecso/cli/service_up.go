@@ -11,28 +11,65 @@ import (
 func NewServiceUpCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
 	flags := struct {
 		Environment cli.StringFlag
+		Version     cli.StringFlag
+		SkipBuild   cli.BoolFlag
+		Yes         cli.BoolFlag
+		Param       cli.StringSliceFlag
+		Tag         cli.StringSliceFlag
+		Save        cli.BoolFlag
 	}{
 		Environment: cli.StringFlag{
 			Name:   "environment",
 			Usage:  "The name of the environment to deploy to",
 			EnvVar: "ECSO_ENVIRONMENT",
 		},
+		Version: cli.StringFlag{
+			Name:  "version",
+			Usage: "The version label to deploy as. Defaults to a label generated by the project's VersionStrategy",
+		},
+		SkipBuild: cli.BoolFlag{
+			Name:  "skip-build",
+			Usage: "If set, do not build images for containers with a build section in the compose file. The image most recently pushed to ECR for each container is deployed instead",
+		},
+		Yes: cli.BoolFlag{
+			Name:  "yes",
+			Usage: "Skip the confirmation prompt if the environment is protected. Only permitted for the IAM principals listed in the environment's AllowedPrincipals",
+		},
+		Param: makeParamFlag(),
+		Tag:   makeTagFlag(),
+		Save:  makeSaveFlag(),
 	}
 
 	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		params, tags, err := parseOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
-			return commands.NewServiceUpCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region))
+			return commands.NewServiceUpCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region)).
+				WithVersion(ctx.String(flags.Version.Name)).
+				WithSkipBuild(ctx.Bool(flags.SkipBuild.Name)).
+				WithYes(ctx.Bool(flags.Yes.Name)).
+				WithOverrides(params, tags).
+				WithSave(ctx.Bool(flags.Save.Name))
 		})
 	}
 
 	return cli.Command{
 		Name:        "up",
 		Usage:       "Deploy a service",
-		Description: "The service's docker-compose file will be transformed into an ECS task definition, and registered with ECS. The service CloudFormation template will be deployed. Service deployment policies and constraints can be set in the service CloudFormation templates. By default a rolling deployment is performed, with the number of services running at any time equal to at least the desired service count, and at most 200% of the desired service count.",
+		Description: "The service's docker-compose file will be transformed into an ECS task definition, and registered with ECS. The service CloudFormation template will be deployed. Service deployment policies and constraints can be set in the service CloudFormation templates. By default a rolling deployment is performed, with the number of services running at any time equal to at least the desired service count, and at most 200% of the desired service count. Each deployment is labelled with a version, which must not already exist for the service in the environment. Labels are generated according to the VersionStrategy setting in project.json, which may be 'timestamp' (the default), 'git-sha', 'git-describe', or a template combining {{.Timestamp}}, {{.GitSHA}}, {{.GitDescribe}} and {{.GitBranch}}. Containers with a build section in the compose file are built with the docker cli, tagged with a hash of their build context, and pushed to an ECR repository named <project>/<service>/<container>, which is created if necessary. Images are only rebuilt when their build context changes. To see the changes a deployment would make without executing it, use `ecso service plan`, which is the dry run for services.",
 		ArgsUsage:   "SERVICE",
 		Action:      MakeAction(dispatcher, fn),
 		Flags: []cli.Flag{
 			flags.Environment,
+			flags.Version,
+			flags.SkipBuild,
+			flags.Yes,
+			flags.Param,
+			flags.Tag,
+			flags.Save,
 		},
 	}
 }
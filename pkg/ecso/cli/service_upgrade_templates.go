@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServiceUpgradeTemplatesCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		DryRun cli.BoolFlag
+	}{
+		DryRun: cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Report how each file would be upgraded, without changing any files",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		name := ctx.Args().First()
+
+		if name == "" {
+			return nil, ecso.NewArgumentRequiredError("service")
+		}
+
+		if !project.HasService(name) {
+			return nil, fmt.Errorf("Service '%s' does not exist in the project", name)
+		}
+
+		return commands.NewServiceUpgradeTemplatesCommand(name).
+			WithDryRun(ctx.Bool(flags.DryRun.Name)), nil
+	}
+
+	return cli.Command{
+		Name:        "upgrade-templates",
+		Usage:       "Upgrade a service's files to the current version of its template pack",
+		Description: "Merges the changes between the files that were generated when the service was added and the current version of its template pack in to the service's files, keeping any changes you have made. Files where both sets of changes overlap are written with conflict markers, which must be resolved before running `service up`. Files generated by older releases of ecso can't be merged, so the new version is written alongside them with a .upgrade extension.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.DryRun,
+		},
+	}
+}
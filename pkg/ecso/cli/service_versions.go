@@ -33,5 +33,8 @@ func NewServiceVersionsCliCommand(project *ecso.Project, dispatcher dispatcher.D
 		Flags: []cli.Flag{
 			flags.Environment,
 		},
+		Subcommands: []cli.Command{
+			NewServiceVersionsPruneCliCommand(project, dispatcher),
+		},
 	}
 }
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServiceVersionsPruneCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+		Keep        cli.IntFlag
+		DryRun      cli.BoolFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:   "environment",
+			Usage:  "The name of the environment",
+			EnvVar: "ECSO_ENVIRONMENT",
+		},
+		Keep: cli.IntFlag{
+			Name:  "keep",
+			Usage: "The number of most recent versions to keep",
+		},
+		DryRun: cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "If set, list the versions that would be deleted, but do not delete them.",
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
+			return commands.NewServiceVersionsPruneCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region)).
+				WithKeep(ctx.Int(flags.Keep.Name)).
+				WithDryRun(ctx.Bool(flags.DryRun.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "prune",
+		Usage:       "Delete old versions of a service from the ecso bucket",
+		Description: "Deletes the deployment packages of all but the newest --keep versions of a service. The currently deployed version, and the last successfully deployed version before it, are never deleted so that rollbacks remain possible.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+			flags.Keep,
+			flags.DryRun,
+		},
+	}
+}
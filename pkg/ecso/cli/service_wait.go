@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewServiceWaitCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	flags := struct {
+		Environment cli.StringFlag
+		Timeout     cli.DurationFlag
+	}{
+		Environment: cli.StringFlag{
+			Name:   "environment",
+			Usage:  "The name of the environment",
+			EnvVar: "ECSO_ENVIRONMENT",
+		},
+		Timeout: cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "How long to wait for the service to become stable",
+			Value: time.Minute * 10,
+		},
+	}
+
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return makeServiceCommand(ctx, project, func(service *ecso.Service, env *ecso.Environment) ecso.Command {
+			return commands.NewServiceWaitCommand(service.Name, env.Name, cfg.ServiceAPI(env.Region)).
+				WithTimeout(ctx.Duration(flags.Timeout.Name))
+		})
+	}
+
+	return cli.Command{
+		Name:        "wait",
+		Usage:       "Wait for a service to become stable",
+		Description: "Blocks until the service has a single PRIMARY deployment, and its running count equals its desired count. Exits with status 0 once the service is stable, 2 if the timeout is reached, 3 if the deployment fails and 1 on any other failure. A deployment has failed once the ECS deployment circuit breaker rolls it back, or once its tasks have repeatedly failed to start.",
+		ArgsUsage:   "SERVICE",
+		Action:      MakeAction(dispatcher, fn),
+		Flags: []cli.Flag{
+			flags.Environment,
+			flags.Timeout,
+		},
+	}
+}
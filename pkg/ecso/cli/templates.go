@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewTemplatesCliCommand(project *ecso.Project, dispatcher dispatcher.Dispatcher) cli.Command {
+	return cli.Command{
+		Name:  "templates",
+		Usage: "Manage service template packs",
+		Subcommands: []cli.Command{
+			NewTemplatesLsCliCommand(project, dispatcher),
+		},
+	}
+}
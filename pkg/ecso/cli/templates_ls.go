@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/commands"
+	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/dispatcher"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func NewTemplatesLsCliCommand(project *ecso.Project, d dispatcher.Dispatcher) cli.Command {
+	fn := func(ctx *cli.Context, cfg *config.Config) (ecso.Command, error) {
+		return commands.NewTemplatesLsCommand(), nil
+	}
+
+	return cli.Command{
+		Name:        "ls",
+		Usage:       "List the available service template packs",
+		Description: "Lists the built-in template packs, and the packs found in the directories listed in the TemplatePath setting of the .ecso/project.json file. A template pack is a directory containing a template.json manifest, which declares the pack's Name, Description, whether it is a Web service, and any Variables with their Prompt and Default value. Every other file in the directory is rendered as a go template, with .Service, .Project and .Variables as data, and written to the same path in the new service's dir. Packs must contain a docker-compose.yaml and a cloudformation/stack.yaml file.",
+		Action:      MakeAction(d, fn, dispatcher.SkipEnsureProjectExists()),
+	}
+}
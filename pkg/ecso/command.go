@@ -1,6 +1,12 @@
 package ecso
 
-import "io"
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
 
 // Command represents a single ecso command
 type Command interface {
@@ -31,18 +37,28 @@ func CommandError(err error) Command {
 	})
 }
 
-// CommandContext provides access to configuration and preferences scoped to a running Command
+// CommandContext provides access to configuration and preferences scoped to a running Command.
+// The embedded context.Context is cancelled when the command is interrupted or times out, and
+// should be passed to any long running operation
 type CommandContext struct {
+	context.Context
+
 	EcsoVersion     string
 	Project         *Project
 	UserPreferences *UserPreferences
+
+	// Renderer writes command results in the output format requested by
+	// the user
+	Renderer ui.Renderer
 }
 
 // NewCommandContext creates a CommandContext
 func NewCommandContext(project *Project, preferences *UserPreferences, version string) *CommandContext {
 	return &CommandContext{
+		Context:         context.Background(),
 		Project:         project,
 		UserPreferences: preferences,
 		EcsoVersion:     version,
+		Renderer:        ui.NewTableRenderer(os.Stdout),
 	}
 }
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// NewApplyCommand creates a command that executes a plan created by
+// `ecso service plan`
+func NewApplyCommand(plan *api.Plan, serviceAPI api.ServiceAPI) *ApplyCommand {
+	return &ApplyCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            plan.Service,
+			environmentName: plan.Environment,
+			serviceAPI:      serviceAPI,
+		},
+		plan: plan,
+	}
+}
+
+type ApplyCommand struct {
+	*ServiceCommand
+
+	plan *api.Plan
+	yes  bool
+}
+
+// WithYes applies plans to protected environments without asking for
+// confirmation
+func (cmd *ApplyCommand) WithYes(yes bool) *ApplyCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+func (cmd *ApplyCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		project = ctx.Project
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	fmt.Fprintf(blue, "Applying plan for version '%s' of service '%s' to the '%s' environment", cmd.plan.Version, service.Name, env.Name)
+
+	cmd.plan.WriteTo(w)
+
+	if err := confirmProtectedEnvironment(env, cmd.yes, cmd.serviceAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+		return err
+	}
+
+	description, err := cmd.serviceAPI.ServiceApply(ctx, project, env, service, cmd.plan, w)
+	if err != nil {
+		return err
+	}
+
+	description.WriteTo(w)
+
+	fmt.Fprintf(green, "Deployed version '%s' of service '%s' to the '%s' environment", cmd.plan.Version, service.Name, env.Name)
+
+	return nil
+}
+
+func (cmd *ApplyCommand) Validate(ctx *ecso.CommandContext) error {
+	if cmd.plan.Project != ctx.Project.Name {
+		return fmt.Errorf("The plan was made for the '%s' project, not '%s'", cmd.plan.Project, ctx.Project.Name)
+	}
+
+	if !ctx.Project.HasEnvironment(cmd.plan.Environment) {
+		return fmt.Errorf("No environment named '%s' was found", cmd.plan.Environment)
+	}
+
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if region := cmd.Environment(ctx).Region; region != cmd.plan.Region {
+		return fmt.Errorf("The plan was made for the %s region, but the '%s' environment is in %s", cmd.plan.Region, cmd.plan.Environment, region)
+	}
+
+	if !cmd.plan.HasChanges() {
+		return fmt.Errorf("The plan contains no changes")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewBucketCheckCommand(environmentName string, environmentAPI api.EnvironmentAPI) ecso.Command {
+	return &bucketCheckCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type bucketCheckCommand struct {
+	*EnvironmentCommand
+}
+
+func (cmd *bucketCheckCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env  = cmd.Environment(ctx)
+		blue = ui.NewBannerWriter(w, ui.BlueBold)
+	)
+
+	bucket, err := cmd.environmentAPI.GetEcsoBucket(env)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(blue, "Checking the '%s' bucket used by the '%s' environment", bucket, env.Name)
+
+	checks, err := cmd.environmentAPI.CheckEcsoBucket(env)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Renderer.Render(checks); err != nil {
+		return err
+	}
+
+	if !checks.OK() {
+		return fmt.Errorf("The '%s' bucket does not match the bucket configuration. Buckets created by this version of ecso are configured automatically; older buckets must be updated by hand", bucket)
+	}
+
+	return nil
+}
@@ -26,7 +26,5 @@ func (cmd *environmentDescribeCommand) Execute(ctx *ecso.CommandContext, r io.Re
 		return err
 	}
 
-	description.WriteTo(w)
-
-	return nil
+	return ctx.Renderer.Render(description)
 }
@@ -38,7 +38,7 @@ func (cmd *EnvironmentDownCommand) Execute(ctx *ecso.CommandContext, r io.Reader
 
 	fmt.Fprintf(blue, "Stopping '%s' environment", env.Name)
 
-	if err := cmd.environmentAPI.EnvironmentDown(project, env, w); err != nil {
+	if err := cmd.environmentAPI.EnvironmentDown(ctx, project, env, w); err != nil {
 		return err
 	}
 
@@ -52,6 +52,10 @@ func (cmd *EnvironmentDownCommand) Validate(ctx *ecso.CommandContext) error {
 		return err
 	}
 
+	if cmd.Environment(ctx).Protected {
+		return ecso.NewProtectedEnvironmentError(cmd.environmentName)
+	}
+
 	if !cmd.force {
 		return ecso.NewOptionRequiredError("force")
 	}
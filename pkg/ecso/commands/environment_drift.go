@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewEnvironmentDriftCommand(environmentName string, environmentAPI api.EnvironmentAPI) ecso.Command {
+	return &environmentDriftCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type environmentDriftCommand struct {
+	*EnvironmentCommand
+}
+
+func (cmd *environmentDriftCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env  = cmd.Environment(ctx)
+		blue = ui.NewBannerWriter(w, ui.BlueBold)
+	)
+
+	fmt.Fprintf(blue, "Detecting drift in the '%s' environment", env.Name)
+
+	drift, err := cmd.environmentAPI.EnvironmentDrift(ctx, env, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Renderer.Render(drift)
+}
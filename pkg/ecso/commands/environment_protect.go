@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewEnvironmentProtectCommand(environmentName string, environmentAPI api.EnvironmentAPI) *EnvironmentProtectCommand {
+	return &EnvironmentProtectCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type EnvironmentProtectCommand struct {
+	*EnvironmentCommand
+}
+
+func (cmd *EnvironmentProtectCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		project = ctx.Project
+		env     = cmd.Environment(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	fmt.Fprintf(blue, "Protecting the '%s' environment", env.Name)
+
+	if err := cmd.environmentAPI.SetEnvironmentProtection(env, true); err != nil {
+		return err
+	}
+
+	env.Protected = true
+
+	if err := project.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "The '%s' environment is protected", env.Name)
+
+	return nil
+}
@@ -21,12 +21,10 @@ type envPsCommand struct {
 }
 
 func (cmd *envPsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
-	containers, err := cmd.environmentAPI.GetECSContainers(cmd.Environment(ctx))
+	containers, err := cmd.environmentAPI.GetECSContainers(ctx, cmd.Environment(ctx))
 	if err != nil {
 		return err
 	}
 
-	_, err = containers.WriteTo(w)
-
-	return err
+	return ctx.Renderer.Render(containers)
 }
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewEnvironmentUnprotectCommand(environmentName string, environmentAPI api.EnvironmentAPI) *EnvironmentUnprotectCommand {
+	return &EnvironmentUnprotectCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type EnvironmentUnprotectCommand struct {
+	*EnvironmentCommand
+	yes bool
+}
+
+// WithYes lifts protection without asking for confirmation. This is only
+// permitted for the IAM principals allowed by the environment
+func (cmd *EnvironmentUnprotectCommand) WithYes(yes bool) *EnvironmentUnprotectCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+func (cmd *EnvironmentUnprotectCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		project = ctx.Project
+		env     = cmd.Environment(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	if err := confirmProtectedEnvironment(env, cmd.yes, cmd.environmentAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(blue, "Lifting protection from the '%s' environment", env.Name)
+
+	if err := cmd.environmentAPI.SetEnvironmentProtection(env, false); err != nil {
+		return err
+	}
+
+	env.Protected = false
+
+	if err := project.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "The '%s' environment is no longer protected", env.Name)
+
+	return nil
+}
@@ -23,8 +23,12 @@ func NewEnvironmentUpCommand(environmentName string, environmentAPI api.Environm
 type EnvironmentUpCommand struct {
 	*EnvironmentCommand
 
-	dryRun bool
-	force  bool
+	dryRun    bool
+	force     bool
+	yes       bool
+	overrides *api.StackOverrides
+	save      bool
+	prune     bool
 }
 
 func (cmd *EnvironmentUpCommand) WithDryRun(dryRun bool) *EnvironmentUpCommand {
@@ -37,6 +41,35 @@ func (cmd *EnvironmentUpCommand) WithForce(force bool) *EnvironmentUpCommand {
 	return cmd
 }
 
+// WithPruneResources deletes resources from S3 that have been removed from
+// the environment's resource dir
+func (cmd *EnvironmentUpCommand) WithPruneResources(prune bool) *EnvironmentUpCommand {
+	cmd.prune = prune
+	return cmd
+}
+
+// WithYes skips the confirmation prompt if the environment is protected
+func (cmd *EnvironmentUpCommand) WithYes(yes bool) *EnvironmentUpCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+// WithOverrides sets cloudformation parameters and tags that take precedence
+// over those in project.json for this deployment
+func (cmd *EnvironmentUpCommand) WithOverrides(params, tags map[string]string) *EnvironmentUpCommand {
+	cmd.overrides = &api.StackOverrides{
+		Params: params,
+		Tags:   tags,
+	}
+	return cmd
+}
+
+// WithSave saves the overrides to project.json once the deployment succeeds
+func (cmd *EnvironmentUpCommand) WithSave(save bool) *EnvironmentUpCommand {
+	cmd.save = save
+	return cmd
+}
+
 func (cmd *EnvironmentUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
 	var (
 		project = ctx.Project
@@ -46,6 +79,12 @@ func (cmd *EnvironmentUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader,
 		info    = ui.NewInfoWriter(w)
 	)
 
+	if !cmd.dryRun {
+		if err := confirmProtectedEnvironment(env, cmd.yes, cmd.environmentAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(blue, "Bringing up environment '%s'", env.Name)
 
 	if cmd.dryRun {
@@ -56,7 +95,7 @@ func (cmd *EnvironmentUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader,
 		return err
 	}
 
-	if err := cmd.environmentAPI.EnvironmentUp(project, env, cmd.dryRun, w); err != nil {
+	if err := cmd.environmentAPI.EnvironmentUp(ctx, project, env, cmd.overrides, cmd.prune, cmd.dryRun, w); err != nil {
 		return err
 	}
 
@@ -65,6 +104,12 @@ func (cmd *EnvironmentUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader,
 		return nil
 	}
 
+	if cmd.save {
+		if err := saveEnvironmentOverrides(project, env, cmd.overrides); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(green, "Environment '%s' is up and running", env.Name)
 
 	_, err := cmd.environmentAPI.DescribeEnvironment(env)
@@ -82,20 +127,10 @@ func (cmd *EnvironmentUpCommand) ensureEnvironmentFiles(ctx *ecso.CommandContext
 		return err
 	}
 
-	templateData := struct {
-		ServiceDiscoveryLambdaVersion string
-		InstanceDrainerLambdaVersion  string
-		DNSCleanerLambdaVersion       string
-		Environment                   *ecso.Environment
-	}{
-		ServiceDiscoveryLambdaVersion: resources.ServiceDiscoveryLambdaVersion,
-		InstanceDrainerLambdaVersion:  resources.InstanceDrainerLambdaVersion,
-		DNSCleanerLambdaVersion:       resources.DNSCleanerLambdaVersion,
-		Environment:                   env,
-	}
+	templateData := environmentTemplateData(env)
 
 	if cmd.force {
-		w := resources.NewFileSystemResourceWriter(project.Dir())
+		w := resources.NewTrackingResourceWriter(project.Dir(), project.Dir())
 		return w.WriteResources(templateData, resources.EnvironmentFiles...)
 	}
 
@@ -108,6 +143,16 @@ func (cmd *EnvironmentUpCommand) ensureEnvironmentFiles(ctx *ecso.CommandContext
 		return fmt.Errorf("This looks like the first time you've run `environment up` for the %s environment from this repository, however there is already a CloudFormation stack up and running. This could mean that someone has already created the %s environment for the %s project. If you really know what you are doing, you can rerun `environment up` with the `--force` flag.", env.Name, env.Name, project.Name)
 	}
 
-	w := resources.NewFileSystemResourceWriter(project.Dir())
+	w := resources.NewTrackingResourceWriter(project.Dir(), project.Dir())
 	return w.WriteResources(templateData, resources.EnvironmentFiles...)
 }
+
+// environmentTemplateData is the data used to render the environment's
+// cloudformation templates and resources
+func environmentTemplateData(env *ecso.Environment) interface{} {
+	return struct {
+		Environment *ecso.Environment
+	}{
+		Environment: env,
+	}
+}
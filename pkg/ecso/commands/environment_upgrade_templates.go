@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/resources"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+	"github.com/bernos/ecso/pkg/ecso/util"
+)
+
+func NewEnvironmentUpgradeTemplatesCommand(environmentName string, environmentAPI api.EnvironmentAPI) *EnvironmentUpgradeTemplatesCommand {
+	return &EnvironmentUpgradeTemplatesCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type EnvironmentUpgradeTemplatesCommand struct {
+	*EnvironmentCommand
+	dryRun bool
+}
+
+func (cmd *EnvironmentUpgradeTemplatesCommand) WithDryRun(dryRun bool) *EnvironmentUpgradeTemplatesCommand {
+	cmd.dryRun = dryRun
+	return cmd
+}
+
+func (cmd *EnvironmentUpgradeTemplatesCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.EnvironmentCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	exists, err := util.DirExists(cmd.Environment(ctx).GetCloudFormationTemplateDir())
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("The '%s' environment has no templates to upgrade. Run `ecso environment up %s` to create them", cmd.environmentName, cmd.environmentName)
+	}
+
+	return nil
+}
+
+func (cmd *EnvironmentUpgradeTemplatesCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env  = cmd.Environment(ctx)
+		blue = ui.NewBannerWriter(w, ui.BlueBold)
+	)
+
+	fmt.Fprintf(blue, "Upgrading templates for the '%s' environment", env.Name)
+
+	results, err := resources.UpgradeResources(ctx.Project.Dir(), ctx.Project.Dir(), environmentTemplateData(env), cmd.dryRun, resources.EnvironmentFiles...)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Renderer.Render(results); err != nil {
+		return err
+	}
+
+	writeUpgradeSummary(w, results, cmd.dryRun, fmt.Sprintf("ecso environment up %s", env.Name))
+
+	return nil
+}
+
+// writeUpgradeSummary tells the user what to do about any files that could
+// not be upgraded automatically
+func writeUpgradeSummary(w io.Writer, results resources.UpgradeResultList, dryRun bool, next string) {
+	switch {
+	case dryRun:
+		fmt.Fprintf(w, "\nThis was a dry run. No files were changed.\n")
+	case results.HasConflicts():
+		fmt.Fprintf(w, "\nWARNING Some files could not be upgraded automatically. Resolve the conflict markers in files marked 'conflict', and merge the .upgrade file in to files marked 'untracked', before running `%s`.\n", next)
+	default:
+		fmt.Fprintf(w, "\nTemplates upgraded. Review the changes and run `%s` to deploy them.\n", next)
+	}
+}
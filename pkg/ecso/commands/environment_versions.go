@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+)
+
+func NewEnvironmentVersionsCommand(environmentName string, environmentAPI api.EnvironmentAPI) *EnvironmentVersionsCommand {
+	return &EnvironmentVersionsCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type EnvironmentVersionsCommand struct {
+	*EnvironmentCommand
+}
+
+func (cmd *EnvironmentVersionsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	versions, err := cmd.environmentAPI.GetAvailableVersions(ctx, cmd.Environment(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Renderer.Render(versions)
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewEnvironmentVersionsPruneCommand(environmentName string, environmentAPI api.EnvironmentAPI) *EnvironmentVersionsPruneCommand {
+	return &EnvironmentVersionsPruneCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type EnvironmentVersionsPruneCommand struct {
+	*EnvironmentCommand
+
+	keep   int
+	dryRun bool
+}
+
+func (cmd *EnvironmentVersionsPruneCommand) WithKeep(keep int) *EnvironmentVersionsPruneCommand {
+	cmd.keep = keep
+	return cmd
+}
+
+func (cmd *EnvironmentVersionsPruneCommand) WithDryRun(dryRun bool) *EnvironmentVersionsPruneCommand {
+	cmd.dryRun = dryRun
+	return cmd
+}
+
+func (cmd *EnvironmentVersionsPruneCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env   = cmd.Environment(ctx)
+		blue  = ui.NewBannerWriter(w, ui.BlueBold)
+		green = ui.NewBannerWriter(w, ui.GreenBold)
+		info  = ui.NewInfoWriter(w)
+	)
+
+	fmt.Fprintf(blue, "Pruning versions of the '%s' environment, keeping the newest %d", env.Name, cmd.keep)
+
+	if cmd.dryRun {
+		fmt.Fprintf(info, "THIS IS A DRY RUN - no versions will be deleted.")
+	}
+
+	versions, err := cmd.environmentAPI.PruneVersions(ctx, ctx.Project, env, cmd.keep, cmd.dryRun, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Fprintf(green, "There were no versions to prune")
+		return nil
+	}
+
+	if cmd.dryRun {
+		fmt.Fprintf(info, "The following versions would be deleted:")
+	} else {
+		fmt.Fprintf(info, "The following versions were deleted:")
+	}
+
+	if err := ctx.Renderer.Render(versions); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "Pruned %d versions of the '%s' environment", len(versions), env.Name)
+
+	return nil
+}
+
+func (cmd *EnvironmentVersionsPruneCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.EnvironmentCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.keep < 1 {
+		return fmt.Errorf("Keep must be at least 1")
+	}
+
+	return nil
+}
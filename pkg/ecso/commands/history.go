@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+)
+
+// NewHistoryCommand creates a command that lists the deployment history of
+// environments. environmentAPIs holds an api for each environment to list,
+// by environment name, as environments may be in different regions
+func NewHistoryCommand(environmentAPIs map[string]api.EnvironmentAPI) *HistoryCommand {
+	return &HistoryCommand{
+		environmentAPIs: environmentAPIs,
+	}
+}
+
+type HistoryCommand struct {
+	environmentAPIs map[string]api.EnvironmentAPI
+	service         string
+	since           time.Time
+}
+
+// WithService limits the history to changes made to a single service
+func (cmd *HistoryCommand) WithService(service string) *HistoryCommand {
+	cmd.service = service
+	return cmd
+}
+
+// WithSince limits the history to changes made after a time
+func (cmd *HistoryCommand) WithSince(since time.Time) *HistoryCommand {
+	cmd.since = since
+	return cmd
+}
+
+func (cmd *HistoryCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		records = make(api.HistoryRecordList, 0)
+		filter  = &helpers.HistoryFilter{Service: cmd.service, Since: cmd.since}
+	)
+
+	for name, environmentAPI := range cmd.environmentAPIs {
+		store, err := environmentAPI.GetHistoryStore(ctx.Project.Environments[name])
+		if err != nil {
+			return err
+		}
+
+		result, err := store.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, result...)
+	}
+
+	sort.Sort(records)
+
+	return ctx.Renderer.Render(records)
+}
+
+func (cmd *HistoryCommand) Validate(ctx *ecso.CommandContext) error {
+	for name := range cmd.environmentAPIs {
+		if !ctx.Project.HasEnvironment(name) {
+			return fmt.Errorf("No environment named '%s' was found", name)
+		}
+	}
+
+	if cmd.service != "" && !ctx.Project.HasService(cmd.service) {
+		return fmt.Errorf("Service '%s' does not exist in the project", cmd.service)
+	}
+
+	return nil
+}
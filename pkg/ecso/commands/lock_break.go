@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewLockBreakCommand(environmentName string, environmentAPI api.EnvironmentAPI) *LockBreakCommand {
+	return &LockBreakCommand{
+		EnvironmentCommand: &EnvironmentCommand{
+			environmentName: environmentName,
+			environmentAPI:  environmentAPI,
+		},
+	}
+}
+
+type LockBreakCommand struct {
+	*EnvironmentCommand
+
+	service string
+	force   bool
+}
+
+// WithService breaks the lock on a service, rather than on the environment
+func (cmd *LockBreakCommand) WithService(service string) *LockBreakCommand {
+	cmd.service = service
+	return cmd
+}
+
+// WithForce breaks the lock even if it has not expired
+func (cmd *LockBreakCommand) WithForce(force bool) *LockBreakCommand {
+	cmd.force = force
+	return cmd
+}
+
+func (cmd *LockBreakCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env   = cmd.Environment(ctx)
+		name  = api.LockName(cmd.service)
+		green = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	store, err := cmd.environmentAPI.GetLockStore(env)
+	if err != nil {
+		return err
+	}
+
+	lock, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if lock == nil {
+		return fmt.Errorf("There is no %s lock in the '%s' environment", name, env.Name)
+	}
+
+	if !lock.Expired(time.Now()) && !cmd.force {
+		return fmt.Errorf("The %s lock is held by %s, who is running `ecso %s` on %s, and is still being renewed. Use --force to break it anyway", name, lock.Holder, lock.Action, lock.Host)
+	}
+
+	if err := store.Delete(lock); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "Broke the %s lock held by %s in the '%s' environment", name, lock.Holder, env.Name)
+
+	return nil
+}
+
+func (cmd *LockBreakCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.EnvironmentCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.service != "" && !ctx.Project.HasService(cmd.service) {
+		return fmt.Errorf("Service '%s' does not exist in the project", cmd.service)
+	}
+
+	return nil
+}
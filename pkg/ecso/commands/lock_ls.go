@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+)
+
+// NewLockLsCommand creates a command that lists the locks held on
+// environments and their services. environmentAPIs holds an api for each
+// environment to list, by environment name
+func NewLockLsCommand(environmentAPIs map[string]api.EnvironmentAPI) *LockLsCommand {
+	return &LockLsCommand{
+		environmentAPIs: environmentAPIs,
+	}
+}
+
+type LockLsCommand struct {
+	environmentAPIs map[string]api.EnvironmentAPI
+}
+
+func (cmd *LockLsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	locks := make(api.LockList, 0)
+
+	for name, environmentAPI := range cmd.environmentAPIs {
+		store, err := environmentAPI.GetLockStore(ctx.Project.Environments[name])
+		if err != nil {
+			return err
+		}
+
+		result, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		locks = append(locks, result...)
+	}
+
+	sort.Slice(locks, func(i, j int) bool {
+		if locks[i].Environment == locks[j].Environment {
+			return locks[i].Name < locks[j].Name
+		}
+
+		return locks[i].Environment < locks[j].Environment
+	})
+
+	return ctx.Renderer.Render(locks)
+}
+
+func (cmd *LockLsCommand) Validate(ctx *ecso.CommandContext) error {
+	for name := range cmd.environmentAPIs {
+		if !ctx.Project.HasEnvironment(name) {
+			return fmt.Errorf("No environment named '%s' was found", name)
+		}
+	}
+
+	return nil
+}
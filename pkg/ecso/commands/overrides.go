@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+)
+
+// saveEnvironmentOverrides merges parameter and tag overrides into the
+// environment's configuration in project.json
+func saveEnvironmentOverrides(project *ecso.Project, env *ecso.Environment, overrides *api.StackOverrides) error {
+	if overrides.IsEmpty() {
+		return nil
+	}
+
+	if env.CloudFormationParameters == nil {
+		env.CloudFormationParameters = make(map[string]string)
+	}
+
+	if env.CloudFormationTags == nil {
+		env.CloudFormationTags = make(map[string]string)
+	}
+
+	overrides.Apply(env.CloudFormationParameters, env.CloudFormationTags)
+
+	return project.Save()
+}
+
+// saveServiceOverrides merges parameter overrides into the service's
+// configuration for the environment in project.json. Service tags are not
+// configured per environment, so tag overrides are saved for all environments
+func saveServiceOverrides(project *ecso.Project, env *ecso.Environment, service *ecso.Service, overrides *api.StackOverrides) error {
+	if overrides.IsEmpty() {
+		return nil
+	}
+
+	if service.Environments == nil {
+		service.Environments = make(map[string]ecso.ServiceConfiguration)
+	}
+
+	cfg := service.Environments[env.Name]
+
+	if cfg.CloudFormationParameters == nil {
+		cfg.CloudFormationParameters = make(map[string]string)
+	}
+
+	if service.Tags == nil {
+		service.Tags = make(map[string]string)
+	}
+
+	overrides.Apply(cfg.CloudFormationParameters, service.Tags)
+
+	service.Environments[env.Name] = cfg
+
+	return project.Save()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+// confirmProtectedEnvironment asks the user to type the name of a protected
+// environment before it is changed. When yes is true the prompt is skipped,
+// provided the current IAM principal is one of the environment's
+// AllowedPrincipals
+func confirmProtectedEnvironment(env *ecso.Environment, yes bool, principal func() (string, error), r io.Reader, w io.Writer) error {
+	if !env.Protected {
+		return nil
+	}
+
+	if yes {
+		arn, err := principal()
+		if err != nil {
+			return err
+		}
+
+		if !env.IsAllowedPrincipal(arn) {
+			return fmt.Errorf("%s is not allowed to change the protected '%s' environment with --yes. Add it to the environment's AllowedPrincipals in project.json, or run the command without --yes to confirm interactively", arn, env.Name)
+		}
+
+		return nil
+	}
+
+	fmt.Fprintf(w, "WARNING The '%s' environment is protected.\n", env.Name)
+	fmt.Fprintf(w, "Type the name of the environment to confirm: ")
+
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("Changes to the protected '%s' environment must be confirmed", env.Name)
+	}
+
+	if strings.TrimSpace(scanner.Text()) != env.Name {
+		return fmt.Errorf("Confirmation did not match. The '%s' environment was not changed", env.Name)
+	}
+
+	return nil
+}
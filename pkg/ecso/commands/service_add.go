@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/resources"
@@ -21,6 +22,8 @@ type ServiceAddCommand struct {
 	desiredCount int
 	route        string
 	port         int
+	template     *resources.TemplatePack
+	variables    map[string]string
 }
 
 func (cmd *ServiceAddCommand) WithDesiredCount(x int) *ServiceAddCommand {
@@ -38,6 +41,21 @@ func (cmd *ServiceAddCommand) WithPort(x int) *ServiceAddCommand {
 	return cmd
 }
 
+// WithTemplate sets the template pack used to create the service's files.
+// By default the built-in "web" pack is used for services with a route, and
+// the "worker" pack for other services
+func (cmd *ServiceAddCommand) WithTemplate(pack *resources.TemplatePack) *ServiceAddCommand {
+	cmd.template = pack
+	return cmd
+}
+
+// WithVariables sets values for the template pack's variables. Variables
+// that are not set take their default value
+func (cmd *ServiceAddCommand) WithVariables(variables map[string]string) *ServiceAddCommand {
+	cmd.variables = variables
+	return cmd
+}
+
 func (cmd *ServiceAddCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
 	service := &ecso.Service{
 		Name:         cmd.name,
@@ -55,6 +73,11 @@ func (cmd *ServiceAddCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w i
 		service.Port = cmd.port
 	}
 
+	if cmd.template != nil {
+		service.Template = templatePackReference(ctx.Project, cmd.template)
+		service.TemplateVariables = cmd.template.ResolveVariables(cmd.variables)
+	}
+
 	ctx.Project.AddService(service)
 
 	if err := cmd.createResources(ctx.Project, service); err != nil {
@@ -76,36 +99,75 @@ func (cmd *ServiceAddCommand) Validate(ctx *ecso.CommandContext) error {
 	if cmd.route != "" && cmd.port == 0 {
 		return fmt.Errorf("Port is required")
 	}
+
+	if cmd.template != nil {
+		if cmd.template.Web && cmd.route == "" {
+			return fmt.Errorf("The '%s' template pack is for web services, and requires a route", cmd.template.Name)
+		}
+
+		if !cmd.template.Web && cmd.route != "" {
+			return fmt.Errorf("The '%s' template pack is not for web services, and cannot be used with a route", cmd.template.Name)
+		}
+
+		for name := range cmd.variables {
+			if !cmd.template.HasVariable(name) {
+				return fmt.Errorf("The '%s' template pack has no variable named '%s'", cmd.template.Name, name)
+			}
+		}
+	}
+
 	return nil
 }
 
 func (cmd *ServiceAddCommand) createResources(project *ecso.Project, service *ecso.Service) error {
-	cfnWriter := resources.NewFileSystemResourceWriter(service.GetCloudFormationTemplateDir())
-	resourceWriter := resources.NewFileSystemResourceWriter(service.Dir())
+	pack := cmd.template
 
-	templateData := struct {
-		Service *ecso.Service
-		Project *ecso.Project
-	}{
-		Service: service,
-		Project: project,
+	if pack == nil {
+		pack = defaultServiceTemplatePack(service)
 	}
 
-	var serviceResources *resources.ServiceResources
+	data := serviceTemplateData(project, service, pack.ResolveVariables(cmd.variables))
 
+	return resources.NewTrackingResourceWriter(project.Dir(), service.Dir()).WriteResources(data, pack.Files...)
+}
+
+// serviceTemplateData is the data used to render the files in a service
+// template pack
+func serviceTemplateData(project *ecso.Project, service *ecso.Service, variables map[string]string) interface{} {
+	return struct {
+		Service   *ecso.Service
+		Project   *ecso.Project
+		Variables map[string]string
+	}{
+		Service:   service,
+		Project:   project,
+		Variables: variables,
+	}
+}
+
+func defaultServiceTemplatePack(service *ecso.Service) *resources.TemplatePack {
 	if len(service.Route) > 0 {
-		serviceResources = &resources.WebService
-	} else {
-		serviceResources = &resources.WorkerService
+		return resources.GetBuiltInTemplatePack("web")
 	}
 
-	if err := cfnWriter.WriteResource(serviceResources.CloudFormationTemplate, templateData); err != nil {
-		return err
+	return resources.GetBuiltInTemplatePack("worker")
+}
+
+// templatePackReference returns the name that the pack can be found by
+// later. Packs that were loaded from a dir are referred to by their path,
+// relative to the project dir if possible
+func templatePackReference(project *ecso.Project, pack *resources.TemplatePack) string {
+	if pack.Source == resources.BuiltInTemplatePackSource {
+		return pack.Name
 	}
 
-	if err := resourceWriter.WriteResource(serviceResources.ComposeFile, templateData); err != nil {
-		return err
+	if found, err := resources.FindTemplatePack(pack.Name, project.TemplateSearchPath()); err == nil && found.Source == pack.Source {
+		return pack.Name
 	}
 
-	return nil
+	if rel, err := filepath.Rel(project.Dir(), pack.Source); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+
+	return pack.Source
 }
@@ -33,7 +33,5 @@ func (cmd *serviceDecribeCommand) Execute(ctx *ecso.CommandContext, r io.Reader,
 		return err
 	}
 
-	description.WriteTo(w)
-
-	return nil
+	return ctx.Renderer.Render(description)
 }
@@ -39,7 +39,7 @@ func (cmd *ServiceDownCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w
 
 	fmt.Fprintf(blue, "Terminating the '%s' service in the '%s' environment", service.Name, env.Name)
 
-	if err := cmd.serviceAPI.ServiceDown(ctx.Project, env, service, w); err != nil {
+	if err := cmd.serviceAPI.ServiceDown(ctx, ctx.Project, env, service, w); err != nil {
 		return err
 	}
 
@@ -53,6 +53,10 @@ func (cmd *ServiceDownCommand) Validate(ctx *ecso.CommandContext) error {
 		return err
 	}
 
+	if cmd.Environment(ctx).Protected {
+		return ecso.NewProtectedEnvironmentError(cmd.environmentName)
+	}
+
 	if !cmd.force {
 		return ecso.NewOptionRequiredError("force")
 	}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewServiceDriftCommand(name, environmentName string, serviceAPI api.ServiceAPI) ecso.Command {
+	return &serviceDriftCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: environmentName,
+			serviceAPI:      serviceAPI,
+		},
+	}
+}
+
+type serviceDriftCommand struct {
+	*ServiceCommand
+}
+
+func (cmd *serviceDriftCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+	)
+
+	fmt.Fprintf(blue, "Detecting drift in service '%s' in the '%s' environment", service.Name, env.Name)
+
+	drift, err := cmd.serviceAPI.ServiceDrift(ctx, ctx.Project, env, service, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Renderer.Render(drift)
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewServiceEndpointsCommand(name string, environmentName string, serviceAPI api.ServiceAPI) *ServiceEndpointsCommand {
+	return &ServiceEndpointsCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: environmentName,
+			serviceAPI:      serviceAPI,
+		},
+	}
+}
+
+type ServiceEndpointsCommand struct {
+	*ServiceCommand
+
+	clean bool
+}
+
+func (cmd *ServiceEndpointsCommand) WithClean(clean bool) *ServiceEndpointsCommand {
+	cmd.clean = clean
+	return cmd
+}
+
+func (cmd *ServiceEndpointsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		info    = ui.NewInfoWriter(w)
+	)
+
+	endpoints, err := cmd.serviceAPI.GetServiceEndpoints(ctx, ctx.Project, env, service)
+	if err != nil {
+		return err
+	}
+
+	stale := endpoints.Stale()
+
+	if cmd.clean {
+		fmt.Fprintf(info, "Deleting stale SRV records for %s", service.GetDiscoveryName(env))
+
+		if err := cmd.serviceAPI.CleanServiceEndpoints(ctx, env, service, endpoints, ui.NewPrefixWriter(w, "  ")); err != nil {
+			return err
+		}
+
+		endpoints = endpoints.Live()
+	}
+
+	if len(endpoints) == 0 {
+		fmt.Fprintf(info, "No endpoints are registered for %s", service.GetDiscoveryName(env))
+		return nil
+	}
+
+	if err := ctx.Renderer.Render(endpoints); err != nil {
+		return err
+	}
+
+	if len(stale) > 0 && !cmd.clean {
+		fmt.Fprintf(w, "WARNING %d endpoints have no matching running container. Run `ecso service endpoints --environment %s --clean %s` to delete their records\n", len(stale), env.Name, service.Name)
+	}
+
+	return nil
+}
+
+func (cmd *ServiceEndpointsCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if env := cmd.Environment(ctx); cmd.clean && env.GetServiceDiscovery() == ecso.ServiceDiscoveryCloudMap {
+		return fmt.Errorf("--clean is not supported in the '%s' environment, as its service discovery records are managed by Cloud Map", env.Name)
+	}
+
+	return nil
+}
@@ -1,9 +1,9 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"time"
 
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/bernos/ecso/pkg/ecso"
@@ -23,17 +23,23 @@ func NewServiceEventsCommand(name string, environmentName string, serviceAPI api
 
 type ServiceEventsCommand struct {
 	*ServiceCommand
+	untilStable bool
+}
+
+func (cmd *ServiceEventsCommand) WithUntilStable(untilStable bool) *ServiceEventsCommand {
+	cmd.untilStable = untilStable
+	return cmd
 }
 
 func (cmd *ServiceEventsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
 	var (
 		env     = cmd.Environment(ctx)
 		service = cmd.Service(ctx)
-		count   = 0
 		ew      = ui.NewErrWriter(w)
+		stable  chan error
 	)
 
-	cancel, err := cmd.serviceAPI.ServiceEvents(ctx.Project, env, service, func(e *ecs.ServiceEvent, err error) {
+	cancel, err := cmd.serviceAPI.ServiceEvents(ctx, ctx.Project, env, service, func(e *ecs.ServiceEvent, err error) {
 		if err != nil {
 			fmt.Fprintf(ew, "%s\n", err.Error())
 		} else {
@@ -47,10 +53,25 @@ func (cmd *ServiceEventsCommand) Execute(ctx *ecso.CommandContext, r io.Reader,
 
 	defer cancel()
 
-	for count < 10 {
-		time.Sleep(time.Second * 60)
-		count++
+	if cmd.untilStable {
+		stable = make(chan error, 1)
+
+		go func() {
+			stable <- cmd.serviceAPI.WaitUntilServiceStable(ctx, ctx.Project, env, service, 0)
+		}()
 	}
 
-	return nil
+	// Following events ends when the user interrupts the command
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return nil
+		}
+		return ctx.Err()
+	case err := <-stable:
+		if ctx.Err() == context.Canceled {
+			return nil
+		}
+		return err
+	}
 }
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// NewServicePlanCommand creates a command that prepares a deployment of a
+// service and writes it to a plan file, without executing it
+func NewServicePlanCommand(name, environmentName, out string, serviceAPI api.ServiceAPI) *ServicePlanCommand {
+	return &ServicePlanCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: environmentName,
+			serviceAPI:      serviceAPI,
+		},
+		out: out,
+	}
+}
+
+type ServicePlanCommand struct {
+	*ServiceCommand
+
+	out       string
+	version   string
+	skipBuild bool
+	overrides *api.StackOverrides
+}
+
+// WithVersion sets the label of the version to plan. If no version is set, a
+// label is generated using the project's versioning strategy
+func (cmd *ServicePlanCommand) WithVersion(version string) *ServicePlanCommand {
+	cmd.version = version
+	return cmd
+}
+
+// WithSkipBuild prevents images being built for containers with a `build`
+// section in the compose file
+func (cmd *ServicePlanCommand) WithSkipBuild(skipBuild bool) *ServicePlanCommand {
+	cmd.skipBuild = skipBuild
+	return cmd
+}
+
+// WithOverrides sets cloudformation parameters and tags that take precedence
+// over those in project.json for the planned deployment
+func (cmd *ServicePlanCommand) WithOverrides(params, tags map[string]string) *ServicePlanCommand {
+	cmd.overrides = &api.StackOverrides{
+		Params: params,
+		Tags:   tags,
+	}
+	return cmd
+}
+
+func (cmd *ServicePlanCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		project = ctx.Project
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	version := cmd.version
+
+	if version == "" {
+		label, err := helpers.NewVersionLabel(project.VersionStrategy, project.Dir(), time.Now())
+		if err != nil {
+			return err
+		}
+
+		version = label
+	}
+
+	fmt.Fprintf(blue, "Planning deployment of version '%s' of service '%s' to the '%s' environment", version, service.Name, env.Name)
+
+	plan, err := cmd.serviceAPI.ServicePlan(ctx, project, env, service, version, cmd.skipBuild, cmd.overrides, w)
+	if err != nil {
+		return err
+	}
+
+	plan.WriteTo(w)
+
+	if !plan.HasChanges() {
+		fmt.Fprintf(green, "Service '%s' is up to date in the '%s' environment. No plan was written", service.Name, env.Name)
+		return nil
+	}
+
+	if err := plan.Save(cmd.out); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "Wrote plan to %s. Run `ecso apply %s` to deploy it", cmd.out, cmd.out)
+
+	return nil
+}
+
+func (cmd *ServicePlanCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.out == "" {
+		return fmt.Errorf("Plan file is required")
+	}
+
+	if cmd.version != "" {
+		return helpers.ValidateVersionLabel(cmd.version)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// NewServicePromoteCommand creates a command that promotes a version of a
+// service from one environment to another. sourceAPI is used to access the
+// environment being promoted from, and serviceAPI the environment being
+// promoted to, as they may be in different regions
+func NewServicePromoteCommand(name, fromEnvironmentName, toEnvironmentName string, sourceAPI, serviceAPI api.ServiceAPI) *ServicePromoteCommand {
+	return &ServicePromoteCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: toEnvironmentName,
+			serviceAPI:      serviceAPI,
+		},
+		fromEnvironmentName: fromEnvironmentName,
+		sourceAPI:           sourceAPI,
+	}
+}
+
+type ServicePromoteCommand struct {
+	*ServiceCommand
+
+	fromEnvironmentName string
+	sourceAPI           api.ServiceAPI
+	version             string
+	yes                 bool
+}
+
+// WithVersion sets the version to promote. If no version is set, the version
+// currently deployed to the source environment is promoted
+func (cmd *ServicePromoteCommand) WithVersion(version string) *ServicePromoteCommand {
+	cmd.version = version
+	return cmd
+}
+
+// WithYes skips the confirmation prompt when promoting to a protected
+// environment
+func (cmd *ServicePromoteCommand) WithYes(yes bool) *ServicePromoteCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+func (cmd *ServicePromoteCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		project = ctx.Project
+		from    = project.Environments[cmd.fromEnvironmentName]
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	if err := confirmProtectedEnvironment(env, cmd.yes, cmd.serviceAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+		return err
+	}
+
+	source, err := cmd.sourceAPI.GetVersion(ctx, project, from, service, cmd.version)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(blue, "Promoting version '%s' of service '%s' from the '%s' environment to the '%s' environment", source.Label, service.Name, from.Name, env.Name)
+
+	description, err := cmd.serviceAPI.ServicePromote(ctx, project, env, service, from, source, w)
+	if err != nil {
+		return err
+	}
+
+	description.WriteTo(w)
+
+	fmt.Fprintf(green, "Promoted version '%s' of service '%s' to the '%s' environment", source.Label, service.Name, env.Name)
+
+	return nil
+}
+
+func (cmd *ServicePromoteCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.fromEnvironmentName == "" {
+		return fmt.Errorf("Source environment is required")
+	}
+
+	if !ctx.Project.HasEnvironment(cmd.fromEnvironmentName) {
+		return fmt.Errorf("No environment named '%s' was found", cmd.fromEnvironmentName)
+	}
+
+	if cmd.fromEnvironmentName == cmd.environmentName {
+		return fmt.Errorf("Cannot promote a version to the environment it was deployed from")
+	}
+
+	return nil
+}
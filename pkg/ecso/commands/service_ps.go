@@ -27,12 +27,10 @@ func (cmd *ServicePsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io
 		service = cmd.Service(ctx)
 	)
 
-	containers, err := cmd.serviceAPI.GetECSContainers(ctx.Project, env, service)
+	containers, err := cmd.serviceAPI.GetECSContainers(ctx, ctx.Project, env, service)
 	if err != nil {
 		return err
 	}
 
-	_, err = containers.WriteTo(w)
-
-	return err
+	return ctx.Renderer.Render(containers)
 }
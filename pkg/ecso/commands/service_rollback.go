@@ -22,7 +22,33 @@ func NewServiceRollbackCommand(name string, environmentName string, version stri
 
 type ServiceRollbackCommand struct {
 	*ServiceCommand
-	version string
+	version   string
+	yes       bool
+	overrides *api.StackOverrides
+	save      bool
+}
+
+// WithYes skips the confirmation prompt when rolling back a service in a
+// protected environment
+func (cmd *ServiceRollbackCommand) WithYes(yes bool) *ServiceRollbackCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+// WithOverrides sets cloudformation parameters and tags that take precedence
+// over those in project.json for this rollback
+func (cmd *ServiceRollbackCommand) WithOverrides(params, tags map[string]string) *ServiceRollbackCommand {
+	cmd.overrides = &api.StackOverrides{
+		Params: params,
+		Tags:   tags,
+	}
+	return cmd
+}
+
+// WithSave saves the overrides to project.json once the rollback succeeds
+func (cmd *ServiceRollbackCommand) WithSave(save bool) *ServiceRollbackCommand {
+	cmd.save = save
+	return cmd
 }
 
 func (cmd *ServiceRollbackCommand) Validate(ctx *ecso.CommandContext) error {
@@ -46,15 +72,25 @@ func (cmd *ServiceRollbackCommand) Execute(ctx *ecso.CommandContext, r io.Reader
 		green   = ui.NewBannerWriter(w, ui.GreenBold)
 	)
 
+	if err := confirmProtectedEnvironment(env, cmd.yes, cmd.serviceAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+		return err
+	}
+
 	fmt.Fprintf(blue, "Rolling back service '%s' to version '%s' in the '%s' environment", service.Name, cmd.version, env.Name)
 
-	description, err := cmd.serviceAPI.ServiceRollback(project, env, service, cmd.version, w)
+	description, err := cmd.serviceAPI.ServiceRollback(ctx, project, env, service, cmd.version, cmd.overrides, w)
 	if err != nil {
 		return err
 	}
 
 	description.WriteTo(w)
 
+	if cmd.save {
+		if err := saveServiceOverrides(project, env, service, cmd.overrides); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(green, "Rolled back service '%s' to version '%s' in the '%s' environment", service.Name, cmd.version, env.Name)
 
 	return nil
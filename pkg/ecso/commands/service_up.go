@@ -3,9 +3,11 @@ package commands
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/helpers"
 	"github.com/bernos/ecso/pkg/ecso/ui"
 )
 
@@ -21,6 +23,50 @@ func NewServiceUpCommand(name string, environmentName string, serviceAPI api.Ser
 
 type ServiceUpCommand struct {
 	*ServiceCommand
+
+	version   string
+	skipBuild bool
+	yes       bool
+	overrides *api.StackOverrides
+	save      bool
+}
+
+// WithVersion sets the label of the version to deploy. If no version is set,
+// a label is generated using the project's versioning strategy
+func (cmd *ServiceUpCommand) WithVersion(version string) *ServiceUpCommand {
+	cmd.version = version
+	return cmd
+}
+
+// WithSkipBuild prevents images being built for containers with a `build`
+// section in the compose file. The image most recently pushed for each
+// container is deployed instead
+func (cmd *ServiceUpCommand) WithSkipBuild(skipBuild bool) *ServiceUpCommand {
+	cmd.skipBuild = skipBuild
+	return cmd
+}
+
+// WithYes skips confirmation when the environment is protected. This is only
+// permitted for the IAM principals allowed by the environment
+func (cmd *ServiceUpCommand) WithYes(yes bool) *ServiceUpCommand {
+	cmd.yes = yes
+	return cmd
+}
+
+// WithOverrides sets cloudformation parameters and tags that take precedence
+// over those in project.json for this deployment
+func (cmd *ServiceUpCommand) WithOverrides(params, tags map[string]string) *ServiceUpCommand {
+	cmd.overrides = &api.StackOverrides{
+		Params: params,
+		Tags:   tags,
+	}
+	return cmd
+}
+
+// WithSave saves the overrides to project.json once the deployment succeeds
+func (cmd *ServiceUpCommand) WithSave(save bool) *ServiceUpCommand {
+	cmd.save = save
+	return cmd
 }
 
 func (cmd *ServiceUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
@@ -32,9 +78,24 @@ func (cmd *ServiceUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io
 		green   = ui.NewBannerWriter(w, ui.GreenBold)
 	)
 
-	fmt.Fprintf(blue, "Deploying service '%s' to the '%s' environment", service.Name, env.Name)
+	if err := confirmProtectedEnvironment(env, cmd.yes, cmd.serviceAPI.GetCurrentAWSPrincipal, r, w); err != nil {
+		return err
+	}
+
+	version := cmd.version
 
-	description, err := cmd.serviceAPI.ServiceUp(project, env, service, w)
+	if version == "" {
+		label, err := helpers.NewVersionLabel(project.VersionStrategy, project.Dir(), time.Now())
+		if err != nil {
+			return err
+		}
+
+		version = label
+	}
+
+	fmt.Fprintf(blue, "Deploying version '%s' of service '%s' to the '%s' environment", version, service.Name, env.Name)
+
+	description, err := cmd.serviceAPI.ServiceUp(ctx, project, env, service, version, cmd.skipBuild, cmd.overrides, w)
 
 	if err != nil {
 		return err
@@ -42,7 +103,25 @@ func (cmd *ServiceUpCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io
 
 	description.WriteTo(w)
 
+	if cmd.save {
+		if err := saveServiceOverrides(project, env, service, cmd.overrides); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(green, "Deployed service '%s' to the '%s' environment", service.Name, env.Name)
 
 	return nil
 }
+
+func (cmd *ServiceUpCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.version != "" {
+		return helpers.ValidateVersionLabel(cmd.version)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/resources"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewServiceUpgradeTemplatesCommand(name string) *ServiceUpgradeTemplatesCommand {
+	return &ServiceUpgradeTemplatesCommand{
+		name: name,
+	}
+}
+
+type ServiceUpgradeTemplatesCommand struct {
+	name   string
+	dryRun bool
+}
+
+func (cmd *ServiceUpgradeTemplatesCommand) WithDryRun(dryRun bool) *ServiceUpgradeTemplatesCommand {
+	cmd.dryRun = dryRun
+	return cmd
+}
+
+func (cmd *ServiceUpgradeTemplatesCommand) Validate(ctx *ecso.CommandContext) error {
+	if cmd.name == "" {
+		return fmt.Errorf("Service name is required")
+	}
+
+	if !ctx.Project.HasService(cmd.name) {
+		return fmt.Errorf("No service named '%s' was found", cmd.name)
+	}
+
+	return nil
+}
+
+func (cmd *ServiceUpgradeTemplatesCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		service = ctx.Project.Services[cmd.name]
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+	)
+
+	pack, err := serviceTemplatePack(ctx.Project, service)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(blue, "Upgrading templates for the '%s' service from the '%s' template pack", service.Name, pack.Name)
+
+	data := serviceTemplateData(ctx.Project, service, pack.ResolveVariables(service.TemplateVariables))
+
+	results, err := resources.UpgradeResources(ctx.Project.Dir(), service.Dir(), data, cmd.dryRun, pack.Files...)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Renderer.Render(results); err != nil {
+		return err
+	}
+
+	writeUpgradeSummary(w, results, cmd.dryRun, fmt.Sprintf("ecso service up %s", service.Name))
+
+	return nil
+}
+
+// serviceTemplatePack returns the template pack that the service was created
+// from
+func serviceTemplatePack(project *ecso.Project, service *ecso.Service) (*resources.TemplatePack, error) {
+	if service.Template == "" {
+		return defaultServiceTemplatePack(service), nil
+	}
+
+	dir := service.Template
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(project.Dir(), dir)
+	}
+
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return resources.LoadTemplatePack(dir)
+	}
+
+	return resources.FindTemplatePack(service.Template, project.TemplateSearchPath())
+}
@@ -27,12 +27,10 @@ func (cmd *ServiceVersionsCommand) Execute(ctx *ecso.CommandContext, r io.Reader
 		service = cmd.Service(ctx)
 	)
 
-	versions, err := cmd.serviceAPI.GetAvailableVersions(ctx.Project, env, service)
+	versions, err := cmd.serviceAPI.GetAvailableVersions(ctx, ctx.Project, env, service)
 	if err != nil {
 		return err
 	}
 
-	_, err = versions.WriteTo(w)
-
-	return err
+	return ctx.Renderer.Render(versions)
 }
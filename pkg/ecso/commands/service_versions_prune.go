@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewServiceVersionsPruneCommand(name string, environmentName string, serviceAPI api.ServiceAPI) *ServiceVersionsPruneCommand {
+	return &ServiceVersionsPruneCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: environmentName,
+			serviceAPI:      serviceAPI,
+		},
+	}
+}
+
+type ServiceVersionsPruneCommand struct {
+	*ServiceCommand
+
+	keep   int
+	dryRun bool
+}
+
+func (cmd *ServiceVersionsPruneCommand) WithKeep(keep int) *ServiceVersionsPruneCommand {
+	cmd.keep = keep
+	return cmd
+}
+
+func (cmd *ServiceVersionsPruneCommand) WithDryRun(dryRun bool) *ServiceVersionsPruneCommand {
+	cmd.dryRun = dryRun
+	return cmd
+}
+
+func (cmd *ServiceVersionsPruneCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+		info    = ui.NewInfoWriter(w)
+	)
+
+	fmt.Fprintf(blue, "Pruning versions of service '%s' in the '%s' environment, keeping the newest %d", service.Name, env.Name, cmd.keep)
+
+	if cmd.dryRun {
+		fmt.Fprintf(info, "THIS IS A DRY RUN - no versions will be deleted.")
+	}
+
+	versions, err := cmd.serviceAPI.PruneVersions(ctx, ctx.Project, env, service, cmd.keep, cmd.dryRun, ui.NewPrefixWriter(w, "  "))
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Fprintf(green, "There were no versions to prune")
+		return nil
+	}
+
+	if cmd.dryRun {
+		fmt.Fprintf(info, "The following versions would be deleted:")
+	} else {
+		fmt.Fprintf(info, "The following versions were deleted:")
+	}
+
+	if err := ctx.Renderer.Render(versions); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "Pruned %d versions of service '%s'", len(versions), service.Name)
+
+	return nil
+}
+
+func (cmd *ServiceVersionsPruneCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.keep < 1 {
+		return fmt.Errorf("Keep must be at least 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+func NewServiceWaitCommand(name string, environmentName string, serviceAPI api.ServiceAPI) *ServiceWaitCommand {
+	return &ServiceWaitCommand{
+		ServiceCommand: &ServiceCommand{
+			name:            name,
+			environmentName: environmentName,
+			serviceAPI:      serviceAPI,
+		},
+		timeout: time.Minute * 10,
+	}
+}
+
+type ServiceWaitCommand struct {
+	*ServiceCommand
+	timeout time.Duration
+}
+
+func (cmd *ServiceWaitCommand) WithTimeout(timeout time.Duration) *ServiceWaitCommand {
+	cmd.timeout = timeout
+	return cmd
+}
+
+func (cmd *ServiceWaitCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var (
+		env     = cmd.Environment(ctx)
+		service = cmd.Service(ctx)
+		blue    = ui.NewBannerWriter(w, ui.BlueBold)
+		green   = ui.NewBannerWriter(w, ui.GreenBold)
+	)
+
+	fmt.Fprintf(blue, "Waiting up to %s for service '%s' in the '%s' environment to become stable", cmd.timeout, service.Name, env.Name)
+
+	if err := cmd.serviceAPI.WaitUntilServiceStable(ctx, ctx.Project, env, service, cmd.timeout); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(green, "Service '%s' in the '%s' environment is stable", service.Name, env.Name)
+
+	return nil
+}
+
+func (cmd *ServiceWaitCommand) Validate(ctx *ecso.CommandContext) error {
+	if err := cmd.ServiceCommand.Validate(ctx); err != nil {
+		return err
+	}
+
+	if cmd.timeout <= 0 {
+		return fmt.Errorf("Timeout must be greater than zero")
+	}
+
+	return nil
+}
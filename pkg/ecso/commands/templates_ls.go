@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/resources"
+)
+
+func NewTemplatesLsCommand() *TemplatesLsCommand {
+	return &TemplatesLsCommand{}
+}
+
+type TemplatesLsCommand struct{}
+
+func (cmd *TemplatesLsCommand) Execute(ctx *ecso.CommandContext, r io.Reader, w io.Writer) error {
+	var searchPath []string
+
+	if ctx.Project != nil {
+		searchPath = ctx.Project.TemplateSearchPath()
+	}
+
+	packs, err := resources.ListTemplatePacks(searchPath)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Renderer.Render(packs)
+}
+
+func (cmd *TemplatesLsCommand) Validate(ctx *ecso.CommandContext) error {
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -29,6 +31,7 @@ type Config struct {
 	sessions map[string]*session.Session
 
 	w      io.Writer
+	out    io.Writer
 	reader io.Reader
 }
 
@@ -51,7 +54,9 @@ func (c *Config) ServiceAPI(region string) api.ServiceAPI {
 		route53.New(sess),
 		s3.New(sess),
 		sns.New(sess),
-		sts.New(sess))
+		sts.New(sess),
+		ecr.New(sess),
+		ec2.New(sess))
 }
 
 func (c *Config) EnvironmentAPI(region string) api.EnvironmentAPI {
@@ -64,13 +69,21 @@ func (c *Config) EnvironmentAPI(region string) api.EnvironmentAPI {
 		route53.New(sess),
 		s3.New(sess),
 		sns.New(sess),
-		sts.New(sess))
+		sts.New(sess),
+		ecr.New(sess),
+		ec2.New(sess))
 }
 
 func (c *Config) Writer() io.Writer {
 	return c.w
 }
 
+// OutputWriter is where the results of read commands are written. Progress
+// and diagnostic messages go to Writer
+func (c *Config) OutputWriter() io.Writer {
+	return c.out
+}
+
 func (c *Config) Reader() io.Reader {
 	return c.reader
 }
@@ -91,6 +104,7 @@ func NewConfig(version string, options ...func(*Config)) (*Config, error) {
 	cfg := &Config{
 		Version:  version,
 		w:        os.Stderr,
+		out:      os.Stdout,
 		reader:   os.Stdin,
 		sess:     sess,
 		sessions: make(map[string]*session.Session),
@@ -120,3 +134,9 @@ func WithWriter(w io.Writer) func(*Config) {
 		cfg.w = w
 	}
 }
+
+func WithOutputWriter(w io.Writer) func(*Config) {
+	return func(cfg *Config) {
+		cfg.out = w
+	}
+}
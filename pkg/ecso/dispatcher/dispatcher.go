@@ -1,10 +1,13 @@
 package dispatcher
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/config"
+	"github.com/bernos/ecso/pkg/ecso/ui"
 )
 
 // Dispatcher executes an ecso Command
@@ -28,7 +31,26 @@ func NewDispatcher(project *ecso.Project, cfg *config.Config, prefs *ecso.UserPr
 			return fmt.Errorf("No ecso project file was found")
 		}
 
+		renderer, err := ui.NewRenderer(cfg.OutputWriter(), opt.OutputFormat, opt.OutputTemplate)
+		if err != nil {
+			return err
+		}
+
 		ctx := ecso.NewCommandContext(project, prefs, cfg.Version)
+		ctx.Renderer = renderer
+
+		if opt.Timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			ctx.Context, cancelTimeout = context.WithTimeout(ctx.Context, opt.Timeout)
+			defer cancelTimeout()
+		}
+
+		var cancel context.CancelFunc
+		ctx.Context, cancel = context.WithCancel(ctx.Context)
+		defer cancel()
+
+		stop := cancelOnInterrupt(cancel, cfg.Writer())
+		defer stop()
 
 		cmd, err := factory.Build(cfg)
 		if err != nil {
@@ -39,7 +61,9 @@ func NewDispatcher(project *ecso.Project, cfg *config.Config, prefs *ecso.UserPr
 			return err
 		}
 
-		return cmd.Execute(ctx, cfg.Reader(), cfg.Writer())
+		err = cmd.Execute(ctx, cfg.Reader(), cfg.Writer())
+
+		return handleCancellation(ctx, err, opt.Timeout, cfg.Reader(), cfg.Writer())
 	})
 }
 
@@ -57,6 +81,18 @@ type DispatchOptions struct {
 	// EnsureProjectExists determines whether the dispatcher will return an
 	// error if the Project it is dispatching the Command on is nil
 	EnsureProjectExists bool
+
+	// OutputFormat is the format that command results are rendered in. See
+	// ui.NewRenderer for supported formats
+	OutputFormat string
+
+	// OutputTemplate is the go template used to render command results
+	// when OutputFormat is "template"
+	OutputTemplate string
+
+	// Timeout is the maximum time a command may run for. A timeout of zero
+	// allows commands to run until they complete or are interrupted
+	Timeout time.Duration
 }
 
 // SkipEnsureProjectExists is an option function that will permit dispatching
@@ -66,3 +102,20 @@ func SkipEnsureProjectExists() func(*DispatchOptions) {
 		opt.EnsureProjectExists = false
 	}
 }
+
+// WithOutputFormat is an option function that sets the format and template
+// used to render command results
+func WithOutputFormat(format, tmpl string) func(*DispatchOptions) {
+	return func(opt *DispatchOptions) {
+		opt.OutputFormat = format
+		opt.OutputTemplate = tmpl
+	}
+}
+
+// WithTimeout is an option function that cancels commands that run for
+// longer than timeout
+func WithTimeout(timeout time.Duration) func(*DispatchOptions) {
+	return func(opt *DispatchOptions) {
+		opt.Timeout = timeout
+	}
+}
@@ -0,0 +1,81 @@
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// cancelOnInterrupt calls cancel the first time the process receives
+// SIGINT, so that the running command can stop cleanly. A second SIGINT exits
+// immediately. The returned func stops listening for signals
+func cancelOnInterrupt(cancel context.CancelFunc, w io.Writer) (stop func()) {
+	var (
+		interrupt = make(chan os.Signal, 2)
+		done      = make(chan struct{})
+	)
+
+	signal.Notify(interrupt, os.Interrupt)
+
+	go func() {
+		select {
+		case <-interrupt:
+			fmt.Fprintf(w, "\nInterrupted. Stopping... (press Ctrl-C again to exit immediately)\n")
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-interrupt:
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupt)
+		close(done)
+	}
+}
+
+// handleCancellation deals with the error returned by a command that may
+// have been cancelled. If the command was interrupted while an AWS operation
+// was in progress, the user is asked whether to roll the operation back.
+// Commands that ran out of time return an ecso.TimeoutError
+func handleCancellation(ctx context.Context, err error, timeout time.Duration, r io.Reader, w io.Writer) error {
+	if err == nil {
+		return nil
+	}
+
+	if interrupted, ok := err.(*ecso.InterruptedError); ok && interrupted.Rollback != nil {
+		choice, askErr := ui.Choice(r, w, interrupted.Error(), []string{
+			interrupted.Undo,
+			"Leave it running",
+		})
+
+		if askErr == nil && choice == 0 {
+			if rollbackErr := interrupted.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("%s. Failed to %s. %s", interrupted.Error(), interrupted.Undo, rollbackErr.Error())
+			}
+
+			fmt.Fprintf(w, "Requested: %s\n", interrupted.Undo)
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return ecso.NewTimeoutError("Command did not complete within %s. %s", timeout, err.Error())
+	}
+
+	if ctx.Err() == context.Canceled && !ecso.IsInterruptedError(err) {
+		return fmt.Errorf("Interrupted")
+	}
+
+	return err
+}
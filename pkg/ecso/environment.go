@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"strings"
 )
 
 var (
@@ -24,6 +25,18 @@ var (
 	DefaultRegion = "ap-southeast-2"
 )
 
+const (
+	// ServiceDiscoverySRVLambda is the service discovery mode in which the
+	// environment's service-discovery lambda registers an SRV record in the
+	// environment's DNS zone for each container port
+	ServiceDiscoverySRVLambda = "srv-lambda"
+
+	// ServiceDiscoveryCloudMap is the service discovery mode in which
+	// services register themselves with ECS service discovery, in a private
+	// Cloud Map DNS namespace created by the environment
+	ServiceDiscoveryCloudMap = "cloudmap"
+)
+
 type Environment struct {
 	project *Project
 
@@ -31,6 +44,46 @@ type Environment struct {
 	Region                   string
 	CloudFormationParameters map[string]string
 	CloudFormationTags       map[string]string
+
+	// Protected environments have termination protection enabled on their
+	// cloudformation stack, cannot be taken down, and require confirmation
+	// before they are changed
+	Protected bool `json:",omitempty"`
+
+	// AllowedPrincipals lists the ARNs of the IAM principals that may skip
+	// confirmation when changing a protected environment with --yes
+	AllowedPrincipals []string `json:",omitempty"`
+
+	// Bucket configures the S3 bucket used by the environment, overriding
+	// the project's bucket configuration
+	Bucket *BucketConfiguration `json:",omitempty"`
+
+	// Notifiers configures where deployment events for the environment are
+	// sent
+	Notifiers []*NotifierConfiguration `json:",omitempty"`
+
+	// ServiceDiscovery is either "srv-lambda" or "cloudmap". Defaults to
+	// "srv-lambda"
+	ServiceDiscovery string `json:",omitempty"`
+}
+
+// GetServiceDiscovery returns the service discovery mode of the environment
+func (e *Environment) GetServiceDiscovery() string {
+	if e.ServiceDiscovery == "" {
+		return ServiceDiscoverySRVLambda
+	}
+
+	return e.ServiceDiscovery
+}
+
+// GetServiceDiscoveryDomain returns the DNS domain that services in the
+// environment are registered under
+func (e *Environment) GetServiceDiscoveryDomain() string {
+	if e.GetServiceDiscovery() == ServiceDiscoveryCloudMap {
+		return fmt.Sprintf("%s.local", e.GetClusterName())
+	}
+
+	return fmt.Sprintf("%s.%s", e.GetClusterName(), e.CloudFormationParameters["DNSZone"])
 }
 
 func (e *Environment) GetCloudFormationStackName() string {
@@ -46,13 +99,51 @@ func (e *Environment) GetBaseBucketPrefix() string {
 }
 
 func (e *Environment) GetDeploymentBucketPrefix(version string) string {
-	return path.Join(e.GetBaseBucketPrefix(), "environment", version)
+	return path.Join(e.GetVersionsBucketPrefix(), version)
+}
+
+func (e *Environment) GetVersionsBucketPrefix() string {
+	return path.Join(e.GetBaseBucketPrefix(), "environment")
+}
+
+// GetDryRunBucketPrefix is the prefix that the environment's stack is
+// packaged to for a dry run. It is outside of the versions prefix so that dry
+// runs are never listed as versions
+func (e *Environment) GetDryRunBucketPrefix(version string) string {
+	return path.Join(e.GetBaseBucketPrefix(), "dry-run", version)
 }
 
 func (e *Environment) GetResourceBucketPrefix() string {
 	return path.Join(e.GetBaseBucketPrefix(), "resources")
 }
 
+// GetHistoryBucketPrefix is the prefix that the environment's deployment
+// history is stored under
+func (e *Environment) GetHistoryBucketPrefix() string {
+	return path.Join(e.GetBaseBucketPrefix(), "history")
+}
+
+// GetLockBucketPrefix is the prefix that locks on the environment and its
+// services are stored under
+func (e *Environment) GetLockBucketPrefix() string {
+	return path.Join(e.GetBaseBucketPrefix(), "locks")
+}
+
+// GetLambdaBucketPrefix is the prefix that the environment's lambda function
+// bundles are uploaded to. It is separate from the resource prefix, so that
+// bundles used by earlier versions of the stack are kept when resources are
+// pruned
+func (e *Environment) GetLambdaBucketPrefix() string {
+	return path.Join(e.GetBaseBucketPrefix(), "lambda")
+}
+
+// GetTemplatesBucketPrefix is the prefix that the nested templates of the
+// environment's stack are uploaded to. Like lambda bundles, they are kept
+// apart from the versions that reference them
+func (e *Environment) GetTemplatesBucketPrefix() string {
+	return path.Join(e.GetBaseBucketPrefix(), "templates", "environment")
+}
+
 func (e *Environment) GetCloudFormationTemplateDir() string {
 	return filepath.Join(e.project.Dir(), EnvironmentCloudFormationDir)
 }
@@ -69,6 +160,29 @@ func (e *Environment) GetClusterName() string {
 	return fmt.Sprintf("%s-%s", e.project.Name, e.Name)
 }
 
+// IsAllowedPrincipal returns true if the IAM principal with the given ARN may
+// change the environment without confirmation. The ARN of an assumed role
+// session matches the ARN of the role itself
+func (e *Environment) IsAllowedPrincipal(arn string) bool {
+	candidates := []string{arn}
+
+	// arn:aws:sts::123456789012:assumed-role/role-name/session-name
+	if parts := strings.SplitN(arn, ":", 6); len(parts) == 6 && parts[2] == "sts" && strings.HasPrefix(parts[5], "assumed-role/") {
+		role := strings.Split(strings.TrimPrefix(parts[5], "assumed-role/"), "/")[0]
+		candidates = append(candidates, fmt.Sprintf("%s:%s:iam::%s:role/%s", parts[0], parts[1], parts[4], role))
+	}
+
+	for _, allowed := range e.AllowedPrincipals {
+		for _, candidate := range candidates {
+			if allowed == candidate {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (e *Environment) SetProject(p *Project) {
 	e.project = p
 }
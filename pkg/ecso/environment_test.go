@@ -24,6 +24,11 @@ func TestEnvironmentGetDeploymentBucketPrefix(t *testing.T) {
 		makeTestEnvironment().GetDeploymentBucketPrefix("1.0.0"), t)
 }
 
+func TestEnvironmentGetVersionsBucketPrefix(t *testing.T) {
+	assertEqual("my-project-test/environment",
+		makeTestEnvironment().GetVersionsBucketPrefix(), t)
+}
+
 func TestEnvironmentResourceBucketPrefix(t *testing.T) {
 	assertEqual("my-project-test/resources",
 		makeTestEnvironment().GetResourceBucketPrefix(), t)
@@ -55,6 +60,18 @@ func TestEnvironmentGetClusterName(t *testing.T) {
 		makeTestEnvironment().GetClusterName(), t)
 }
 
+func TestEnvironmentGetServiceDiscoveryDomain(t *testing.T) {
+	env := makeTestEnvironment()
+	env.CloudFormationParameters = map[string]string{"DNSZone": "example.com"}
+
+	assertEqual(ServiceDiscoverySRVLambda, env.GetServiceDiscovery(), t)
+	assertEqual("my-project-test.example.com", env.GetServiceDiscoveryDomain(), t)
+
+	env.ServiceDiscovery = ServiceDiscoveryCloudMap
+
+	assertEqual("my-project-test.local", env.GetServiceDiscoveryDomain(), t)
+}
+
 func TestEnvironmentSetProject(t *testing.T) {
 	project := &Project{}
 	env := &Environment{}
@@ -62,3 +79,62 @@ func TestEnvironmentSetProject(t *testing.T) {
 
 	assertEqual(project, env.project, t)
 }
+
+func TestEnvironmentIsAllowedPrincipal(t *testing.T) {
+	env := makeTestEnvironment()
+	env.AllowedPrincipals = []string{
+		"arn:aws:iam::123456789012:role/deployer",
+		"arn:aws:iam::123456789012:user/ci",
+	}
+
+	tests := []struct {
+		arn  string
+		want bool
+	}{
+		{"arn:aws:iam::123456789012:user/ci", true},
+		{"arn:aws:iam::123456789012:user/someone", false},
+		{"arn:aws:sts::123456789012:assumed-role/deployer/session-1", true},
+		{"arn:aws:sts::123456789012:assumed-role/admin/session-1", false},
+		{"arn:aws:sts::999999999999:assumed-role/deployer/session-1", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := env.IsAllowedPrincipal(test.arn); got != test.want {
+			t.Errorf("IsAllowedPrincipal(%q): want %t, got %t", test.arn, test.want, got)
+		}
+	}
+}
+
+func TestEnvironmentGetBucketConfiguration(t *testing.T) {
+	env := makeTestEnvironment()
+
+	assertEqual(BucketConfiguration{
+		NoncurrentVersionExpirationDays: DefaultNoncurrentVersionExpirationDays,
+	}, *env.GetBucketConfiguration(), t)
+
+	env.project.Bucket = &BucketConfiguration{
+		Name:                            "my-bucket",
+		KMSKeyID:                        "alias/project",
+		NoncurrentVersionExpirationDays: 30,
+	}
+
+	env.Bucket = &BucketConfiguration{
+		KMSKeyID: "alias/test",
+	}
+
+	assertEqual(BucketConfiguration{
+		Name:                            "my-bucket",
+		KMSKeyID:                        "alias/test",
+		NoncurrentVersionExpirationDays: 30,
+	}, *env.GetBucketConfiguration(), t)
+}
+
+func TestNotifierConfigurationAccepts(t *testing.T) {
+	all := &NotifierConfiguration{Type: NotifierTypeSlack}
+	failures := &NotifierConfiguration{Type: NotifierTypeSlack, Events: []string{"failed"}}
+
+	assertEqual(true, all.Accepts("started"), t)
+	assertEqual(true, failures.Accepts("failed"), t)
+	assertEqual(false, failures.Accepts("succeeded"), t)
+}
@@ -46,6 +46,25 @@ func (err *EnvironmentExistsError) Error() string {
 	return fmt.Sprintf("An environment named '%s' already exists for this project.", err.name)
 }
 
+// ProtectedEnvironmentError is returned when a command would take down a
+// protected environment
+type ProtectedEnvironmentError struct {
+	name string
+}
+
+func NewProtectedEnvironmentError(name string) error {
+	return &ProtectedEnvironmentError{name}
+}
+
+func (err *ProtectedEnvironmentError) Error() string {
+	return fmt.Sprintf("The '%s' environment is protected. Run `ecso environment unprotect %s` first if you really mean to take it down.", err.name, err.name)
+}
+
+func IsProtectedEnvironmentError(err error) bool {
+	_, ok := err.(*ProtectedEnvironmentError)
+	return ok
+}
+
 func IsArgumentRequiredError(err error) bool {
 	_, ok := err.(*ArgumentRequiredError)
 	return ok
@@ -55,3 +74,61 @@ func IsOptionRequiredError(err error) bool {
 	_, ok := err.(*OptionRequiredError)
 	return ok
 }
+
+type TimeoutError struct {
+	msg string
+}
+
+func NewTimeoutError(format string, a ...interface{}) error {
+	return &TimeoutError{fmt.Sprintf(format, a...)}
+}
+
+func (err *TimeoutError) Error() string {
+	return err.msg
+}
+
+func IsTimeoutError(err error) bool {
+	_, ok := err.(*TimeoutError)
+	return ok
+}
+
+// DeploymentFailedError is returned when a deployment of an ECS service has
+// failed, rather than just not yet become stable
+type DeploymentFailedError struct {
+	msg string
+}
+
+func NewDeploymentFailedError(format string, a ...interface{}) error {
+	return &DeploymentFailedError{fmt.Sprintf(format, a...)}
+}
+
+func (err *DeploymentFailedError) Error() string {
+	return err.msg
+}
+
+func IsDeploymentFailedError(err error) bool {
+	_, ok := err.(*DeploymentFailedError)
+	return ok
+}
+
+// InterruptedError is returned when a command is cancelled while an AWS
+// operation that it started is still in progress. If the operation can be
+// undone, Undo describes the action and Rollback performs it
+type InterruptedError struct {
+	msg      string
+	Undo     string
+	Rollback func() error
+}
+
+func NewInterruptedError(msg, undo string, rollback func() error) error {
+	return &InterruptedError{msg, undo, rollback}
+}
+
+func (err *InterruptedError) Error() string {
+	return err.msg
+}
+
+func IsInterruptedError(err error) bool {
+	_, ok := err.(*InterruptedError)
+	return ok
+}
@@ -0,0 +1,298 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// ChangeSet is a readable description of the changes a cloudformation change
+// set will make, including the changes to any nested stacks
+type ChangeSet struct {
+	ID         string
+	StackID    string
+	StackName  string
+	Status     string
+	Parameters []*ParameterChange
+	Resources  []*ResourceChange
+}
+
+// ParameterChange is a stack parameter whose value will be changed by a
+// change set
+type ParameterChange struct {
+	Key      string
+	Previous string
+	Value    string
+	IsNew    bool
+}
+
+// ResourceChange is a resource that will be added, modified or removed by a
+// change set
+type ResourceChange struct {
+	Action       string
+	LogicalID    string
+	PhysicalID   string
+	ResourceType string
+	Replacement  string
+	Properties   []string
+	Nested       *ChangeSet
+}
+
+// HasChanges returns true if the change set will change any resources or
+// parameters
+func (cs *ChangeSet) HasChanges() bool {
+	return len(cs.Resources) > 0 || len(cs.Parameters) > 0
+}
+
+// WriteTo renders the change set, with the changes to nested stacks
+// indented beneath the nested stack resource
+func (cs *ChangeSet) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	cs.render(&buf)
+
+	n, err := w.Write(buf.Bytes())
+
+	return int64(n), err
+}
+
+func (cs *ChangeSet) String() string {
+	var buf bytes.Buffer
+	cs.WriteTo(&buf)
+	return buf.String()
+}
+
+func (cs *ChangeSet) render(w io.Writer) {
+	if len(cs.Parameters) > 0 {
+		fmt.Fprintf(w, "Parameters:\n")
+
+		for _, p := range cs.Parameters {
+			if p.IsNew {
+				fmt.Fprintf(w, "  + %s: %s\n", p.Key, p.Value)
+			} else {
+				fmt.Fprintf(w, "  ~ %s: %s => %s\n", p.Key, p.Previous, p.Value)
+			}
+		}
+	}
+
+	if len(cs.Resources) > 0 {
+		fmt.Fprintf(w, "Resources:\n")
+
+		for _, r := range cs.Resources {
+			fmt.Fprintf(w, "  %s %s (%s)%s\n", changeSymbol(r.Action), r.LogicalID, r.ResourceType, replacementNote(r.Replacement))
+
+			if len(r.Properties) > 0 {
+				fmt.Fprintf(w, "      Changed: %s\n", strings.Join(r.Properties, ", "))
+			}
+
+			if r.Nested != nil && r.Nested.HasChanges() {
+				r.Nested.render(ui.NewPrefixWriter(w, "      "))
+			}
+		}
+	}
+
+	if !cs.HasChanges() {
+		fmt.Fprintf(w, "No changes\n")
+	}
+}
+
+func changeSymbol(action string) string {
+	switch action {
+	case cloudformation.ChangeActionAdd:
+		return "+"
+	case cloudformation.ChangeActionModify:
+		return "~"
+	case cloudformation.ChangeActionRemove:
+		return "-"
+	default:
+		return "?"
+	}
+}
+
+func replacementNote(replacement string) string {
+	switch replacement {
+	case cloudformation.ReplacementTrue:
+		return " [replacement]"
+	case cloudformation.ReplacementConditional:
+		return " [may require replacement]"
+	default:
+		return ""
+	}
+}
+
+// DescribeChangeSet fetches a change set and the change sets of any nested
+// stacks, and compares its parameters with those of the existing stack
+func (h *cfnHelper) DescribeChangeSet(changeset string) (*ChangeSet, error) {
+	resp, err := h.describeChangeSetPages(changeset)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &ChangeSet{
+		ID:        aws.StringValue(resp.ChangeSetId),
+		StackID:   aws.StringValue(resp.StackId),
+		StackName: aws.StringValue(resp.StackName),
+		Status:    aws.StringValue(resp.Status),
+	}
+
+	previous, err := h.getStackParameters(cs.StackID)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.Parameters = diffParameters(previous, resp.Parameters)
+
+	for _, change := range resp.Changes {
+		rc := change.ResourceChange
+		if rc == nil {
+			continue
+		}
+
+		resource := &ResourceChange{
+			Action:       aws.StringValue(rc.Action),
+			LogicalID:    aws.StringValue(rc.LogicalResourceId),
+			PhysicalID:   aws.StringValue(rc.PhysicalResourceId),
+			ResourceType: aws.StringValue(rc.ResourceType),
+			Replacement:  aws.StringValue(rc.Replacement),
+			Properties:   changedProperties(rc.Details),
+		}
+
+		if nested := aws.StringValue(rc.ChangeSetId); nested != "" {
+			if resource.Nested, err = h.DescribeChangeSet(nested); err != nil {
+				return nil, err
+			}
+		}
+
+		cs.Resources = append(cs.Resources, resource)
+	}
+
+	return cs, nil
+}
+
+// getStackParameters returns the current parameter values of a stack, or an
+// empty map if the stack has not been created yet
+func (h *cfnHelper) getStackParameters(stackID string) (map[string]string, error) {
+	params := make(map[string]string)
+
+	resp, err := h.cfnClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackID),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stack := range resp.Stacks {
+		if aws.StringValue(stack.StackStatus) == cloudformation.StackStatusReviewInProgress {
+			continue
+		}
+
+		for _, p := range stack.Parameters {
+			params[aws.StringValue(p.ParameterKey)] = aws.StringValue(p.ParameterValue)
+		}
+	}
+
+	return params, nil
+}
+
+func diffParameters(previous map[string]string, params []*cloudformation.Parameter) []*ParameterChange {
+	var (
+		values  = make(map[string]string)
+		keys    = make([]string, 0)
+		changes = make([]*ParameterChange, 0)
+	)
+
+	for _, p := range params {
+		key := aws.StringValue(p.ParameterKey)
+		values[key] = aws.StringValue(p.ParameterValue)
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		old, ok := previous[key]
+
+		if ok && old == values[key] {
+			continue
+		}
+
+		changes = append(changes, &ParameterChange{
+			Key:      key,
+			Previous: old,
+			Value:    values[key],
+			IsNew:    !ok,
+		})
+	}
+
+	return changes
+}
+
+// changedProperties returns the names of the properties and attributes of a
+// resource that caused it to change
+func changedProperties(details []*cloudformation.ResourceChangeDetail) []string {
+	var (
+		seen  = make(map[string]bool)
+		names = make([]string, 0)
+	)
+
+	for _, detail := range details {
+		if detail.Target == nil {
+			continue
+		}
+
+		name := aws.StringValue(detail.Target.Attribute)
+
+		if name == cloudformation.ResourceAttributeProperties {
+			name = aws.StringValue(detail.Target.Name)
+		}
+
+		if aws.StringValue(detail.Target.RequiresRecreation) == cloudformation.RequiresRecreationAlways {
+			name = name + " (replacement)"
+		}
+
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// describeChangeSetPages describes all pages of a change set
+func (h *cfnHelper) describeChangeSetPages(changeset string) (*cloudformation.DescribeChangeSetOutput, error) {
+	var (
+		result *cloudformation.DescribeChangeSetOutput
+		input  = &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(changeset),
+		}
+	)
+
+	for {
+		page, err := h.cfnClient.DescribeChangeSet(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil {
+			result = page
+		} else {
+			result.Changes = append(result.Changes, page.Changes...)
+		}
+
+		if page.NextToken == nil {
+			return result, nil
+		}
+
+		input.NextToken = page.NextToken
+	}
+}
@@ -0,0 +1,187 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+)
+
+func TestChangeSetWriteTo(t *testing.T) {
+	cs := &ChangeSet{
+		Parameters: []*ParameterChange{
+			{Key: "Version", Previous: "v1", Value: "v2"},
+			{Key: "Alarms", Value: "true", IsNew: true},
+		},
+		Resources: []*ResourceChange{
+			{Action: "Add", LogicalID: "Topic", ResourceType: "AWS::SNS::Topic"},
+			{
+				Action:       "Modify",
+				LogicalID:    "Network",
+				ResourceType: "AWS::CloudFormation::Stack",
+				Properties:   []string{"TemplateURL"},
+				Nested: &ChangeSet{
+					Resources: []*ResourceChange{
+						{Action: "Modify", LogicalID: "Subnet", ResourceType: "AWS::EC2::Subnet", Replacement: "True", Properties: []string{"CidrBlock (replacement)"}},
+					},
+				},
+			},
+			{Action: "Remove", LogicalID: "Queue", ResourceType: "AWS::SQS::Queue"},
+		},
+	}
+
+	want := strings.Join([]string{
+		"Parameters:",
+		"  ~ Version: v1 => v2",
+		"  + Alarms: true",
+		"Resources:",
+		"  + Topic (AWS::SNS::Topic)",
+		"  ~ Network (AWS::CloudFormation::Stack)",
+		"      Changed: TemplateURL",
+		"      Resources:",
+		"        ~ Subnet (AWS::EC2::Subnet) [replacement]",
+		"            Changed: CidrBlock (replacement)",
+		"  - Queue (AWS::SQS::Queue)",
+		"",
+	}, "\n")
+
+	if got := cs.String(); got != want {
+		t.Errorf("Want:\n%s\nGot:\n%s", want, got)
+	}
+}
+
+func TestChangeSetWriteToNoChanges(t *testing.T) {
+	if got := (&ChangeSet{}).String(); got != "No changes\n" {
+		t.Errorf("Want 'No changes', got %q", got)
+	}
+}
+
+func TestDiffParameters(t *testing.T) {
+	previous := map[string]string{"A": "1", "B": "2"}
+
+	changes := diffParameters(previous, []*cloudformation.Parameter{
+		{ParameterKey: aws.String("B"), ParameterValue: aws.String("3")},
+		{ParameterKey: aws.String("A"), ParameterValue: aws.String("1")},
+		{ParameterKey: aws.String("C"), ParameterValue: aws.String("4")},
+	})
+
+	if len(changes) != 2 {
+		t.Fatalf("Want 2 changes, got %d", len(changes))
+	}
+
+	if changes[0].Key != "B" || changes[0].Previous != "2" || changes[0].Value != "3" || changes[0].IsNew {
+		t.Errorf("Unexpected change %+v", changes[0])
+	}
+
+	if changes[1].Key != "C" || !changes[1].IsNew {
+		t.Errorf("Unexpected change %+v", changes[1])
+	}
+}
+
+func TestChangedProperties(t *testing.T) {
+	target := func(attribute, name, recreation string) *cloudformation.ResourceChangeDetail {
+		return &cloudformation.ResourceChangeDetail{
+			Target: &cloudformation.ResourceTargetDefinition{
+				Attribute:          aws.String(attribute),
+				Name:               aws.String(name),
+				RequiresRecreation: aws.String(recreation),
+			},
+		}
+	}
+
+	got := changedProperties([]*cloudformation.ResourceChangeDetail{
+		target("Properties", "TaskDefinition", "Never"),
+		target("Tags", "", "Never"),
+		target("Properties", "TaskDefinition", "Never"),
+		target("Properties", "Name", "Always"),
+	})
+
+	want := []string{"Name (replacement)", "Tags", "TaskDefinition"}
+
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Want %v, got %v", want, got)
+	}
+}
+
+type changeSetCfnMock struct {
+	cloudformationiface.CloudFormationAPI
+
+	changeSets map[string][]*cloudformation.DescribeChangeSetOutput
+}
+
+func (m *changeSetCfnMock) DescribeChangeSet(input *cloudformation.DescribeChangeSetInput) (*cloudformation.DescribeChangeSetOutput, error) {
+	pages := m.changeSets[*input.ChangeSetName]
+	page := 0
+
+	if input.NextToken != nil {
+		page, _ = strconv.Atoi(*input.NextToken)
+	}
+
+	return pages[page], nil
+}
+
+func (m *changeSetCfnMock) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	return &cloudformation.DescribeStacksOutput{}, nil
+}
+
+func TestDescribeChangeSetIncludesNestedChangeSets(t *testing.T) {
+	resource := func(action, logicalID, changeSetID string) *cloudformation.Change {
+		rc := &cloudformation.ResourceChange{
+			Action:            aws.String(action),
+			LogicalResourceId: aws.String(logicalID),
+			ResourceType:      aws.String("AWS::CloudFormation::Stack"),
+		}
+
+		if changeSetID != "" {
+			rc.ChangeSetId = aws.String(changeSetID)
+		}
+
+		return &cloudformation.Change{ResourceChange: rc}
+	}
+
+	h := &cfnHelper{
+		cfnClient: &changeSetCfnMock{
+			changeSets: map[string][]*cloudformation.DescribeChangeSetOutput{
+				"root": {
+					{
+						ChangeSetId: aws.String("root"),
+						StackId:     aws.String("root-stack"),
+						Changes:     []*cloudformation.Change{resource("Add", "Topic", "")},
+						NextToken:   aws.String("1"),
+					},
+					{
+						Changes: []*cloudformation.Change{resource("Modify", "Network", "nested")},
+					},
+				},
+				"nested": {
+					{
+						ChangeSetId: aws.String("nested"),
+						StackId:     aws.String("nested-stack"),
+						Changes:     []*cloudformation.Change{resource("Remove", "Subnet", "")},
+					},
+				},
+			},
+		},
+	}
+
+	cs, err := h.DescribeChangeSet("root")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cs.Resources) != 2 {
+		t.Fatalf("Want 2 resource changes across both pages, got %d", len(cs.Resources))
+	}
+
+	nested := cs.Resources[1].Nested
+	if nested == nil {
+		t.Fatalf("Want the nested change set to be described")
+	}
+
+	if len(nested.Resources) != 1 || nested.Resources[0].LogicalID != "Subnet" {
+		t.Errorf("Unexpected nested changes %+v", nested.Resources)
+	}
+}
@@ -1,10 +1,12 @@
 package helpers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -19,11 +21,16 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/bernos/ecso/pkg/ecso"
 	"github.com/bernos/ecso/pkg/ecso/ui"
 )
 
 var (
 	childTemplateRegexp = regexp.MustCompile(`(\s*)TemplateURL:\s*(\./)*(.+)`)
+
+	// packagedTemplateURLRegexp matches the nested template urls written by
+	// updateNestedTemplateURLs, capturing the bucket and key
+	packagedTemplateURLRegexp = regexp.MustCompile(`TemplateURL:\s*https://[^/\s]+/([^/\s]+)/(\S+)`)
 )
 
 // DeploymentResult holds information about a successful cloud formation
@@ -32,19 +39,33 @@ type DeploymentResult struct {
 	StackID            string
 	ChangeSetID        string
 	DidRequireUpdating bool
+	IsCreate           bool
 }
 
 // CloudFormationHelper contains high level helper functions for dealing with
 // cloud formation
 type CloudFormationHelper interface {
-	DeleteStack(serviceName string, w io.Writer) error
-	Deploy(pkg *Package, stackName string, dryRun bool, w io.Writer) (*DeploymentResult, error)
+	DeleteStack(ctx context.Context, stackName string, w io.Writer) error
+	Deploy(ctx context.Context, pkg *Package, stackName string, dryRun bool, w io.Writer) (*DeploymentResult, error)
+	CreateChangeSet(ctx context.Context, pkg *Package, stackName string, w io.Writer) (*DeploymentResult, error)
+	ExecuteChangeSet(ctx context.Context, result *DeploymentResult, w io.Writer) error
+	DeleteChangeSet(changeset string) error
+	CancelUpdate(stackID string) error
+	SetTerminationProtection(stackName string, enabled bool) error
+	DeleteStaleChangeSets(stackName, keep string, w io.Writer) error
 	GetChangeSet(changeset string) (*cloudformation.DescribeChangeSetOutput, error)
+	DescribeChangeSet(changeset string) (*ChangeSet, error)
+	DetectDrift(ctx context.Context, stackName, templateFile string, w io.Writer) (*StackDrift, error)
+	DiagnoseFailure(stackName string) (*StackFailure, error)
 	GetStackOutputs(stackName string) (map[string]string, error)
-	Package(templateFile, bucket, prefix string, tags, params map[string]string, w io.Writer) (*Package, error)
+	GetStackTags(stackName string) (map[string]string, error)
+	GetStackLastUpdated(stackName string) (time.Time, error)
+	Package(ctx context.Context, templateFile, bucket, prefix, templatesPrefix string, tags, params map[string]string, w io.Writer) (*Package, error)
 	StackExists(stackName string) (bool, error)
-	WaitForChangeset(changeset string, status ...string) (*cloudformation.DescribeChangeSetOutput, error)
-	PackageIsUploadedToS3(pkg *Package) (bool, error)
+	WaitForChangeset(ctx context.Context, changeset string, status ...string) (*cloudformation.DescribeChangeSetOutput, error)
+	PackageIsUploadedToS3(ctx context.Context, pkg *Package) (bool, error)
+	GetPackageTemplate(ctx context.Context, pkg *Package) ([]byte, error)
+	CopyPackage(ctx context.Context, pkg *Package, prefix string, tags, params map[string]string, w io.Writer) (*Package, error)
 }
 
 // NewCloudFormationHelper creates a CloudFormationHelper
@@ -88,11 +109,35 @@ func (h *cfnHelper) GetStackOutputs(stackName string) (map[string]string, error)
 	return outputs, nil
 }
 
+func (h *cfnHelper) GetStackTags(stackName string) (map[string]string, error) {
+	resp, err := h.cfnClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+
+	for _, stack := range resp.Stacks {
+		if *stack.StackName == stackName {
+			for _, tag := range stack.Tags {
+				tags[*tag.Key] = *tag.Value
+			}
+		}
+	}
+
+	return tags, nil
+}
+
 // Package creates a Package from local cloudformation template file. Any child templates in the
 // template file will be uploaded to S3, as well as the template file itself. Before the template
 // is uploaded, and relative references to child templates will be updated with the fully qualified
-// S3 url that they were uploaded to. The resulting Package can be deployed using the Deploy method
-func (h *cfnHelper) Package(templateFile, bucket, prefix string, tags, params map[string]string, w io.Writer) (*Package, error) {
+// S3 url that they were uploaded to. Child templates are uploaded under templatesPrefix, rather than
+// the package's prefix, so that they can be shared by packages. The resulting Package can be
+// deployed using the Deploy method
+func (h *cfnHelper) Package(ctx context.Context, templateFile, bucket, prefix, templatesPrefix string, tags, params map[string]string, w io.Writer) (*Package, error) {
 	pkg := NewPackage(bucket, prefix, h.region)
 
 	fmt.Fprintf(w, "Creating deployment package at %s\n", pkg.GetURL())
@@ -101,7 +146,6 @@ func (h *cfnHelper) Package(templateFile, bucket, prefix string, tags, params ma
 		return nil, err
 	}
 
-	templatePrefix := pkg.GetTemplateBucketPrefix()
 	basedir := filepath.Dir(templateFile)
 
 	templateBody, err := ioutil.ReadFile(templateFile)
@@ -109,37 +153,38 @@ func (h *cfnHelper) Package(templateFile, bucket, prefix string, tags, params ma
 		return pkg, err
 	}
 
-	if err := h.uploadChildTemplates(basedir, string(templateBody), bucket, templatePrefix, w); err != nil {
+	keys, err := h.uploadChildTemplates(ctx, basedir, string(templateBody), bucket, templatesPrefix, w)
+	if err != nil {
 		return pkg, err
 	}
 
-	body := updateNestedTemplateURLs(string(templateBody), h.region, bucket, templatePrefix)
+	body := updateNestedTemplateURLs(string(templateBody), h.region, bucket, keys)
 
 	if err := h.validateTemplate([]byte(body)); err != nil {
 		return pkg, err
 	}
 
-	if err := h.uploadTemplate(strings.NewReader(body), bucket, pkg.GetTemplateBucketKey(), w); err != nil {
+	if err := h.uploadTemplate(ctx, strings.NewReader(body), bucket, pkg.GetTemplateBucketKey(), w); err != nil {
 		return pkg, err
 	}
 
 	s3Helper := NewS3Helper(h.s3Client, h.region)
 
 	fmt.Fprintf(w, "Uploading cloud formation tags to %s\n", pkg.GetTagsBucketKey())
-	if err := s3Helper.UploadObjectJSON(tags, bucket, pkg.GetTagsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
+	if err := s3Helper.UploadObjectJSON(ctx, tags, bucket, pkg.GetTagsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
 		return nil, err
 	}
 
 	fmt.Fprintf(w, "Uploading cloud formation params to %s\n", pkg.GetParamsBucketKey())
-	if err := s3Helper.UploadObjectJSON(params, bucket, pkg.GetParamsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
+	if err := s3Helper.UploadObjectJSON(ctx, params, bucket, pkg.GetParamsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
 		return nil, err
 	}
 
 	return pkg, nil
 }
 
-func (h *cfnHelper) PackageIsUploadedToS3(pkg *Package) (bool, error) {
-	if _, err := h.s3Client.HeadObject(&s3.HeadObjectInput{
+func (h *cfnHelper) PackageIsUploadedToS3(ctx context.Context, pkg *Package) (bool, error) {
+	if _, err := h.s3Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(pkg.bucket),
 		Key:    aws.String(pkg.GetTemplateBucketKey()),
 	}); err != nil {
@@ -156,12 +201,71 @@ func (h *cfnHelper) PackageIsUploadedToS3(pkg *Package) (bool, error) {
 	return true, nil
 }
 
-func (h *cfnHelper) Deploy(pkg *Package, stackName string, dryRun bool, w io.Writer) (*DeploymentResult, error) {
+// GetPackageTemplate downloads the root template of a package
+func (h *cfnHelper) GetPackageTemplate(ctx context.Context, pkg *Package) ([]byte, error) {
+	resp, err := h.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(pkg.bucket),
+		Key:    aws.String(pkg.GetTemplateBucketKey()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// CopyPackage copies the root template of pkg to a new package at prefix,
+// with the given tags and params. Nested templates are referenced by their
+// S3 urls, so they are shared with the original package rather than copied
+func (h *cfnHelper) CopyPackage(ctx context.Context, pkg *Package, prefix string, tags, params map[string]string, w io.Writer) (*Package, error) {
+	copied := NewPackage(pkg.bucket, prefix, h.region)
+
+	fmt.Fprintf(w, "Creating deployment package at %s\n", copied.GetURL())
+	fmt.Fprintf(w, "Copying cloudformation template to 's3://%s/%s'\n", copied.bucket, copied.GetTemplateBucketKey())
+
+	if _, err := h.s3Client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(copied.bucket),
+		Key:        aws.String(copied.GetTemplateBucketKey()),
+		CopySource: aws.String(path.Join(pkg.bucket, pkg.GetTemplateBucketKey())),
+	}); err != nil {
+		return nil, err
+	}
+
+	s3Helper := NewS3Helper(h.s3Client, h.region)
+
+	fmt.Fprintf(w, "Uploading cloud formation tags to %s\n", copied.GetTagsBucketKey())
+	if err := s3Helper.UploadObjectJSON(ctx, tags, copied.bucket, copied.GetTagsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "Uploading cloud formation params to %s\n", copied.GetParamsBucketKey())
+	if err := s3Helper.UploadObjectJSON(ctx, params, copied.bucket, copied.GetParamsBucketKey(), ui.NewPrefixWriter(w, "  ")); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+func (h *cfnHelper) Deploy(ctx context.Context, pkg *Package, stackName string, dryRun bool, w io.Writer) (*DeploymentResult, error) {
+	result, err := h.CreateChangeSet(ctx, pkg, stackName, w)
+	if err != nil || !result.DidRequireUpdating || dryRun {
+		return result, err
+	}
+
+	return result, h.ExecuteChangeSet(ctx, result, w)
+}
+
+// CreateChangeSet creates a change set that deploys pkg to the stack, and
+// waits for it to be ready. Change sets that contain no changes are deleted,
+// and the returned result's DidRequireUpdating field is false
+func (h *cfnHelper) CreateChangeSet(ctx context.Context, pkg *Package, stackName string, w io.Writer) (*DeploymentResult, error) {
 	fmt.Fprintf(w, "Deploying package from %s\n", pkg.GetURL())
 
 	s3Helper := NewS3Helper(h.s3Client, h.region)
 
-	versionExists, err := h.PackageIsUploadedToS3(pkg)
+	versionExists, err := h.PackageIsUploadedToS3(ctx, pkg)
 	if err != nil {
 		return nil, err
 	}
@@ -175,12 +279,12 @@ func (h *cfnHelper) Deploy(pkg *Package, stackName string, dryRun bool, w io.Wri
 	tags := make(map[string]string)
 
 	fmt.Fprintf(w, "Downloading stack params \n")
-	if err := s3Helper.DownloadObjectJSON(&params, pkg.bucket, pkg.GetParamsBucketKey()); err != nil {
+	if err := s3Helper.DownloadObjectJSON(ctx, &params, pkg.bucket, pkg.GetParamsBucketKey()); err != nil {
 		return nil, err
 	}
 
 	fmt.Fprintf(w, "Downloading stack tags \n")
-	if err := s3Helper.DownloadObjectJSON(&tags, pkg.bucket, pkg.GetTagsBucketKey()); err != nil {
+	if err := s3Helper.DownloadObjectJSON(ctx, &tags, pkg.bucket, pkg.GetTagsBucketKey()); err != nil {
 		return nil, err
 	}
 
@@ -191,6 +295,7 @@ func (h *cfnHelper) Deploy(pkg *Package, stackName string, dryRun bool, w io.Wri
 		Tags:                make([]*cloudformation.Tag, 0),
 		TemplateURL:         aws.String(pkg.GetTemplateURL()),
 		UsePreviousTemplate: aws.Bool(false),
+		IncludeNestedStacks: aws.Bool(true),
 		Capabilities: []*string{
 			aws.String("CAPABILITY_NAMED_IAM"),
 			aws.String("CAPABILITY_IAM"),
@@ -227,8 +332,7 @@ func (h *cfnHelper) Deploy(pkg *Package, stackName string, dryRun bool, w io.Wri
 
 	fmt.Fprintf(w, "Creating changeset...\n")
 
-	changeset, err := h.cfnClient.CreateChangeSet(input)
-
+	changeset, err := h.cfnClient.CreateChangeSetWithContext(ctx, input)
 	if err != nil {
 		return nil, err
 	}
@@ -237,51 +341,200 @@ func (h *cfnHelper) Deploy(pkg *Package, stackName string, dryRun bool, w io.Wri
 		StackID:            *changeset.StackId,
 		ChangeSetID:        *changeset.Id,
 		DidRequireUpdating: true,
+		IsCreate:           !exists,
 	}
 
 	fmt.Fprintf(w, "Waiting for changeset %s to be ready...\n", *changeset.Id)
 
-	if changeSetDescription, err := h.WaitForChangeset(*changeset.Id, cloudformation.ChangeSetStatusCreateComplete, cloudformation.ChangeSetStatusFailed); err != nil {
+	changeSetDescription, err := h.WaitForChangeset(ctx, *changeset.Id, cloudformation.ChangeSetStatusCreateComplete, cloudformation.ChangeSetStatusFailed)
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, ecso.NewInterruptedError(
+				fmt.Sprintf("Interrupted while creating changeset %s", *changeset.Id),
+				fmt.Sprintf("Delete changeset %s", *changeset.Id),
+				func() error { return h.DeleteChangeSet(*changeset.Id) })
+		}
 		return result, err
-	} else if len(changeSetDescription.Changes) == 0 {
+	}
+
+	if *changeSetDescription.Status == cloudformation.ChangeSetStatusFailed || len(changeSetDescription.Changes) == 0 {
+		if err := h.DeleteChangeSet(*changeset.Id); err != nil {
+			fmt.Fprintf(w, "WARNING Failed to delete changeset %s. %s\n", *changeset.Id, err.Error())
+		}
+
+		if !isEmptyChangeSet(changeSetDescription) {
+			return result, fmt.Errorf("Failed to create changeset %s. %s", *changeset.Id, aws.StringValue(changeSetDescription.StatusReason))
+		}
+
 		result.DidRequireUpdating = false
 		return result, nil
 	}
 
 	fmt.Fprintf(w, "Created changeset %s\n", *changeset.Id)
 
-	if dryRun {
-		return result, nil
-	}
+	return result, nil
+}
 
-	if _, err := h.cfnClient.ExecuteChangeSet(&cloudformation.ExecuteChangeSetInput{
-		ChangeSetName: changeset.Id,
-		StackName:     changeset.StackId,
+// ExecuteChangeSet executes a change set created by CreateChangeSet, and
+// waits for the stack update to complete
+func (h *cfnHelper) ExecuteChangeSet(ctx context.Context, result *DeploymentResult, w io.Writer) error {
+	if _, err := h.cfnClient.ExecuteChangeSetWithContext(ctx, &cloudformation.ExecuteChangeSetInput{
+		ChangeSetName: aws.String(result.ChangeSetID),
+		StackName:     aws.String(result.StackID),
 	}); err != nil {
-		return result, err
-	}
-
-	stack := &cloudformation.DescribeStacksInput{
-		StackName: aws.String(stackName),
+		return err
 	}
 
 	childWriter := ui.NewPrefixWriter(w, "  ")
 
-	cancel := h.LogStackEvents(*changeset.StackId, func(ev *cloudformation.StackEvent, err error) {
+	cancel := h.LogStackEvents(ctx, result.StackID, func(ev *cloudformation.StackEvent, err error) {
 		if ev != nil {
-			fmt.Fprintf(childWriter, "%s: %s\n", *ev.LogicalResourceId, *ev.ResourceStatus)
+			fmt.Fprintf(childWriter, "%s\n", formatStackEvent(ev))
 		}
 	})
 
 	defer cancel()
 
-	if exists {
+	if !result.IsCreate {
 		fmt.Fprintf(w, "Waiting for stack update to complete...\n")
-		return result, h.cfnClient.WaitUntilStackUpdateComplete(stack)
+
+		err := h.waitForStack(ctx, result.StackID, cloudformation.StackStatusUpdateComplete, w)
+		if err != nil && ctx.Err() != nil {
+			return ecso.NewInterruptedError(
+				fmt.Sprintf("Interrupted while stack %s was being updated. The update is still in progress", result.StackID),
+				"Cancel the stack update and roll back",
+				func() error { return h.CancelUpdate(result.StackID) })
+		}
+
+		return err
 	}
 
 	fmt.Fprintf(w, "Waiting for stack creation to complete...\n")
-	return result, h.cfnClient.WaitUntilStackCreateComplete(stack)
+
+	err := h.waitForStack(ctx, result.StackID, cloudformation.StackStatusCreateComplete, w)
+	if err != nil && ctx.Err() != nil {
+		return ecso.NewInterruptedError(fmt.Sprintf("Interrupted while stack %s was being created. The stack creation is still in progress", result.StackID), "", nil)
+	}
+
+	return err
+}
+
+// CancelUpdate cancels an update that is in progress, rolling the stack back
+// to its previous state
+func (h *cfnHelper) CancelUpdate(stackID string) error {
+	_, err := h.cfnClient.CancelUpdateStack(&cloudformation.CancelUpdateStackInput{
+		StackName: aws.String(stackID),
+	})
+
+	return err
+}
+
+// SetTerminationProtection enables or disables termination protection on a
+// stack. Stacks with termination protection enabled cannot be deleted
+func (h *cfnHelper) SetTerminationProtection(stackName string, enabled bool) error {
+	_, err := h.cfnClient.UpdateTerminationProtection(&cloudformation.UpdateTerminationProtectionInput{
+		EnableTerminationProtection: aws.Bool(enabled),
+		StackName:                   aws.String(stackName),
+	})
+
+	return err
+}
+
+// DeleteChangeSet deletes a change set that will not be executed
+func (h *cfnHelper) DeleteChangeSet(changeset string) error {
+	_, err := h.cfnClient.DeleteChangeSet(&cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(changeset),
+	})
+
+	return err
+}
+
+// DeleteStaleChangeSets deletes all change sets created by ecso for the
+// stack that can no longer be executed, or that were never executed, except
+// for the change set named by keep
+func (h *cfnHelper) DeleteStaleChangeSets(stackName, keep string, w io.Writer) error {
+	exists, err := h.StackExists(stackName)
+	if err != nil || !exists {
+		return err
+	}
+
+	params := &cloudformation.ListChangeSetsInput{
+		StackName: aws.String(stackName),
+	}
+
+	for {
+		resp, err := h.cfnClient.ListChangeSets(params)
+		if err != nil {
+			return err
+		}
+
+		for _, summary := range resp.Summaries {
+			if aws.StringValue(summary.ChangeSetId) == keep || aws.StringValue(summary.ChangeSetName) == keep {
+				continue
+			}
+
+			if !strings.HasPrefix(aws.StringValue(summary.ChangeSetName), stackName+"-") {
+				continue
+			}
+
+			if aws.StringValue(summary.ExecutionStatus) == cloudformation.ExecutionStatusExecuteInProgress {
+				continue
+			}
+
+			fmt.Fprintf(w, "Deleting stale changeset %s\n", aws.StringValue(summary.ChangeSetName))
+
+			if err := h.DeleteChangeSet(aws.StringValue(summary.ChangeSetId)); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextToken == nil {
+			return nil
+		}
+
+		params.NextToken = resp.NextToken
+	}
+}
+
+// GetStackLastUpdated returns the time that the stack was last created or
+// updated, or the zero time if the stack does not exist
+func (h *cfnHelper) GetStackLastUpdated(stackName string) (time.Time, error) {
+	exists, err := h.StackExists(stackName)
+	if err != nil || !exists {
+		return time.Time{}, err
+	}
+
+	resp, err := h.cfnClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(resp.Stacks) == 0 {
+		return time.Time{}, nil
+	}
+
+	stack := resp.Stacks[0]
+
+	if stack.LastUpdatedTime != nil {
+		return stack.LastUpdatedTime.UTC(), nil
+	}
+
+	return aws.TimeValue(stack.CreationTime).UTC(), nil
+}
+
+// isEmptyChangeSet returns true if the change set failed only because the
+// stack was already up to date
+func isEmptyChangeSet(changeset *cloudformation.DescribeChangeSetOutput) bool {
+	if *changeset.Status != cloudformation.ChangeSetStatusFailed {
+		return len(changeset.Changes) == 0
+	}
+
+	reason := aws.StringValue(changeset.StatusReason)
+
+	return strings.Contains(reason, "didn't contain changes") || strings.Contains(reason, "No updates are to be performed")
 }
 
 func (h *cfnHelper) GetChangeSet(changeset string) (*cloudformation.DescribeChangeSetOutput, error) {
@@ -292,8 +545,8 @@ func (h *cfnHelper) GetChangeSet(changeset string) (*cloudformation.DescribeChan
 	return h.cfnClient.DescribeChangeSet(params)
 }
 
-func (h *cfnHelper) DeleteStack(stackName string, w io.Writer) error {
-	_, err := h.cfnClient.DeleteStack(&cloudformation.DeleteStackInput{
+func (h *cfnHelper) DeleteStack(ctx context.Context, stackName string, w io.Writer) error {
+	_, err := h.cfnClient.DeleteStackWithContext(ctx, &cloudformation.DeleteStackInput{
 		StackName: aws.String(stackName),
 	})
 
@@ -303,9 +556,9 @@ func (h *cfnHelper) DeleteStack(stackName string, w io.Writer) error {
 
 	childWriter := ui.NewPrefixWriter(w, "  ")
 
-	cancel := h.LogStackEvents(stackName, func(ev *cloudformation.StackEvent, err error) {
+	cancel := h.LogStackEvents(ctx, stackName, func(ev *cloudformation.StackEvent, err error) {
 		if ev != nil {
-			fmt.Fprintf(childWriter, "%s: %s\n", *ev.LogicalResourceId, *ev.ResourceStatus)
+			fmt.Fprintf(childWriter, "%s\n", formatStackEvent(ev))
 		}
 	})
 
@@ -313,9 +566,12 @@ func (h *cfnHelper) DeleteStack(stackName string, w io.Writer) error {
 
 	fmt.Fprintf(w, "Waiting for stack delete to complete...\n")
 
-	return h.cfnClient.WaitUntilStackDeleteComplete(&cloudformation.DescribeStacksInput{
-		StackName: aws.String(stackName),
-	})
+	err = h.waitForStack(ctx, stackName, cloudformation.StackStatusDeleteComplete, w)
+	if err != nil && ctx.Err() != nil {
+		return ecso.NewInterruptedError(fmt.Sprintf("Interrupted while stack %s was being deleted. The deletion is still in progress", stackName), "", nil)
+	}
+
+	return err
 }
 
 func (h *cfnHelper) StackExists(stackName string) (bool, error) {
@@ -352,7 +608,7 @@ func (h *cfnHelper) StackExists(stackName string) (bool, error) {
 	return found, err
 }
 
-func (h *cfnHelper) WaitForChangeset(changeset string, status ...string) (*cloudformation.DescribeChangeSetOutput, error) {
+func (h *cfnHelper) WaitForChangeset(ctx context.Context, changeset string, status ...string) (*cloudformation.DescribeChangeSetOutput, error) {
 	params := &cloudformation.DescribeChangeSetInput{
 		ChangeSetName: aws.String(changeset),
 	}
@@ -361,7 +617,7 @@ func (h *cfnHelper) WaitForChangeset(changeset string, status ...string) (*cloud
 	timeout := time.Second * 60 * 20
 
 	for {
-		resp, err := h.cfnClient.DescribeChangeSet(params)
+		resp, err := h.cfnClient.DescribeChangeSetWithContext(ctx, params)
 
 		if err != nil {
 			return resp, err
@@ -377,11 +633,13 @@ func (h *cfnHelper) WaitForChangeset(changeset string, status ...string) (*cloud
 			return resp, fmt.Errorf("Changeset %s failed to reach state %s within %s", changeset, status, timeout)
 		}
 
-		time.Sleep(time.Second * 5)
+		if err := sleepWithContext(ctx, time.Second*5); err != nil {
+			return resp, err
+		}
 	}
 }
 
-func (h *cfnHelper) LogStackEvents(stackID string, logger func(*cloudformation.StackEvent, error)) (cancel func()) {
+func (h *cfnHelper) LogStackEvents(ctx context.Context, stackID string, logger func(*cloudformation.StackEvent, error)) (cancel func()) {
 	done := make(chan struct{})
 	ticker := time.NewTicker(time.Second * 5)
 
@@ -394,9 +652,12 @@ func (h *cfnHelper) LogStackEvents(stackID string, logger func(*cloudformation.S
 		var lastEventID string
 
 		for {
-			resp, err := h.cfnClient.DescribeStackEvents(params)
+			resp, err := h.cfnClient.DescribeStackEventsWithContext(ctx, params)
 
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				logger(nil, err)
 			} else {
 				if len(resp.StackEvents) > 0 {
@@ -423,6 +684,8 @@ func (h *cfnHelper) LogStackEvents(stackID string, logger func(*cloudformation.S
 			select {
 			case <-done:
 				return
+			case <-ctx.Done():
+				return
 			case <-ticker.C:
 			}
 		}
@@ -433,28 +696,62 @@ func (h *cfnHelper) LogStackEvents(stackID string, logger func(*cloudformation.S
 	}
 }
 
-func (h *cfnHelper) uploadChildTemplates(basedir, templateBody, bucket, prefix string, w io.Writer) error {
-	files := findNestedTemplateFiles(templateBody)
+// uploadChildTemplates syncs each of the nested templates referenced by the
+// template body to S3. Nested templates are stored by content, so templates
+// that have not changed since an earlier deployment are not uploaded again.
+// The returned map contains the S3 key of each nested template file
+func (h *cfnHelper) uploadChildTemplates(ctx context.Context, basedir, templateBody, bucket, prefix string, w io.Writer) (map[string]string, error) {
+	var (
+		files   = findNestedTemplateFiles(templateBody)
+		keys    = make(map[string]string)
+		objects = make([]*SyncObject, 0, len(files))
+	)
 
 	for _, file := range files {
-		if err := h.validateTemplateFile(filepath.Join(basedir, file), w); err != nil {
-			return err
+		filename := filepath.Join(basedir, file)
+
+		if err := h.validateTemplateFile(filename, w); err != nil {
+			return nil, err
+		}
+
+		body, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
 		}
+
+		hash := sha256.Sum256(body)
+		key := path.Join(prefix, hex.EncodeToString(hash[:])[:16], path.Base(file))
+
+		keys[file] = key
+		objects = append(objects, &SyncObject{Key: key, Body: body, Source: filename})
 	}
 
+	// Always sync, even with no nested templates, as it ensures the bucket
+	// exists before the root template is uploaded
 	s3Helper := NewS3Helper(h.s3Client, h.region)
-	err := s3Helper.EnsureBucket(bucket, ui.NewPrefixWriter(w, "  "))
+
+	result, err := s3Helper.Sync(ctx, objects, bucket, nil, ui.NewPrefixWriter(w, "  "))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, file := range files {
-		if err := h.uploadTemplateFile(basedir, file, bucket, prefix, w); err != nil {
-			return err
+	fmt.Fprintf(w, "Nested templates synced: %s\n", result)
+
+	return keys, nil
+}
+
+// GetNestedTemplateKeys returns the S3 keys of the nested templates that a
+// packaged template references in the bucket
+func GetNestedTemplateKeys(templateBody, bucket string) []string {
+	keys := make([]string, 0)
+
+	for _, match := range packagedTemplateURLRegexp.FindAllStringSubmatch(templateBody, -1) {
+		if match[1] == bucket {
+			keys = append(keys, match[2])
 		}
 	}
 
-	return nil
+	return keys
 }
 
 func findNestedTemplateFiles(templateBody string) []string {
@@ -468,25 +765,22 @@ func findNestedTemplateFiles(templateBody string) []string {
 	return files
 }
 
-func updateNestedTemplateURLs(templateBody, region, bucket, prefix string) string {
-	repl := fmt.Sprintf("${1}TemplateURL: https://s3-%s.amazonaws.com/%s/%s/$3", region, bucket, prefix)
-	return childTemplateRegexp.ReplaceAllString(templateBody, repl)
-}
+// updateNestedTemplateURLs replaces the relative path of each nested template
+// with the S3 url of the key it was uploaded to
+func updateNestedTemplateURLs(templateBody, region, bucket string, keys map[string]string) string {
+	return childTemplateRegexp.ReplaceAllStringFunc(templateBody, func(match string) string {
+		groups := childTemplateRegexp.FindStringSubmatch(match)
 
-func (h *cfnHelper) uploadTemplateFile(basedir, file, bucket, prefix string, w io.Writer) error {
-	reader, err := os.Open(path.Join(basedir, file))
-	if err != nil {
-		return err
-	}
-
-	defer reader.Close()
-
-	key := path.Join(prefix, file)
+		key, ok := keys[groups[3]]
+		if !ok {
+			return match
+		}
 
-	return h.uploadTemplate(reader, bucket, key, w)
+		return fmt.Sprintf("%sTemplateURL: https://s3-%s.amazonaws.com/%s/%s", groups[1], region, bucket, key)
+	})
 }
 
-func (h *cfnHelper) uploadTemplate(r io.Reader, bucket, key string, w io.Writer) error {
+func (h *cfnHelper) uploadTemplate(ctx context.Context, r io.Reader, bucket, key string, w io.Writer) error {
 	params := &s3manager.UploadInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
@@ -495,7 +789,7 @@ func (h *cfnHelper) uploadTemplate(r io.Reader, bucket, key string, w io.Writer)
 
 	fmt.Fprintf(w, "Uploading cloudformation template to 's3://%s/%s'\n", bucket, key)
 
-	if _, err := h.uploader.Upload(params); err != nil {
+	if _, err := h.uploader.UploadWithContext(ctx, params); err != nil {
 		return err
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/bernos/ecso/pkg/ecso/api/mocks"
 )
@@ -96,13 +97,62 @@ func TestFindNestedTemplateFiles(t *testing.T) {
 
 func TestUpdateNestedTemplateURLs(t *testing.T) {
 	want := MustReadFile(t, "./testdata/packaged_root_template.yaml")
-	got := updateNestedTemplateURLs(MustReadFile(t, "./testdata/root_template.yaml"), "ap-southeast-2", "bucketname", "my/bucket/prefix")
+	keys := map[string]string{
+		"infrastructure/security-groups.yaml": "my/bucket/prefix/infrastructure/security-groups.yaml",
+		"infrastructure/load-balancers.yaml":  "my/bucket/prefix/infrastructure/load-balancers.yaml",
+		"infrastructure/ecs-cluster.yaml":     "my/bucket/prefix/infrastructure/ecs-cluster.yaml",
+	}
+
+	got := updateNestedTemplateURLs(MustReadFile(t, "./testdata/root_template.yaml"), "ap-southeast-2", "bucketname", keys)
 
 	if want != got {
 		t.Errorf("Want %s, got %s", want, got)
 	}
 }
 
+type terminationProtectionCfnMock struct {
+	cloudformationiface.CloudFormationAPI
+	input *cloudformation.UpdateTerminationProtectionInput
+}
+
+func (m *terminationProtectionCfnMock) UpdateTerminationProtection(input *cloudformation.UpdateTerminationProtectionInput) (*cloudformation.UpdateTerminationProtectionOutput, error) {
+	m.input = input
+	return &cloudformation.UpdateTerminationProtectionOutput{StackId: input.StackName}, nil
+}
+
+func TestSetTerminationProtection(t *testing.T) {
+	mock := &terminationProtectionCfnMock{}
+	h := &cfnHelper{cfnClient: mock}
+
+	if err := h.SetTerminationProtection("stack", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if aws.StringValue(mock.input.StackName) != "stack" || !aws.BoolValue(mock.input.EnableTerminationProtection) {
+		t.Errorf("Unexpected input %s", mock.input)
+	}
+}
+
+func TestGetNestedTemplateKeys(t *testing.T) {
+	body := MustReadFile(t, "./testdata/packaged_root_template.yaml")
+
+	keys := GetNestedTemplateKeys(body, "bucketname")
+
+	want := []string{
+		"my/bucket/prefix/infrastructure/security-groups.yaml",
+		"my/bucket/prefix/infrastructure/load-balancers.yaml",
+		"my/bucket/prefix/infrastructure/ecs-cluster.yaml",
+	}
+
+	if !reflect.DeepEqual(want, keys) {
+		t.Errorf("Want %v, got %v", want, keys)
+	}
+
+	if keys := GetNestedTemplateKeys(body, "otherbucket"); len(keys) != 0 {
+		t.Errorf("Want no keys for another bucket, got %v", keys)
+	}
+}
+
 func MustReadFile(t *testing.T, filename string) string {
 	data, err := ioutil.ReadFile(filename)
 
@@ -15,6 +15,10 @@ func NewPackage(bucket, prefix, region string) *Package {
 	return &Package{bucket, prefix, region}
 }
 
+func (p *Package) GetBucket() string {
+	return p.bucket
+}
+
 func (p *Package) GetBucketPrefix() string {
 	return p.prefix
 }
@@ -35,6 +39,10 @@ func (p *Package) GetParamsBucketKey() string {
 	return fmt.Sprintf("%s/params.json", p.GetBucketPrefix())
 }
 
+func (p *Package) GetManifestBucketKey() string {
+	return fmt.Sprintf("%s/manifest.json", p.GetBucketPrefix())
+}
+
 func (p *Package) GetTemplateURL() string {
 	return fmt.Sprintf("https://s3-%s.amazonaws.com/%s/%s", p.region, p.bucket, p.GetTemplateBucketKey())
 }
@@ -0,0 +1,347 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// StackDriftStatusDrifted indicates that the live resources of a stack
+	// differ from its template
+	StackDriftStatusDrifted = cloudformation.StackDriftStatusDrifted
+
+	// StackDriftStatusInSync indicates that the live resources of a stack
+	// match its template
+	StackDriftStatusInSync = cloudformation.StackDriftStatusInSync
+)
+
+var nestedTemplateURLRegexp = regexp.MustCompile(`(TemplateURL:\s*)(?:https://\S+/|\./)?([^/\s]+)`)
+
+// StackDrift describes the differences between a stack's template and its
+// live resources, and between the deployed template and the local template
+// file it was deployed from
+type StackDrift struct {
+	LogicalID       string `json:",omitempty" yaml:",omitempty"`
+	StackName       string
+	Status          string
+	TemplateFile    string           `json:",omitempty" yaml:",omitempty"`
+	TemplateDiffers bool             `json:",omitempty" yaml:",omitempty"`
+	Resources       []*ResourceDrift `json:",omitempty" yaml:",omitempty"`
+	NestedStacks    []*StackDrift    `json:",omitempty" yaml:",omitempty"`
+}
+
+// ResourceDrift is a resource that has been modified or deleted outside of
+// cloudformation
+type ResourceDrift struct {
+	LogicalID    string
+	PhysicalID   string
+	ResourceType string
+	Status       string
+	Differences  []*PropertyDifference `json:",omitempty" yaml:",omitempty"`
+}
+
+// PropertyDifference is a single property of a drifted resource whose live
+// value differs from the expected value
+type PropertyDifference struct {
+	Path     string
+	Type     string
+	Expected string
+	Actual   string
+}
+
+// HasDrifted returns true if the stack, or any of its nested stacks, has
+// drifted or no longer matches its local template
+func (d *StackDrift) HasDrifted() bool {
+	if d.Status == StackDriftStatusDrifted || d.TemplateDiffers {
+		return true
+	}
+
+	for _, nested := range d.NestedStacks {
+		if nested.HasDrifted() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *StackDrift) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	d.render(&buf)
+
+	if !d.HasDrifted() {
+		fmt.Fprintf(&buf, "\nNo drift was detected\n")
+	}
+
+	n, err := w.Write(buf.Bytes())
+
+	return int64(n), err
+}
+
+func (d *StackDrift) render(w io.Writer) {
+	name := d.StackName
+
+	if d.LogicalID != "" {
+		name = fmt.Sprintf("%s (%s)", d.LogicalID, d.StackName)
+	}
+
+	fmt.Fprintf(w, "%s: %s\n", name, d.Status)
+
+	if d.TemplateDiffers {
+		fmt.Fprintf(w, "  ! Deployed template differs from %s\n", d.TemplateFile)
+	}
+
+	for _, r := range d.Resources {
+		fmt.Fprintf(w, "  %s %s (%s) %s\n", driftSymbol(r.Status), r.LogicalID, r.ResourceType, r.PhysicalID)
+
+		for _, diff := range r.Differences {
+			fmt.Fprintf(w, "      %s %s: expected %s, actual %s\n", diff.Type, diff.Path, diff.Expected, diff.Actual)
+		}
+	}
+
+	for _, nested := range d.NestedStacks {
+		nested.render(ui.NewPrefixWriter(w, "  "))
+	}
+}
+
+func driftSymbol(status string) string {
+	switch status {
+	case cloudformation.StackResourceDriftStatusModified:
+		return "~"
+	case cloudformation.StackResourceDriftStatusDeleted:
+		return "-"
+	default:
+		return "?"
+	}
+}
+
+// DetectDrift runs cloudformation drift detection on the stack and all of
+// its nested stacks, and compares the deployed templates with the local
+// template files they were deployed from. templateFile is the local template
+// of the root stack
+func (h *cfnHelper) DetectDrift(ctx context.Context, stackName, templateFile string, w io.Writer) (*StackDrift, error) {
+	return h.detectDrift(ctx, stackName, "", templateFile, w)
+}
+
+func (h *cfnHelper) detectDrift(ctx context.Context, stackName, logicalID, templateFile string, w io.Writer) (*StackDrift, error) {
+	drift := &StackDrift{
+		LogicalID:    logicalID,
+		StackName:    stackName,
+		TemplateFile: templateFile,
+	}
+
+	fmt.Fprintf(w, "Detecting drift of stack %s...\n", stackName)
+
+	status, err := h.detectStackDrift(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	drift.Status = status
+
+	if drift.Resources, err = h.getResourceDrifts(ctx, stackName); err != nil {
+		return nil, err
+	}
+
+	local, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	deployed, err := h.cfnClient.GetTemplateWithContext(ctx, &cloudformation.GetTemplateInput{
+		StackName: aws.String(stackName),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	drift.TemplateDiffers = !templatesMatch(string(local), aws.StringValue(deployed.TemplateBody))
+
+	nestedTemplates, err := getNestedTemplateFiles(templateFile, local)
+	if err != nil {
+		return nil, err
+	}
+
+	nestedStacks, err := h.getNestedStacks(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nested := range nestedStacks {
+		file, ok := nestedTemplates[aws.StringValue(nested.LogicalResourceId)]
+		if !ok {
+			fmt.Fprintf(w, "WARNING No local template was found for nested stack %s\n", aws.StringValue(nested.LogicalResourceId))
+			continue
+		}
+
+		nestedDrift, err := h.detectDrift(ctx, aws.StringValue(nested.PhysicalResourceId), aws.StringValue(nested.LogicalResourceId), file, w)
+		if err != nil {
+			return nil, err
+		}
+
+		drift.NestedStacks = append(drift.NestedStacks, nestedDrift)
+	}
+
+	return drift, nil
+}
+
+// templatesMatch compares a local template with a deployed template,
+// ignoring the rewriting of nested template urls that happens when the
+// template is packaged
+func templatesMatch(local, deployed string) bool {
+	normalize := func(body string) string {
+		return strings.TrimSpace(nestedTemplateURLRegexp.ReplaceAllString(body, "${1}$2"))
+	}
+
+	return normalize(local) == normalize(deployed)
+}
+
+// getNestedTemplateFiles maps the logical ids of the nested stacks in a
+// template to the local template files they are created from
+func getNestedTemplateFiles(templateFile string, body []byte) (map[string]string, error) {
+	var template struct {
+		Resources map[string]struct {
+			Type       string                 `yaml:"Type"`
+			Properties map[string]interface{} `yaml:"Properties"`
+		} `yaml:"Resources"`
+	}
+
+	if err := yaml.Unmarshal(body, &template); err != nil {
+		return nil, fmt.Errorf("Failed to parse template %s. %s", templateFile, err.Error())
+	}
+
+	files := make(map[string]string)
+
+	for logicalID, resource := range template.Resources {
+		if resource.Type != "AWS::CloudFormation::Stack" {
+			continue
+		}
+
+		url, ok := resource.Properties["TemplateURL"].(string)
+		if !ok || strings.Contains(url, "://") {
+			continue
+		}
+
+		files[logicalID] = filepath.Join(filepath.Dir(templateFile), url)
+	}
+
+	return files, nil
+}
+
+func (h *cfnHelper) getNestedStacks(ctx context.Context, stackName string) ([]*cloudformation.StackResourceSummary, error) {
+	stacks := make([]*cloudformation.StackResourceSummary, 0)
+
+	params := &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	}
+
+	err := h.cfnClient.ListStackResourcesPagesWithContext(ctx, params, func(page *cloudformation.ListStackResourcesOutput, lastPage bool) bool {
+		for _, resource := range page.StackResourceSummaries {
+			if aws.StringValue(resource.ResourceType) == "AWS::CloudFormation::Stack" && aws.StringValue(resource.PhysicalResourceId) != "" {
+				stacks = append(stacks, resource)
+			}
+		}
+		return true
+	})
+
+	return stacks, err
+}
+
+// detectStackDrift starts drift detection for a single stack, and waits for
+// it to complete
+func (h *cfnHelper) detectStackDrift(ctx context.Context, stackName string) (string, error) {
+	detection, err := h.cfnClient.DetectStackDriftWithContext(ctx, &cloudformation.DetectStackDriftInput{
+		StackName: aws.String(stackName),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now().UTC()
+	timeout := time.Second * 60 * 10
+
+	for {
+		status, err := h.cfnClient.DescribeStackDriftDetectionStatusWithContext(ctx, &cloudformation.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: detection.StackDriftDetectionId,
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		switch aws.StringValue(status.DetectionStatus) {
+		case cloudformation.StackDriftDetectionStatusDetectionInProgress:
+		case cloudformation.StackDriftDetectionStatusDetectionFailed:
+			// Detection fails if some resources do not support drift
+			// detection, but the results for the rest are still available
+			if status.StackDriftStatus == nil {
+				return "", fmt.Errorf("Drift detection failed for stack %s. %s", stackName, aws.StringValue(status.DetectionStatusReason))
+			}
+			return aws.StringValue(status.StackDriftStatus), nil
+		default:
+			return aws.StringValue(status.StackDriftStatus), nil
+		}
+
+		if time.Since(start) > timeout {
+			return "", fmt.Errorf("Drift detection for stack %s did not complete within %s", stackName, timeout)
+		}
+
+		if err := sleepWithContext(ctx, time.Second*5); err != nil {
+			return "", err
+		}
+	}
+}
+
+// getResourceDrifts returns the resources of a stack that have been modified
+// or deleted since they were deployed
+func (h *cfnHelper) getResourceDrifts(ctx context.Context, stackName string) ([]*ResourceDrift, error) {
+	drifts := make([]*ResourceDrift, 0)
+
+	params := &cloudformation.DescribeStackResourceDriftsInput{
+		StackName: aws.String(stackName),
+		StackResourceDriftStatusFilters: []*string{
+			aws.String(cloudformation.StackResourceDriftStatusModified),
+			aws.String(cloudformation.StackResourceDriftStatusDeleted),
+		},
+	}
+
+	err := h.cfnClient.DescribeStackResourceDriftsPagesWithContext(ctx, params, func(page *cloudformation.DescribeStackResourceDriftsOutput, lastPage bool) bool {
+		for _, r := range page.StackResourceDrifts {
+			drift := &ResourceDrift{
+				LogicalID:    aws.StringValue(r.LogicalResourceId),
+				PhysicalID:   aws.StringValue(r.PhysicalResourceId),
+				ResourceType: aws.StringValue(r.ResourceType),
+				Status:       aws.StringValue(r.StackResourceDriftStatus),
+			}
+
+			for _, diff := range r.PropertyDifferences {
+				drift.Differences = append(drift.Differences, &PropertyDifference{
+					Path:     aws.StringValue(diff.PropertyPath),
+					Type:     aws.StringValue(diff.DifferenceType),
+					Expected: aws.StringValue(diff.ExpectedValue),
+					Actual:   aws.StringValue(diff.ActualValue),
+				})
+			}
+
+			drifts = append(drifts, drift)
+		}
+		return true
+	})
+
+	return drifts, err
+}
@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+)
+
+func TestTemplatesMatch(t *testing.T) {
+	local := "Resources:\n  Alarms:\n    Properties:\n      TemplateURL: ./alarms.yaml\n"
+	deployed := "Resources:\n  Alarms:\n    Properties:\n      TemplateURL: https://s3-ap-southeast-2.amazonaws.com/bucket/infrastructure/templates/123/alarms.yaml\n\n"
+
+	if !templatesMatch(local, deployed) {
+		t.Errorf("Want templates that differ only by nested template url to match")
+	}
+
+	if templatesMatch(local, strings.Replace(deployed, "Alarms", "Alerts", -1)) {
+		t.Errorf("Want templates with different resources not to match")
+	}
+}
+
+func TestGetNestedTemplateFiles(t *testing.T) {
+	body := `
+Resources:
+  Alarms:
+    Type: AWS::CloudFormation::Stack
+    Properties:
+      TemplateURL: ./alarms.yaml
+      Parameters:
+        Cluster: !Ref Cluster
+  Remote:
+    Type: AWS::CloudFormation::Stack
+    Properties:
+      TemplateURL: https://example.com/remote.yaml
+  Topic:
+    Type: AWS::SNS::Topic
+`
+
+	files, err := getNestedTemplateFiles("/project/templates/stack.yaml", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("Want 1 nested template, got %d", len(files))
+	}
+
+	if want := filepath.Join("/project/templates", "alarms.yaml"); files["Alarms"] != want {
+		t.Errorf("Want %s, got %s", want, files["Alarms"])
+	}
+}
+
+func TestStackDriftHasDrifted(t *testing.T) {
+	drift := &StackDrift{
+		Status: StackDriftStatusInSync,
+		NestedStacks: []*StackDrift{
+			{Status: StackDriftStatusInSync},
+		},
+	}
+
+	if drift.HasDrifted() {
+		t.Errorf("Want in sync stacks not to have drifted")
+	}
+
+	drift.NestedStacks[0].TemplateDiffers = true
+
+	if !drift.HasDrifted() {
+		t.Errorf("Want stack with a changed nested template to have drifted")
+	}
+}
+
+func TestEnvironmentTemplatesParse(t *testing.T) {
+	dir := filepath.Join("..", "resources", "environment", "cloudformation")
+
+	body, err := ioutil.ReadFile(filepath.Join(dir, "stack.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := getNestedTemplateFiles(filepath.Join(dir, "stack.yaml"), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) == 0 {
+		t.Errorf("Want nested templates in the environment stack")
+	}
+}
+
+type driftCfnMock struct {
+	cloudformationiface.CloudFormationAPI
+
+	statuses []string
+	filters  []*string
+}
+
+func (m *driftCfnMock) DetectStackDriftWithContext(ctx aws.Context, input *cloudformation.DetectStackDriftInput, opts ...request.Option) (*cloudformation.DetectStackDriftOutput, error) {
+	return &cloudformation.DetectStackDriftOutput{StackDriftDetectionId: aws.String("detection")}, nil
+}
+
+func (m *driftCfnMock) DescribeStackDriftDetectionStatusWithContext(ctx aws.Context, input *cloudformation.DescribeStackDriftDetectionStatusInput, opts ...request.Option) (*cloudformation.DescribeStackDriftDetectionStatusOutput, error) {
+	status := m.statuses[0]
+	m.statuses = m.statuses[1:]
+
+	return &cloudformation.DescribeStackDriftDetectionStatusOutput{
+		DetectionStatus:  aws.String(status),
+		StackDriftStatus: aws.String(cloudformation.StackDriftStatusDrifted),
+	}, nil
+}
+
+func (m *driftCfnMock) DescribeStackResourceDriftsPagesWithContext(ctx aws.Context, input *cloudformation.DescribeStackResourceDriftsInput, fn func(*cloudformation.DescribeStackResourceDriftsOutput, bool) bool, opts ...request.Option) error {
+	m.filters = input.StackResourceDriftStatusFilters
+
+	fn(&cloudformation.DescribeStackResourceDriftsOutput{
+		StackResourceDrifts: []*cloudformation.StackResourceDrift{
+			{
+				LogicalResourceId:        aws.String("Topic"),
+				ResourceType:             aws.String("AWS::SNS::Topic"),
+				StackResourceDriftStatus: aws.String(cloudformation.StackResourceDriftStatusModified),
+				PropertyDifferences: []*cloudformation.PropertyDifference{
+					{PropertyPath: aws.String("/DisplayName"), DifferenceType: aws.String("NOT_EQUAL"), ExpectedValue: aws.String("a"), ActualValue: aws.String("b")},
+				},
+			},
+		},
+	}, false)
+
+	fn(&cloudformation.DescribeStackResourceDriftsOutput{
+		StackResourceDrifts: []*cloudformation.StackResourceDrift{
+			{
+				LogicalResourceId:        aws.String("Queue"),
+				ResourceType:             aws.String("AWS::SQS::Queue"),
+				StackResourceDriftStatus: aws.String(cloudformation.StackResourceDriftStatusDeleted),
+			},
+		},
+	}, true)
+
+	return nil
+}
+
+func TestDetectStackDriftWaitsForDetection(t *testing.T) {
+	mock := &driftCfnMock{
+		statuses: []string{
+			cloudformation.StackDriftDetectionStatusDetectionInProgress,
+			cloudformation.StackDriftDetectionStatusDetectionComplete,
+		},
+	}
+
+	h := &cfnHelper{cfnClient: mock}
+
+	status, err := h.detectStackDrift(context.Background(), "stack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status != StackDriftStatusDrifted {
+		t.Errorf("Want status %s, got %s", StackDriftStatusDrifted, status)
+	}
+
+	if len(mock.statuses) != 0 {
+		t.Errorf("Want detection status to be polled until complete")
+	}
+}
+
+func TestGetResourceDrifts(t *testing.T) {
+	mock := &driftCfnMock{}
+	h := &cfnHelper{cfnClient: mock}
+
+	drifts, err := h.getResourceDrifts(context.Background(), "stack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mock.filters) != 2 {
+		t.Errorf("Want only modified and deleted resources to be requested, got %d filters", len(mock.filters))
+	}
+
+	if len(drifts) != 2 {
+		t.Fatalf("Want 2 drifted resources across both pages, got %d", len(drifts))
+	}
+
+	if len(drifts[0].Differences) != 1 || drifts[0].Differences[0].Actual != "b" {
+		t.Errorf("Unexpected differences %+v", drifts[0].Differences)
+	}
+
+	if drifts[1].Status != cloudformation.StackResourceDriftStatusDeleted {
+		t.Errorf("Want deleted status, got %s", drifts[1].Status)
+	}
+}
@@ -1,16 +1,23 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/bernos/ecso/pkg/ecso"
 )
 
+// maxFailedTasks is the number of tasks of a deployment that may fail to
+// start before the deployment is considered to have failed
+const maxFailedTasks = 5
+
 type ECSHelper interface {
-	LogServiceEvents(service, cluster string, logger func(*ecs.ServiceEvent, error)) (cancel func())
+	LogServiceEvents(ctx context.Context, service, cluster string, logger func(*ecs.ServiceEvent, error)) (cancel func())
+	WaitUntilServiceStable(ctx context.Context, service, cluster string, timeout time.Duration) error
 }
 
 func NewECSHelper(ecsClient ecsiface.ECSAPI) ECSHelper {
@@ -23,7 +30,7 @@ type ecsHelper struct {
 	ecsClient ecsiface.ECSAPI
 }
 
-func (h *ecsHelper) LogServiceEvents(service, cluster string, logger func(*ecs.ServiceEvent, error)) (cancel func()) {
+func (h *ecsHelper) LogServiceEvents(ctx context.Context, service, cluster string, logger func(*ecs.ServiceEvent, error)) (cancel func()) {
 	done := make(chan struct{})
 	ticker := time.NewTicker(time.Second * 5)
 
@@ -39,9 +46,12 @@ func (h *ecsHelper) LogServiceEvents(service, cluster string, logger func(*ecs.S
 		var lastEventID string
 
 		for {
-			resp, err := h.ecsClient.DescribeServices(params)
+			resp, err := h.ecsClient.DescribeServicesWithContext(ctx, params)
 
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				logger(nil, err)
 			} else {
 				if len(resp.Services) != 1 {
@@ -72,6 +82,8 @@ func (h *ecsHelper) LogServiceEvents(service, cluster string, logger func(*ecs.S
 			select {
 			case <-done:
 				return
+			case <-ctx.Done():
+				return
 			case <-ticker.C:
 			}
 		}
@@ -80,5 +92,93 @@ func (h *ecsHelper) LogServiceEvents(service, cluster string, logger func(*ecs.S
 	return func() {
 		close(done)
 	}
+}
+
+// WaitUntilServiceStable polls the ECS service until it is stable, as defined
+// by IsServiceStable. If the service does not become stable within timeout an
+// ecso.TimeoutError is returned, and if its deployment fails, as reported by
+// DeploymentFailure, an ecso.DeploymentFailedError. A timeout of zero will
+// wait until ctx is done
+func (h *ecsHelper) WaitUntilServiceStable(ctx context.Context, service, cluster string, timeout time.Duration) error {
+	params := &ecs.DescribeServicesInput{
+		Cluster: aws.String(cluster),
+		Services: []*string{
+			aws.String(service),
+		},
+	}
+
+	waitCtx := ctx
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Errors caused by reaching the deadline are reported as timeouts, while
+	// cancellation of the parent context is returned as is
+	checkTimeout := func(err error) error {
+		if ctx.Err() == nil && waitCtx.Err() == context.DeadlineExceeded {
+			return ecso.NewTimeoutError("Service %s failed to become stable within %s", service, timeout)
+		}
+
+		return err
+	}
+
+	for {
+		resp, err := h.ecsClient.DescribeServicesWithContext(waitCtx, params)
+		if err != nil {
+			return checkTimeout(err)
+		}
+
+		if len(resp.Services) != 1 {
+			return fmt.Errorf("Expected to find 1 service, but found %d", len(resp.Services))
+		}
+
+		if *resp.Services[0].Status == "INACTIVE" {
+			return fmt.Errorf("Service %s is inactive", service)
+		}
+
+		if reason := DeploymentFailure(resp.Services[0]); reason != "" {
+			return ecso.NewDeploymentFailedError("The deployment of service %s failed. %s", service, reason)
+		}
+
+		if IsServiceStable(resp.Services[0]) {
+			return nil
+		}
+
+		if err := sleepWithContext(waitCtx, time.Second*5); err != nil {
+			return checkTimeout(err)
+		}
+	}
+}
+
+// IsServiceStable returns true if the service has a single PRIMARY deployment,
+// and the number of running tasks is equal to the desired count
+func IsServiceStable(service *ecs.Service) bool {
+	if len(service.Deployments) != 1 || *service.Deployments[0].Status != "PRIMARY" {
+		return false
+	}
+
+	return *service.RunningCount == *service.DesiredCount
+}
+
+// DeploymentFailure returns the reason that the service's latest deployment
+// has failed, or an empty string if it has not. A deployment fails when the
+// deployment circuit breaker marks it as FAILED, or when maxFailedTasks of the
+// PRIMARY deployment's tasks have failed to start. The circuit breaker may
+// already have made a rollback the PRIMARY deployment, so the failed
+// deployment is looked for among all of the service's deployments
+func DeploymentFailure(service *ecs.Service) string {
+	for _, d := range service.Deployments {
+		if aws.StringValue(d.RolloutState) == ecs.DeploymentRolloutStateFailed {
+			return aws.StringValue(d.RolloutStateReason)
+		}
+
+		if failed := aws.Int64Value(d.FailedTasks); failed >= maxFailedTasks && aws.StringValue(d.Status) == "PRIMARY" {
+			return fmt.Sprintf("%d tasks failed to start", failed)
+		}
+	}
 
+	return ""
 }
@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/api/mocks"
+)
+
+func makeTestECSService(running, desired int64, deployments ...string) *ecs.Service {
+	service := &ecs.Service{
+		Status:       aws.String("ACTIVE"),
+		RunningCount: aws.Int64(running),
+		DesiredCount: aws.Int64(desired),
+	}
+
+	for _, status := range deployments {
+		service.Deployments = append(service.Deployments, &ecs.Deployment{
+			Status: aws.String(status),
+		})
+	}
+
+	return service
+}
+
+func TestIsServiceStable(t *testing.T) {
+	cases := []struct {
+		service *ecs.Service
+		want    bool
+	}{
+		{makeTestECSService(2, 2, "PRIMARY"), true},
+		{makeTestECSService(1, 2, "PRIMARY"), false},
+		{makeTestECSService(2, 2, "PRIMARY", "ACTIVE"), false},
+		{makeTestECSService(2, 2, "ACTIVE"), false},
+		{makeTestECSService(0, 0), false},
+	}
+
+	for i, c := range cases {
+		if got := IsServiceStable(c.service); got != c.want {
+			t.Errorf("Case %d: want %t, got %t", i, c.want, got)
+		}
+	}
+}
+
+func TestWaitUntilServiceStable(t *testing.T) {
+	mock := &mocks.ECSAPIMock{}
+	mock.DescribeServicesReturns(&ecs.DescribeServicesOutput{
+		Services: []*ecs.Service{makeTestECSService(1, 1, "PRIMARY")},
+	}, nil)
+
+	helper := NewECSHelper(mock)
+
+	if err := helper.WaitUntilServiceStable(context.Background(), "service", "cluster", time.Minute); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWaitUntilServiceStableTimeout(t *testing.T) {
+	mock := &mocks.ECSAPIMock{}
+	mock.DescribeServicesReturns(&ecs.DescribeServicesOutput{
+		Services: []*ecs.Service{makeTestECSService(0, 1, "PRIMARY", "ACTIVE")},
+	}, nil)
+
+	helper := NewECSHelper(mock)
+
+	err := helper.WaitUntilServiceStable(context.Background(), "service", "cluster", time.Nanosecond)
+
+	if !ecso.IsTimeoutError(err) {
+		t.Errorf("Want TimeoutError, got %v", err)
+	}
+}
+
+func TestWaitUntilServiceStableFailedDeployment(t *testing.T) {
+	rolledBack := makeTestECSService(1, 1, "PRIMARY", "ACTIVE")
+	rolledBack.Deployments[1].RolloutState = aws.String(ecs.DeploymentRolloutStateFailed)
+	rolledBack.Deployments[1].RolloutStateReason = aws.String("ECS deployment circuit breaker: tasks failed to start.")
+
+	crashing := makeTestECSService(0, 2, "PRIMARY")
+	crashing.Deployments[0].FailedTasks = aws.Int64(maxFailedTasks)
+
+	for i, service := range []*ecs.Service{rolledBack, crashing} {
+		mock := &mocks.ECSAPIMock{}
+		mock.DescribeServicesReturns(&ecs.DescribeServicesOutput{
+			Services: []*ecs.Service{service},
+		}, nil)
+
+		err := NewECSHelper(mock).WaitUntilServiceStable(context.Background(), "service", "cluster", time.Minute)
+
+		if !ecso.IsDeploymentFailedError(err) {
+			t.Errorf("Case %d: want DeploymentFailedError, got %v", i, err)
+		}
+	}
+}
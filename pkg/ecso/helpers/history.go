@@ -0,0 +1,228 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+const (
+	// HistoryOutcomeSucceeded is the outcome of an operation that completed
+	HistoryOutcomeSucceeded = "succeeded"
+
+	// HistoryOutcomeFailed is the outcome of an operation that returned an
+	// error
+	HistoryOutcomeFailed = "failed"
+
+	// historyKeyTimeFormat sorts lexically in time order, so that records
+	// older than a filter's Since time can be skipped without being read
+	historyKeyTimeFormat = "20060102T150405.000000000Z"
+)
+
+// HistoryRecord records a single change made to an environment or service
+// by ecso
+type HistoryRecord struct {
+	// Timestamp is the time that the operation started
+	Timestamp   time.Time
+	Project     string
+	Environment string
+	Service     string `json:",omitempty" yaml:",omitempty"`
+
+	// Action is the ecso command that made the change, such as
+	// "service up" or "environment down"
+	Action  string
+	Version string `json:",omitempty" yaml:",omitempty"`
+
+	// Actor is the ARN of the IAM principal that made the change
+	Actor string
+	Git   *GitInfo `json:",omitempty" yaml:",omitempty"`
+
+	// Duration is the time the operation took, in seconds
+	Duration float64
+	Outcome  string
+	Error    string `json:",omitempty" yaml:",omitempty"`
+}
+
+// HistoryFilter selects records from a HistoryStore
+type HistoryFilter struct {
+	// Service limits records to those for a single service. If empty,
+	// records for the environment and all services are selected
+	Service string
+
+	// Since excludes records that started before the given time
+	Since time.Time
+}
+
+// Matches returns true if the record is selected by the filter
+func (f *HistoryFilter) Matches(r *HistoryRecord) bool {
+	if f.Service != "" && r.Service != f.Service {
+		return false
+	}
+
+	return !r.Timestamp.Before(f.Since)
+}
+
+// HistoryStore stores the history of a single environment
+type HistoryStore interface {
+	// Append adds a record to the store
+	Append(ctx context.Context, r *HistoryRecord) error
+
+	// List returns the records selected by the filter, oldest first
+	List(ctx context.Context, filter *HistoryFilter) ([]*HistoryRecord, error)
+}
+
+// historyRecordName returns the name that a record is stored under. Names
+// begin with the record's timestamp, so they sort in time order
+func historyRecordName(r *HistoryRecord) string {
+	subject := r.Service
+
+	if subject == "" {
+		subject = "environment"
+	}
+
+	return fmt.Sprintf("%s-%s-%s.json", r.Timestamp.UTC().Format(historyKeyTimeFormat), subject, strings.Replace(r.Action, " ", "-", -1))
+}
+
+// isBefore returns true if the record stored under name started before t
+func isBefore(name string, t time.Time) bool {
+	return !t.IsZero() && name < t.UTC().Format(historyKeyTimeFormat)
+}
+
+// NewS3HistoryStore creates a HistoryStore that keeps each record as a json
+// object under prefix in an S3 bucket
+func NewS3HistoryStore(s3API s3iface.S3API, region, bucket, prefix string) HistoryStore {
+	return &s3HistoryStore{
+		s3API:  s3API,
+		s3:     NewS3Helper(s3API, region),
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+type s3HistoryStore struct {
+	s3API  s3iface.S3API
+	s3     S3Helper
+	bucket string
+	prefix string
+}
+
+func (s *s3HistoryStore) Append(ctx context.Context, r *HistoryRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.s3API.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(path.Join(s.prefix, historyRecordName(r))),
+		Body:        bytes.NewReader(b),
+		ContentType: aws.String("application/json"),
+	})
+
+	return err
+}
+
+func (s *s3HistoryStore) List(ctx context.Context, filter *HistoryFilter) ([]*HistoryRecord, error) {
+	objects, err := s.s3.ListObjects(ctx, s.bucket, s.prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+
+	for _, o := range objects {
+		if key := aws.StringValue(o.Key); !isBefore(path.Base(key), filter.Since) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	records := make([]*HistoryRecord, 0)
+
+	for _, key := range keys {
+		r := &HistoryRecord{}
+
+		if err := s.s3.DownloadObjectJSON(ctx, r, s.bucket, key); err != nil {
+			return nil, err
+		}
+
+		if filter.Matches(r) {
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
+
+// NewFileHistoryStore creates a HistoryStore that keeps each record as a
+// json file in a local dir
+func NewFileHistoryStore(dir string) HistoryStore {
+	return &fileHistoryStore{dir: dir}
+}
+
+type fileHistoryStore struct {
+	dir string
+}
+
+func (s *fileHistoryStore) Append(ctx context.Context, r *HistoryRecord) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(s.dir, historyRecordName(r)), b, 0644)
+}
+
+func (s *fileHistoryStore) List(ctx context.Context, filter *HistoryFilter) ([]*HistoryRecord, error) {
+	records := make([]*HistoryRecord, 0)
+
+	files, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// ReadDir sorts by name, which is also time order
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" || isBefore(f.Name(), filter.Since) {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		r := &HistoryRecord{}
+
+		if err := json.Unmarshal(b, r); err != nil {
+			return nil, fmt.Errorf("Failed to read history record %s. %s", f.Name(), err.Error())
+		}
+
+		if filter.Matches(r) {
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+var testHistory = []*HistoryRecord{
+	{
+		Timestamp:   time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC),
+		Project:     "myproject",
+		Environment: "dev",
+		Action:      "environment up",
+		Version:     "20170301100000",
+		Outcome:     HistoryOutcomeSucceeded,
+	},
+	{
+		Timestamp:   time.Date(2017, 3, 2, 10, 0, 0, 0, time.UTC),
+		Project:     "myproject",
+		Environment: "dev",
+		Service:     "web",
+		Action:      "service up",
+		Version:     "1.0.0",
+		Git:         &GitInfo{Commit: "abcdef1234567", Branch: "master"},
+		Outcome:     HistoryOutcomeFailed,
+		Error:       "Stack update failed",
+	},
+	{
+		Timestamp:   time.Date(2017, 3, 3, 10, 0, 0, 0, time.UTC),
+		Project:     "myproject",
+		Environment: "dev",
+		Service:     "worker",
+		Action:      "service down",
+		Outcome:     HistoryOutcomeSucceeded,
+	},
+}
+
+func testHistoryStore(t *testing.T, store HistoryStore) {
+	// Append out of order, to check that records are listed in time order
+	for _, i := range []int{2, 0, 1} {
+		if err := store.Append(context.Background(), testHistory[i]); err != nil {
+			t.Fatalf("Unexpected error appending record: %s", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   *HistoryFilter
+		expected []*HistoryRecord
+	}{
+		{
+			name:     "all",
+			filter:   &HistoryFilter{},
+			expected: testHistory,
+		},
+		{
+			name:     "service",
+			filter:   &HistoryFilter{Service: "web"},
+			expected: testHistory[1:2],
+		},
+		{
+			name:     "since",
+			filter:   &HistoryFilter{Since: time.Date(2017, 3, 2, 10, 0, 0, 0, time.UTC)},
+			expected: testHistory[1:],
+		},
+	}
+
+	for _, test := range tests {
+		records, err := store.List(context.Background(), test.filter)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+
+		if len(records) != len(test.expected) {
+			t.Fatalf("%s: want %d records, got %d", test.name, len(test.expected), len(records))
+		}
+
+		for i, r := range records {
+			if r.Action != test.expected[i].Action || !r.Timestamp.Equal(test.expected[i].Timestamp) || r.Error != test.expected[i].Error {
+				t.Errorf("%s: want record %#v, got %#v", test.name, test.expected[i], r)
+			}
+		}
+	}
+}
+
+func TestFileHistoryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testHistoryStore(t, NewFileHistoryStore(dir))
+}
+
+func TestFileHistoryStoreMissingDir(t *testing.T) {
+	records, err := NewFileHistoryStore("/does/not/exist").List(context.Background(), &HistoryFilter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(records) != 0 {
+		t.Errorf("Want no records, got %d", len(records))
+	}
+}
+
+type historyS3Mock struct {
+	s3iface.S3API
+	objects map[string][]byte
+}
+
+func (m *historyS3Mock) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	b, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.objects[*input.Key] = b
+
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *historyS3Mock) ListObjectsPagesWithContext(ctx aws.Context, input *s3.ListObjectsInput, fn func(*s3.ListObjectsOutput, bool) bool, opts ...request.Option) error {
+	page := &s3.ListObjectsOutput{}
+	keys := make([]string, 0)
+
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+
+	// S3 lists keys in order, unlike ranging over a map
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key)})
+	}
+
+	fn(page, true)
+
+	return nil
+}
+
+func (m *historyS3Mock) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(m.objects[*input.Key])),
+	}, nil
+}
+
+func TestS3HistoryStore(t *testing.T) {
+	mock := &historyS3Mock{objects: make(map[string][]byte)}
+
+	testHistoryStore(t, NewS3HistoryStore(mock, "ap-southeast-2", "ecso-bucket", "myproject-dev/history"))
+
+	for key := range mock.objects {
+		if !strings.HasPrefix(key, "myproject-dev/history/") {
+			t.Errorf("Record stored outside the history prefix: %s", key)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+// RegistryAuth holds the credentials used to push images to a registry
+type RegistryAuth struct {
+	Registry string
+	Username string
+	Password string
+}
+
+// ImageBuilder builds container images and pushes them to a registry
+type ImageBuilder interface {
+	// Build builds an image from build, and tags it as image
+	Build(ctx context.Context, build *ecso.ContainerBuild, image string, w io.Writer) error
+
+	// Login authenticates with a registry, so that images can be pushed
+	// to it
+	Login(ctx context.Context, auth *RegistryAuth, w io.Writer) error
+
+	// Push pushes a previously built image to its registry
+	Push(ctx context.Context, image string, w io.Writer) error
+}
+
+// NewDockerImageBuilder creates an ImageBuilder that uses the docker cli
+func NewDockerImageBuilder() ImageBuilder {
+	return &dockerImageBuilder{}
+}
+
+type dockerImageBuilder struct{}
+
+func (b *dockerImageBuilder) Build(ctx context.Context, build *ecso.ContainerBuild, image string, w io.Writer) error {
+	args := []string{"build", "-t", image}
+
+	if build.Dockerfile != "" {
+		args = append(args, "-f", dockerfilePath(build))
+	}
+
+	for _, k := range sortedKeys(build.Args) {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, build.Args[k]))
+	}
+
+	args = append(args, build.Context)
+
+	return b.docker(ctx, nil, w, args...)
+}
+
+func (b *dockerImageBuilder) Login(ctx context.Context, auth *RegistryAuth, w io.Writer) error {
+	return b.docker(ctx, strings.NewReader(auth.Password), w, "login", "--username", auth.Username, "--password-stdin", auth.Registry)
+}
+
+func (b *dockerImageBuilder) Push(ctx context.Context, image string, w io.Writer) error {
+	return b.docker(ctx, nil, w, "push", image)
+}
+
+func (b *dockerImageBuilder) docker(ctx context.Context, stdin io.Reader, w io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker %s failed. %s", args[0], err.Error())
+	}
+
+	return nil
+}
+
+// ContentTag returns an image tag derived from the contents of a build's
+// context dir, dockerfile and build args. Building the same source always
+// produces the same tag, so unchanged images need not be rebuilt or pushed
+func ContentTag(build *ecso.ContainerBuild) (string, error) {
+	h := sha256.New()
+
+	err := filepath.Walk(build.Context, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(build.Context, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+
+		return hashFile(h, path)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if build.Dockerfile != "" {
+		fmt.Fprintf(h, "dockerfile\x00%s\x00", build.Dockerfile)
+
+		if err := hashFile(h, dockerfilePath(build)); err != nil {
+			return "", err
+		}
+	}
+
+	for _, k := range sortedKeys(build.Args) {
+		fmt.Fprintf(h, "arg\x00%s=%s\x00", k, build.Args[k])
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:12], nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
+
+// dockerfilePath returns the path to a build's dockerfile. Compose files give
+// dockerfile paths relative to the build context, whereas the docker cli
+// expects them relative to the working dir
+func dockerfilePath(build *ecso.ContainerBuild) string {
+	if filepath.IsAbs(build.Dockerfile) {
+		return build.Dockerfile
+	}
+
+	return filepath.Join(build.Context, build.Dockerfile)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+// ImagePublisher builds container images and pushes them to an ImageRegistry
+type ImagePublisher interface {
+	// Publish ensures that an image built from build has been pushed to the
+	// named repository, and returns its reference. If skipBuild is true,
+	// no image is built and the image most recently pushed to the
+	// repository is returned instead
+	Publish(ctx context.Context, repository string, build *ecso.ContainerBuild, skipBuild bool, w io.Writer) (string, error)
+}
+
+// NewImagePublisher creates an ImagePublisher
+func NewImagePublisher(builder ImageBuilder, registry ImageRegistry) ImagePublisher {
+	return &imagePublisher{
+		builder:  builder,
+		registry: registry,
+	}
+}
+
+type imagePublisher struct {
+	builder  ImageBuilder
+	registry ImageRegistry
+	loggedIn bool
+}
+
+func (p *imagePublisher) Publish(ctx context.Context, repository string, build *ecso.ContainerBuild, skipBuild bool, w io.Writer) (string, error) {
+	uri, err := p.registry.EnsureRepository(repository, w)
+	if err != nil {
+		return "", err
+	}
+
+	if skipBuild {
+		tag, err := p.registry.LatestImageTag(repository)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(w, "Skipping build, using last pushed image %s:%s\n", uri, tag)
+
+		return fmt.Sprintf("%s:%s", uri, tag), nil
+	}
+
+	tag, err := ContentTag(build)
+	if err != nil {
+		return "", err
+	}
+
+	image := fmt.Sprintf("%s:%s", uri, tag)
+
+	exists, err := p.registry.ImageExists(repository, tag)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		fmt.Fprintf(w, "Image %s is up to date\n", image)
+		return image, nil
+	}
+
+	fmt.Fprintf(w, "Building image %s\n", image)
+
+	if err := p.builder.Build(ctx, build, image, w); err != nil {
+		return "", err
+	}
+
+	// ECR authorization tokens are valid for 12 hours, so only log in
+	// once per publisher
+	if !p.loggedIn {
+		auth, err := p.registry.Authorization()
+		if err != nil {
+			return "", err
+		}
+
+		if err := p.builder.Login(ctx, auth, w); err != nil {
+			return "", err
+		}
+
+		p.loggedIn = true
+	}
+
+	fmt.Fprintf(w, "Pushing image %s\n", image)
+
+	if err := p.builder.Push(ctx, image, w); err != nil {
+		return "", err
+	}
+
+	return image, nil
+}
@@ -0,0 +1,168 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+type fakeImageBuilder struct {
+	built  []string
+	pushed []string
+	logins int
+}
+
+func (b *fakeImageBuilder) Build(ctx context.Context, build *ecso.ContainerBuild, image string, w io.Writer) error {
+	b.built = append(b.built, image)
+	return nil
+}
+
+func (b *fakeImageBuilder) Login(ctx context.Context, auth *RegistryAuth, w io.Writer) error {
+	b.logins++
+	return nil
+}
+
+func (b *fakeImageBuilder) Push(ctx context.Context, image string, w io.Writer) error {
+	b.pushed = append(b.pushed, image)
+	return nil
+}
+
+type fakeImageRegistry struct {
+	images map[string]bool
+	latest string
+}
+
+func (r *fakeImageRegistry) EnsureRepository(name string, w io.Writer) (string, error) {
+	return "registry.example.com/" + name, nil
+}
+
+func (r *fakeImageRegistry) ImageExists(name, tag string) (bool, error) {
+	return r.images[name+":"+tag], nil
+}
+
+func (r *fakeImageRegistry) LatestImageTag(name string) (string, error) {
+	if r.latest == "" {
+		return "", fmt.Errorf("No images")
+	}
+	return r.latest, nil
+}
+
+func (r *fakeImageRegistry) Authorization() (*RegistryAuth, error) {
+	return &RegistryAuth{}, nil
+}
+
+func makeTestBuild(t *testing.T) (*ecso.ContainerBuild, func()) {
+	dir, err := ioutil.TempDir("", "ecso-build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM busybox"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &ecso.ContainerBuild{Context: dir}, func() { os.RemoveAll(dir) }
+}
+
+func TestContentTag(t *testing.T) {
+	build, cleanup := makeTestBuild(t)
+	defer cleanup()
+
+	first, err := ContentTag(build)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, _ := ContentTag(build)
+	if first != again {
+		t.Errorf("Want the same tag for unchanged source, got %s and %s", first, again)
+	}
+
+	build.Args = map[string]string{"VERSION": "2"}
+
+	withArgs, _ := ContentTag(build)
+	if withArgs == first {
+		t.Error("Want a different tag when build args change")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(build.Context, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, _ := ContentTag(build)
+	if changed == withArgs {
+		t.Error("Want a different tag when source changes")
+	}
+}
+
+func TestImagePublisherPublish(t *testing.T) {
+	build, cleanup := makeTestBuild(t)
+	defer cleanup()
+
+	var (
+		builder   = &fakeImageBuilder{}
+		registry  = &fakeImageRegistry{images: make(map[string]bool)}
+		publisher = NewImagePublisher(builder, registry)
+	)
+
+	tag, _ := ContentTag(build)
+	want := "registry.example.com/project/service/app:" + tag
+
+	for _, repo := range []string{"project/service/app", "project/service/worker"} {
+		if _, err := publisher.Publish(context.Background(), repo, build, false, ioutil.Discard); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(builder.built) != 2 || len(builder.pushed) != 2 || builder.built[0] != want {
+		t.Errorf("Want 2 images built and pushed, got %v %v", builder.built, builder.pushed)
+	}
+
+	if builder.logins != 1 {
+		t.Errorf("Want 1 login, got %d", builder.logins)
+	}
+
+	registry.images["project/service/app:"+tag] = true
+
+	image, err := publisher.Publish(context.Background(), "project/service/app", build, false, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if image != want || len(builder.built) != 2 {
+		t.Errorf("Want existing image %s to be reused, got %s", want, image)
+	}
+}
+
+func TestImagePublisherSkipBuild(t *testing.T) {
+	var (
+		builder   = &fakeImageBuilder{}
+		registry  = &fakeImageRegistry{latest: "abc123"}
+		publisher = NewImagePublisher(builder, registry)
+	)
+
+	image, err := publisher.Publish(context.Background(), "project/service/app", nil, true, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if image != "registry.example.com/project/service/app:abc123" {
+		t.Errorf("Want last pushed image, got %s", image)
+	}
+
+	if len(builder.built) != 0 {
+		t.Error("Want no images to be built")
+	}
+
+	registry.latest = ""
+
+	if _, err := publisher.Publish(context.Background(), "project/service/app", nil, true, ioutil.Discard); err == nil {
+		t.Error("Want error when no image has been pushed")
+	}
+}
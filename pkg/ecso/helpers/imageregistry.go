@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+)
+
+// DefaultECRLifecyclePolicy is applied to ECR repositories created by ecso.
+// It expires all but the 100 most recently pushed images
+const DefaultECRLifecyclePolicy = `{
+  "rules": [
+    {
+      "rulePriority": 1,
+      "description": "Keep the 100 most recent images",
+      "selection": {
+        "tagStatus": "any",
+        "countType": "imageCountMoreThan",
+        "countNumber": 100
+      },
+      "action": {
+        "type": "expire"
+      }
+    }
+  ]
+}`
+
+// ImageRegistry manages the repositories that built images are pushed to
+type ImageRegistry interface {
+	// EnsureRepository creates the named repository if it does not already
+	// exist, and returns its uri
+	EnsureRepository(name string, w io.Writer) (string, error)
+
+	// ImageExists returns true if an image with the tag has been pushed to
+	// the named repository
+	ImageExists(name, tag string) (bool, error)
+
+	// LatestImageTag returns a tag of the image most recently pushed to
+	// the named repository
+	LatestImageTag(name string) (string, error)
+
+	// Authorization returns credentials for pushing images to the registry
+	Authorization() (*RegistryAuth, error)
+}
+
+// NewECRRegistry creates an ImageRegistry backed by ECR
+func NewECRRegistry(ecrAPI ecriface.ECRAPI) ImageRegistry {
+	return &ecrRegistry{
+		ecrAPI: ecrAPI,
+	}
+}
+
+type ecrRegistry struct {
+	ecrAPI ecriface.ECRAPI
+}
+
+func (r *ecrRegistry) EnsureRepository(name string, w io.Writer) (string, error) {
+	resp, err := r.ecrAPI.DescribeRepositories(&ecr.DescribeRepositoriesInput{
+		RepositoryNames: aws.StringSlice([]string{name}),
+	})
+
+	if err == nil && len(resp.Repositories) > 0 {
+		return aws.StringValue(resp.Repositories[0].RepositoryUri), nil
+	}
+
+	if err != nil && !isAWSErrorCode(err, ecr.ErrCodeRepositoryNotFoundException) {
+		return "", err
+	}
+
+	fmt.Fprintf(w, "Creating ECR repository %s\n", name)
+
+	created, err := r.ecrAPI.CreateRepository(&ecr.CreateRepositoryInput{
+		RepositoryName: aws.String(name),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := r.ecrAPI.PutLifecyclePolicy(&ecr.PutLifecyclePolicyInput{
+		RepositoryName:      aws.String(name),
+		LifecyclePolicyText: aws.String(DefaultECRLifecyclePolicy),
+	}); err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(created.Repository.RepositoryUri), nil
+}
+
+func (r *ecrRegistry) ImageExists(name, tag string) (bool, error) {
+	resp, err := r.ecrAPI.DescribeImages(&ecr.DescribeImagesInput{
+		RepositoryName: aws.String(name),
+		ImageIds: []*ecr.ImageIdentifier{
+			{ImageTag: aws.String(tag)},
+		},
+	})
+
+	if err != nil {
+		if isAWSErrorCode(err, ecr.ErrCodeImageNotFoundException) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return len(resp.ImageDetails) > 0, nil
+}
+
+func (r *ecrRegistry) LatestImageTag(name string) (string, error) {
+	var (
+		latest    *ecr.ImageDetail
+		nextToken *string
+	)
+
+	for {
+		resp, err := r.ecrAPI.DescribeImages(&ecr.DescribeImagesInput{
+			RepositoryName: aws.String(name),
+			Filter:         &ecr.DescribeImagesFilter{TagStatus: aws.String("TAGGED")},
+			NextToken:      nextToken,
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		for _, image := range resp.ImageDetails {
+			if len(image.ImageTags) == 0 || image.ImagePushedAt == nil {
+				continue
+			}
+
+			if latest == nil || image.ImagePushedAt.After(*latest.ImagePushedAt) {
+				latest = image
+			}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+
+		nextToken = resp.NextToken
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("No images have been pushed to the ECR repository %s", name)
+	}
+
+	return *latest.ImageTags[0], nil
+}
+
+func (r *ecrRegistry) Authorization() (*RegistryAuth, error) {
+	resp, err := r.ecrAPI.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.AuthorizationData) == 0 {
+		return nil, fmt.Errorf("No ECR authorization data was returned")
+	}
+
+	data := resp.AuthorizationData[0]
+
+	token, err := base64.StdEncoding.DecodeString(aws.StringValue(data.AuthorizationToken))
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := strings.SplitN(string(token), ":", 2)
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("Invalid ECR authorization token")
+	}
+
+	return &RegistryAuth{
+		Registry: strings.TrimPrefix(aws.StringValue(data.ProxyEndpoint), "https://"),
+		Username: tokens[0],
+		Password: tokens[1],
+	}, nil
+}
+
+func isAWSErrorCode(err error, code string) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == code
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/bernos/ecso/pkg/ecso/api/mocks"
+)
+
+func TestECRRegistryEnsureRepository(t *testing.T) {
+	var (
+		ecrAPI   = &mocks.ECRAPIMock{}
+		registry = NewECRRegistry(ecrAPI)
+		uri      = "123456789012.dkr.ecr.ap-southeast-2.amazonaws.com/project/service/app"
+	)
+
+	ecrAPI.DescribeRepositoriesReturns(nil, awserr.New(ecr.ErrCodeRepositoryNotFoundException, "not found", nil))
+	ecrAPI.CreateRepositoryReturns(&ecr.CreateRepositoryOutput{
+		Repository: &ecr.Repository{RepositoryUri: aws.String(uri)},
+	}, nil)
+	ecrAPI.PutLifecyclePolicyReturns(&ecr.PutLifecyclePolicyOutput{}, nil)
+
+	got, err := registry.EnsureRepository("project/service/app", ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != uri {
+		t.Errorf("Want %s, got %s", uri, got)
+	}
+}
+
+func TestECRRegistryLatestImageTag(t *testing.T) {
+	var (
+		ecrAPI   = &mocks.ECRAPIMock{}
+		registry = NewECRRegistry(ecrAPI)
+		now      = time.Now()
+	)
+
+	ecrAPI.DescribeImagesReturns(&ecr.DescribeImagesOutput{
+		ImageDetails: []*ecr.ImageDetail{
+			{ImageTags: aws.StringSlice([]string{"old"}), ImagePushedAt: aws.Time(now.Add(-time.Hour))},
+			{ImageTags: aws.StringSlice([]string{"new"}), ImagePushedAt: aws.Time(now)},
+		},
+	}, nil)
+
+	tag, err := registry.LatestImageTag("project/service/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tag != "new" {
+		t.Errorf("Want new, got %s", tag)
+	}
+}
+
+func TestECRRegistryAuthorization(t *testing.T) {
+	var (
+		ecrAPI   = &mocks.ECRAPIMock{}
+		registry = NewECRRegistry(ecrAPI)
+	)
+
+	ecrAPI.GetAuthorizationTokenReturns(&ecr.GetAuthorizationTokenOutput{
+		AuthorizationData: []*ecr.AuthorizationData{
+			{
+				AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:secret"))),
+				ProxyEndpoint:      aws.String("https://123456789012.dkr.ecr.ap-southeast-2.amazonaws.com"),
+			},
+		},
+	}, nil)
+
+	auth, err := registry.Authorization()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if auth.Username != "AWS" || auth.Password != "secret" || auth.Registry != "123456789012.dkr.ecr.ap-southeast-2.amazonaws.com" {
+		t.Errorf("Unexpected authorization %+v", auth)
+	}
+}
@@ -0,0 +1,477 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubAuthKey  = "https://index.docker.io/v1/"
+)
+
+var (
+	ecrRegistryPattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+	authChallengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+	manifestMediaTypes = []string{
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.oci.image.manifest.v1+json",
+	}
+)
+
+// ImageReference is a parsed docker image reference
+type ImageReference struct {
+	// Name is the image name as written, without any tag or digest
+	Name string
+
+	// Registry is the host name of the registry holding the image
+	Registry string
+
+	// Repository is the name of the repository in the registry
+	Repository string
+
+	Tag    string
+	Digest string
+}
+
+// String returns the reference, pinned to the digest if it is known
+func (r *ImageReference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s@%s", r.Name, r.Digest)
+	}
+
+	return fmt.Sprintf("%s:%s", r.Name, r.Tag)
+}
+
+// ParseImageReference parses a docker image reference, applying the same
+// defaults as the docker cli. Images without a registry are assumed to be on
+// Docker Hub, and images without a tag or digest are assumed to be tagged
+// latest
+func ParseImageReference(image string) (*ImageReference, error) {
+	ref := &ImageReference{}
+	name := image
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("Invalid image reference '%s'", image)
+	}
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	ref.Name = name
+
+	tokens := strings.SplitN(name, "/", 2)
+
+	if len(tokens) == 2 && (strings.ContainsAny(tokens[0], ".:") || tokens[0] == "localhost") {
+		ref.Registry = tokens[0]
+		ref.Repository = tokens[1]
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = name
+
+		if len(tokens) == 1 {
+			ref.Repository = "library/" + name
+		}
+	}
+
+	return ref, nil
+}
+
+// ImageResolver resolves container image references to immutable,
+// digest pinned references
+type ImageResolver interface {
+	// Resolve returns image pinned to the digest that its tag currently
+	// refers to. An error is returned if the image or tag does not exist
+	Resolve(ctx context.Context, image string) (string, error)
+}
+
+// NewImageResolver creates an ImageResolver that resolves images hosted in
+// ECR using the ECR API, and images in any other registry using the Docker
+// Registry HTTP API v2. ecrRegion is the region of the ecrAPI client
+func NewImageResolver(ecrAPI ecriface.ECRAPI, ecrRegion string, client *http.Client) ImageResolver {
+	return &imageResolver{
+		ecr:      NewECRImageResolver(ecrAPI, ecrRegion),
+		registry: NewRegistryImageResolver(client),
+	}
+}
+
+type imageResolver struct {
+	ecr      ImageResolver
+	registry ImageResolver
+}
+
+func (r *imageResolver) Resolve(ctx context.Context, image string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	if ecrRegistryPattern.MatchString(ref.Registry) {
+		return r.ecr.Resolve(ctx, image)
+	}
+
+	return r.registry.Resolve(ctx, image)
+}
+
+// NewECRImageResolver creates an ImageResolver for images hosted in ECR, in
+// the same region as the ecrAPI client
+func NewECRImageResolver(ecrAPI ecriface.ECRAPI, region string) ImageResolver {
+	return &ecrImageResolver{
+		ecrAPI: ecrAPI,
+		region: region,
+	}
+}
+
+type ecrImageResolver struct {
+	ecrAPI ecriface.ECRAPI
+	region string
+}
+
+func (r *ecrImageResolver) Resolve(ctx context.Context, image string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Digest != "" {
+		return ref.String(), nil
+	}
+
+	matches := ecrRegistryPattern.FindStringSubmatch(ref.Registry)
+	if matches == nil {
+		return "", fmt.Errorf("'%s' is not an ECR image", image)
+	}
+
+	if matches[2] != r.region {
+		return "", fmt.Errorf("Cannot resolve the ECR image '%s' from region %s. ECR images must be in the same region as the environment", image, r.region)
+	}
+
+	resp, err := r.ecrAPI.BatchGetImageWithContext(ctx, &ecr.BatchGetImageInput{
+		RegistryId:         aws.String(matches[1]),
+		RepositoryName:     aws.String(ref.Repository),
+		AcceptedMediaTypes: aws.StringSlice(manifestMediaTypes),
+		ImageIds: []*ecr.ImageIdentifier{
+			{ImageTag: aws.String(ref.Tag)},
+		},
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range resp.Failures {
+		return "", fmt.Errorf("Image '%s' was not found. %s", image, aws.StringValue(f.FailureReason))
+	}
+
+	if len(resp.Images) == 0 || resp.Images[0].ImageId == nil || resp.Images[0].ImageId.ImageDigest == nil {
+		return "", fmt.Errorf("Image '%s' was not found", image)
+	}
+
+	ref.Digest = *resp.Images[0].ImageId.ImageDigest
+
+	return ref.String(), nil
+}
+
+// NewRegistryImageResolver creates an ImageResolver that queries registries
+// using the Docker Registry HTTP API v2. Credentials for private registries
+// are read from the docker cli config file. Registries are queried over
+// https, except that registries on localhost fall back to plain http, as
+// they do by default in the docker daemon
+func NewRegistryImageResolver(client *http.Client) ImageResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &registryImageResolver{
+		client: client,
+	}
+}
+
+type registryImageResolver struct {
+	client *http.Client
+}
+
+func (r *registryImageResolver) Resolve(ctx context.Context, image string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Digest != "" {
+		return ref.String(), nil
+	}
+
+	manifestURL := registryManifestURL("https", ref)
+
+	resp, err := r.do(ctx, "HEAD", manifestURL, ref, "")
+	if err != nil && isLocalRegistry(ref.Registry) {
+		manifestURL = registryManifestURL("http", ref)
+		resp, err = r.do(ctx, "HEAD", manifestURL, ref, "")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := r.getToken(ctx, resp.Header.Get("WWW-Authenticate"), ref)
+		if err != nil {
+			return "", err
+		}
+
+		if resp, err = r.do(ctx, "HEAD", manifestURL, ref, token); err != nil {
+			return "", err
+		}
+
+		resp.Body.Close()
+	}
+
+	if resp.StatusCode == http.StatusOK && resp.Header.Get("Docker-Content-Digest") == "" {
+		// Some registries only return the digest header for GET requests,
+		// in which case the digest is calculated from the manifest itself
+		return r.resolveFromManifest(ctx, manifestURL, ref, resp.Request.Header.Get("Authorization"))
+	}
+
+	if err := checkRegistryResponse(resp, image); err != nil {
+		return "", err
+	}
+
+	ref.Digest = resp.Header.Get("Docker-Content-Digest")
+
+	return ref.String(), nil
+}
+
+func registryManifestURL(scheme string, ref *ImageReference) string {
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, ref.Registry, ref.Repository, ref.Tag)
+}
+
+// isLocalRegistry returns true if the registry host is localhost or a
+// loopback address
+func isLocalRegistry(registry string) bool {
+	host, _, err := net.SplitHostPort(registry)
+	if err != nil {
+		host = registry
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+func (r *registryImageResolver) resolveFromManifest(ctx context.Context, manifestURL string, ref *ImageReference, authorization string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", manifestURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if err := checkRegistryResponse(resp, ref.String()); err != nil {
+		return "", err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		ref.Digest = digest
+	} else {
+		ref.Digest = fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	}
+
+	return ref.String(), nil
+}
+
+func (r *registryImageResolver) do(ctx context.Context, method, url string, ref *ImageReference, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return r.client.Do(req)
+}
+
+// getToken fetches a bearer token from the auth server described by a
+// WWW-Authenticate challenge
+func (r *registryImageResolver) getToken(ctx context.Context, challenge string, ref *ImageReference) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("Registry %s requires an unsupported authentication scheme '%s'", ref.Registry, challenge)
+	}
+
+	params := parseAuthChallenge(strings.TrimPrefix(challenge, "Bearer "))
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("Registry %s returned an invalid authentication challenge '%s'", ref.Registry, challenge)
+	}
+
+	query := realm.Query()
+
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	} else {
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	}
+
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if auth := dockerConfigAuth(ref.Registry); auth != "" {
+		req.Header.Set("Authorization", "Basic "+auth)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to authenticate with registry %s. %s", ref.Registry, resp.Status)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+func checkRegistryResponse(resp *http.Response, image string) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("Image '%s' was not found", image)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("Access to image '%s' was denied. Run `docker login` for its registry and try again", image)
+	default:
+		return fmt.Errorf("Failed to resolve image '%s'. Registry returned %s", image, resp.Status)
+	}
+}
+
+// parseAuthChallenge parses the comma separated key="value" pairs of a
+// WWW-Authenticate header
+func parseAuthChallenge(s string) map[string]string {
+	params := make(map[string]string)
+
+	for _, pair := range authChallengePattern.FindAllStringSubmatch(s, -1) {
+		params[pair[1]] = pair[2]
+	}
+
+	return params
+}
+
+// dockerConfigAuth returns the base64 encoded basic auth credentials stored
+// for registry in the docker cli config file, if there are any
+func dockerConfigAuth(registry string) string {
+	dir := os.Getenv("DOCKER_CONFIG")
+
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".docker")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return ""
+	}
+
+	config := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+
+	key := registry
+
+	if registry == dockerHubRegistry {
+		key = dockerHubAuthKey
+	}
+
+	for _, k := range []string{key, "https://" + key} {
+		if auth, ok := config.Auths[k]; ok && auth.Auth != "" {
+			if _, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+				return auth.Auth
+			}
+		}
+	}
+
+	return ""
+}
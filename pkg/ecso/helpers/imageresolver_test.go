@@ -0,0 +1,186 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/bernos/ecso/pkg/ecso/api/mocks"
+)
+
+const testDigest = "sha256:4bcdffd70da292293d059d2435c7056711fab2655f8b74f48ad0abe042b63687"
+
+func TestParseImageReference(t *testing.T) {
+	cases := []struct {
+		image      string
+		registry   string
+		repository string
+		tag        string
+		digest     string
+	}{
+		{"nginx", dockerHubRegistry, "library/nginx", "latest", ""},
+		{"nginx:1.13", dockerHubRegistry, "library/nginx", "1.13", ""},
+		{"bernos/ecso:v1", dockerHubRegistry, "bernos/ecso", "v1", ""},
+		{"localhost:5000/app", "localhost:5000", "app", "latest", ""},
+		{"quay.io/org/app:2.0", "quay.io", "org/app", "2.0", ""},
+		{"123456789012.dkr.ecr.ap-southeast-2.amazonaws.com/app@" + testDigest, "123456789012.dkr.ecr.ap-southeast-2.amazonaws.com", "app", "", testDigest},
+	}
+
+	for _, c := range cases {
+		ref, err := ParseImageReference(c.image)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ref.Registry != c.registry || ref.Repository != c.repository || ref.Tag != c.tag || ref.Digest != c.digest {
+			t.Errorf("%s: want %s %s %s %s, got %s %s %s %s", c.image, c.registry, c.repository, c.tag, c.digest, ref.Registry, ref.Repository, ref.Tag, ref.Digest)
+		}
+	}
+}
+
+func newTestRegistry(t *testing.T, headDigest bool) *httptest.Server {
+	server := httptest.NewUnstartedServer(nil)
+	server.Config.Handler = newTestRegistryHandler(t, headDigest, server)
+	server.StartTLS()
+
+	return server
+}
+
+func newTestRegistryHandler(t *testing.T, headDigest bool, server *httptest.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				t.Errorf("Unexpected token scope %s", r.URL.Query().Get("scope"))
+			}
+			fmt.Fprint(w, `{"token": "secret"}`)
+
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+
+		case r.URL.Path == "/v2/team/app/manifests/v1":
+			if headDigest || r.Method == "GET" {
+				w.Header().Set("Docker-Content-Digest", testDigest)
+			}
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestRegistryImageResolver(t *testing.T) {
+	for _, headDigest := range []bool{true, false} {
+		server := newTestRegistry(t, headDigest)
+		defer server.Close()
+
+		var (
+			host     = strings.TrimPrefix(server.URL, "https://")
+			resolver = NewRegistryImageResolver(server.Client())
+		)
+
+		image, err := resolver.Resolve(context.Background(), host+"/team/app:v1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := host + "/team/app@" + testDigest; image != want {
+			t.Errorf("Want %s, got %s", want, image)
+		}
+
+		if _, err := resolver.Resolve(context.Background(), host+"/team/app:missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("Want not found error, got %v", err)
+		}
+	}
+}
+
+func TestRegistryImageResolverLocalRegistry(t *testing.T) {
+	server := httptest.NewUnstartedServer(nil)
+	server.Config.Handler = newTestRegistryHandler(t, true, server)
+	server.Start()
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	image, err := NewRegistryImageResolver(server.Client()).Resolve(context.Background(), host+"/team/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := host + "/team/app@" + testDigest; image != want {
+		t.Errorf("Want %s, got %s", want, image)
+	}
+}
+
+func TestIsLocalRegistry(t *testing.T) {
+	cases := map[string]bool{
+		"localhost:5000":      true,
+		"localhost":           true,
+		"127.0.0.1:5000":      true,
+		"[::1]:5000":          true,
+		"registry.local:5000": false,
+		"quay.io":             false,
+	}
+
+	for registry, want := range cases {
+		if got := isLocalRegistry(registry); got != want {
+			t.Errorf("%s: want %t, got %t", registry, want, got)
+		}
+	}
+}
+
+func TestRegistryImageResolverPinnedImage(t *testing.T) {
+	image := "nginx@" + testDigest
+
+	resolved, err := NewRegistryImageResolver(nil).Resolve(context.Background(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved != image {
+		t.Errorf("Want %s, got %s", image, resolved)
+	}
+}
+
+func TestECRImageResolver(t *testing.T) {
+	var (
+		repo     = "123456789012.dkr.ecr.ap-southeast-2.amazonaws.com/app"
+		ecrAPI   = &mocks.ECRAPIMock{}
+		resolver = NewImageResolver(ecrAPI, "ap-southeast-2", nil)
+	)
+
+	ecrAPI.BatchGetImageReturns(&ecr.BatchGetImageOutput{
+		Images: []*ecr.Image{
+			{ImageId: &ecr.ImageIdentifier{ImageDigest: aws.String(testDigest), ImageTag: aws.String("v1")}},
+		},
+	}, nil)
+
+	image, err := resolver.Resolve(context.Background(), repo+":v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := repo + "@" + testDigest; image != want {
+		t.Errorf("Want %s, got %s", want, image)
+	}
+
+	ecrAPI.BatchGetImageReturns(&ecr.BatchGetImageOutput{
+		Failures: []*ecr.ImageFailure{
+			{FailureCode: aws.String("ImageNotFound"), FailureReason: aws.String("Requested image not found")},
+		},
+	}, nil)
+
+	if _, err := resolver.Resolve(context.Background(), repo+":v2"); err == nil {
+		t.Error("Want error for missing image")
+	}
+
+	if _, err := resolver.Resolve(context.Background(), "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:v1"); err == nil {
+		t.Error("Want error for image in another region")
+	}
+}
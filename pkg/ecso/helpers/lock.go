@@ -0,0 +1,449 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LockTTL is how long a lock is held for without a heartbeat. Locks
+	// left behind by a process that was killed expire after this long
+	LockTTL = 2 * time.Minute
+
+	// lockHeartbeatInterval is how often a held lock's expiry is extended
+	lockHeartbeatInterval = LockTTL / 4
+)
+
+var (
+	// ErrLockExists is returned by a LockStore when creating a lock that
+	// already exists
+	ErrLockExists = errors.New("Lock already exists")
+
+	// ErrLockChanged is returned by a LockStore when updating or deleting a
+	// lock that has been changed since it was read
+	ErrLockChanged = errors.New("Lock has been changed by another process")
+)
+
+// Lock prevents concurrent changes to an environment or one of its services
+type Lock struct {
+	// Name identifies what is locked, either "environment" or
+	// "service/<name>"
+	Name        string
+	Environment string
+
+	// Holder is the ARN of the IAM principal that holds the lock
+	Holder string
+
+	// Action is the ecso command that the lock is held for
+	Action string
+
+	// Host is the hostname and process id of the process holding the lock
+	Host       string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+
+	// ETag identifies the version of the lock that was read from a
+	// LockStore, so that a lock is only changed by the process that holds it
+	ETag string `json:"-" yaml:"-"`
+}
+
+// Expired returns true if the lock has not been renewed before its expiry
+func (l *Lock) Expired(now time.Time) bool {
+	return now.After(l.ExpiresAt)
+}
+
+// LockHeldError is returned when a lock can't be acquired because another
+// process holds it
+type LockHeldError struct {
+	Lock *Lock
+}
+
+func (err *LockHeldError) Error() string {
+	return fmt.Sprintf(
+		"The %s is locked by %s, who is running `ecso %s` on %s since %s. If that process has died, the lock expires at %s, or run `ecso lock break %s` to remove it now",
+		lockDescription(err.Lock),
+		err.Lock.Holder,
+		err.Lock.Action,
+		err.Lock.Host,
+		err.Lock.AcquiredAt.Local().Format(time.RFC3339),
+		err.Lock.ExpiresAt.Local().Format(time.RFC3339),
+		lockBreakArgs(err.Lock))
+}
+
+// IsLockHeldError returns true if err is a LockHeldError
+func IsLockHeldError(err error) bool {
+	_, ok := err.(*LockHeldError)
+	return ok
+}
+
+func lockDescription(l *Lock) string {
+	if service := strings.TrimPrefix(l.Name, "service/"); service != l.Name {
+		return fmt.Sprintf("'%s' service in the '%s' environment", service, l.Environment)
+	}
+
+	return fmt.Sprintf("'%s' environment", l.Environment)
+}
+
+func lockBreakArgs(l *Lock) string {
+	if service := strings.TrimPrefix(l.Name, "service/"); service != l.Name {
+		return fmt.Sprintf("--service %s %s", service, l.Environment)
+	}
+
+	return l.Environment
+}
+
+// LockStore stores the locks of a single environment
+type LockStore interface {
+	// Create stores a new lock, returning ErrLockExists if a lock with the
+	// same name is already stored
+	Create(l *Lock) error
+
+	// Get returns the lock with the given name, or nil if there is none
+	Get(name string) (*Lock, error)
+
+	// Update replaces a lock, returning ErrLockChanged if it has changed
+	// since it was read
+	Update(l *Lock) error
+
+	// Delete removes a lock, returning ErrLockChanged if it has changed
+	// since it was read
+	Delete(l *Lock) error
+
+	// List returns all of the stored locks
+	List() ([]*Lock, error)
+}
+
+// NewLocker creates a Locker that keeps locks in a LockStore
+func NewLocker(store LockStore) *Locker {
+	return &Locker{
+		store:     store,
+		ttl:       LockTTL,
+		heartbeat: lockHeartbeatInterval,
+	}
+}
+
+// Locker acquires locks from a LockStore, and keeps them alive until they
+// are released
+type Locker struct {
+	store     LockStore
+	ttl       time.Duration
+	heartbeat time.Duration
+}
+
+// Acquire takes a lock on behalf of holder. A LockHeldError is returned if
+// another process holds the lock. Locks that have expired are broken. The
+// context of the returned HeldLock is derived from ctx, and is cancelled if
+// the lock is broken by another process while it is held
+func (l *Locker) Acquire(ctx context.Context, name, environment, holder, action string) (*HeldLock, error) {
+	host, _ := os.Hostname()
+	now := time.Now().UTC()
+
+	lock := &Lock{
+		Name:        name,
+		Environment: environment,
+		Holder:      holder,
+		Action:      action,
+		Host:        fmt.Sprintf("%s (pid %d)", host, os.Getpid()),
+		AcquiredAt:  now,
+		ExpiresAt:   now.Add(l.ttl),
+	}
+
+	// Only retry once, as another process that breaks the same expired lock
+	// at the same time will have created its own lock by then
+	for attempt := 0; ; attempt++ {
+		err := l.store.Create(lock)
+		if err == nil {
+			return l.hold(ctx, lock), nil
+		}
+
+		if err != ErrLockExists {
+			return nil, err
+		}
+
+		existing, err := l.store.Get(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil && (attempt > 0 || !existing.Expired(time.Now())) {
+			return nil, &LockHeldError{existing}
+		}
+
+		if existing != nil {
+			if err := l.store.Delete(existing); err != nil && err != ErrLockChanged {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (l *Locker) hold(ctx context.Context, lock *Lock) *HeldLock {
+	h := &HeldLock{
+		store: l.store,
+		lock:  lock,
+		ttl:   l.ttl,
+		done:  make(chan struct{}),
+	}
+
+	h.ctx, h.cancel = context.WithCancelCause(ctx)
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(l.heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				h.renew()
+			case <-h.done:
+				return
+			}
+		}
+	}()
+
+	return h
+}
+
+// HeldLock is a lock that has been acquired. Its expiry is extended in the
+// background until it is released
+type HeldLock struct {
+	store  LockStore
+	ttl    time.Duration
+	done   chan struct{}
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu   sync.Mutex
+	lock *Lock
+	err  error
+}
+
+func (h *HeldLock) renew() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.err != nil {
+		return
+	}
+
+	renewed := *h.lock
+	renewed.ExpiresAt = time.Now().UTC().Add(h.ttl)
+
+	if err := h.store.Update(&renewed); err != nil {
+		// Failing to renew is only a problem if the lock has been taken
+		// over, as a transient failure is retried on the next heartbeat
+		if err == ErrLockChanged {
+			h.err = fmt.Errorf("The lock on the %s was broken by another process while it was held", lockDescription(h.lock))
+			h.cancel(h.err)
+		}
+
+		return
+	}
+
+	h.lock = &renewed
+}
+
+// Context returns a context that is cancelled if the lock is broken by
+// another process, so that changes made while holding the lock are aborted.
+// context.Cause returns the reason that the lock was lost
+func (h *HeldLock) Context() context.Context {
+	return h.ctx
+}
+
+// Release stops renewing the lock and removes it from the store. An error is
+// returned if the lock was broken by another process while it was held
+func (h *HeldLock) Release() error {
+	close(h.done)
+	h.wg.Wait()
+
+	defer h.cancel(nil)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.err != nil {
+		return h.err
+	}
+
+	return h.store.Delete(h.lock)
+}
+
+// NewFileLockStore creates a LockStore that keeps each lock as a json file in
+// a local dir
+func NewFileLockStore(dir string) LockStore {
+	return &fileLockStore{dir: dir}
+}
+
+type fileLockStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (s *fileLockStore) filename(name string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name)+".json")
+}
+
+func (s *fileLockStore) Create(l *Lock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filename := s.filename(l.Name)
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return ErrLockExists
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return s.write(f, l)
+}
+
+// write encodes a lock to w, and sets its ETag to match
+func (s *fileLockStore) write(w io.Writer, l *Lock) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+
+	l.ETag = fileLockETag(b)
+
+	return nil
+}
+
+func fileLockETag(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *fileLockStore) Get(name string) (*Lock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.filename(name))
+}
+
+func (s *fileLockStore) read(filename string) (*Lock, error) {
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lock{}
+
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, err
+	}
+
+	l.ETag = fileLockETag(b)
+
+	return l, nil
+}
+
+// unchanged returns an error if the stored copy of the lock is not the
+// version that was read
+func (s *fileLockStore) unchanged(l *Lock) error {
+	current, err := s.read(s.filename(l.Name))
+	if err != nil {
+		return err
+	}
+
+	if current == nil || (l.ETag != "" && current.ETag != l.ETag) {
+		return ErrLockChanged
+	}
+
+	return nil
+}
+
+func (s *fileLockStore) Update(l *Lock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.unchanged(l); err != nil {
+		return err
+	}
+
+	f, err := os.Create(s.filename(l.Name))
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return s.write(f, l)
+}
+
+func (s *fileLockStore) Delete(l *Lock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.unchanged(l); err != nil {
+		return err
+	}
+
+	return os.Remove(s.filename(l.Name))
+}
+
+func (s *fileLockStore) List() ([]*Lock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	locks := make([]*Lock, 0)
+
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		l, err := s.read(path)
+		if err != nil || l == nil {
+			return err
+		}
+
+		locks = append(locks, l)
+
+		return nil
+	})
+
+	if os.IsNotExist(err) {
+		return locks, nil
+	}
+
+	return locks, err
+}
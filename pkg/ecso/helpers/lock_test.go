@@ -0,0 +1,273 @@
+package helpers
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func newTestLocker(store LockStore) *Locker {
+	return &Locker{
+		store:     store,
+		ttl:       time.Minute,
+		heartbeat: time.Hour,
+	}
+}
+
+func tempLockStore(t *testing.T) (LockStore, func()) {
+	dir, err := ioutil.TempDir("", "ecso-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewFileLockStore(dir), func() { os.RemoveAll(dir) }
+}
+
+func testLocker(t *testing.T, store LockStore) {
+	locker := newTestLocker(store)
+
+	held, err := locker.Acquire(context.Background(), "service/web", "prod", "arn:aws:iam::123456789012:user/alice", "service up")
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring lock: %s", err)
+	}
+
+	_, err = locker.Acquire(context.Background(), "service/web", "prod", "arn:aws:iam::123456789012:user/bob", "service rollback")
+	if !IsLockHeldError(err) {
+		t.Fatalf("Want LockHeldError, got %v", err)
+	}
+
+	if holder := err.(*LockHeldError).Lock.Holder; holder != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("Want lock held by alice, got %s", holder)
+	}
+
+	// Locks on other services are independent
+	other, err := locker.Acquire(context.Background(), "service/worker", "prod", "arn:aws:iam::123456789012:user/bob", "service up")
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring lock: %s", err)
+	}
+
+	locks, err := store.List()
+	if err != nil {
+		t.Fatalf("Unexpected error listing locks: %s", err)
+	}
+
+	if len(locks) != 2 {
+		t.Errorf("Want 2 locks, got %d", len(locks))
+	}
+
+	for _, h := range []*HeldLock{held, other} {
+		if err := h.Release(); err != nil {
+			t.Fatalf("Unexpected error releasing lock: %s", err)
+		}
+	}
+
+	if l, err := store.Get("service/web"); err != nil || l != nil {
+		t.Errorf("Want lock to be released, got %v, %v", l, err)
+	}
+}
+
+func TestLockerWithFileLockStore(t *testing.T) {
+	store, cleanup := tempLockStore(t)
+	defer cleanup()
+
+	testLocker(t, store)
+}
+
+func TestLockerBreaksExpiredLocks(t *testing.T) {
+	store, cleanup := tempLockStore(t)
+	defer cleanup()
+
+	stale := &Lock{
+		Name:        "environment",
+		Environment: "prod",
+		Holder:      "arn:aws:iam::123456789012:user/alice",
+		AcquiredAt:  time.Now().Add(-time.Hour),
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+
+	if err := store.Create(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	held, err := newTestLocker(store).Acquire(context.Background(), "environment", "prod", "arn:aws:iam::123456789012:user/bob", "environment up")
+	if err != nil {
+		t.Fatalf("Unexpected error acquiring expired lock: %s", err)
+	}
+
+	defer held.Release()
+
+	l, err := store.Get("environment")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l.Holder != "arn:aws:iam::123456789012:user/bob" {
+		t.Errorf("Want lock held by bob, got %s", l.Holder)
+	}
+}
+
+func TestHeldLockRenewsAndDetectsBrokenLocks(t *testing.T) {
+	store, cleanup := tempLockStore(t)
+	defer cleanup()
+
+	held, err := newTestLocker(store).Acquire(context.Background(), "environment", "prod", "arn:aws:iam::123456789012:user/alice", "environment up")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := held.lock.ExpiresAt
+	time.Sleep(10 * time.Millisecond)
+	held.renew()
+
+	if !held.lock.ExpiresAt.After(before) {
+		t.Errorf("Want expiry after %s, got %s", before, held.lock.ExpiresAt)
+	}
+
+	if held.Context().Err() != nil {
+		t.Errorf("Unexpected cancellation of a renewed lock's context")
+	}
+
+	// Someone breaks the lock and takes it
+	current, _ := store.Get("environment")
+	store.Delete(current)
+	store.Create(&Lock{Name: "environment", Environment: "prod", Holder: "arn:aws:iam::123456789012:user/bob"})
+
+	held.renew()
+
+	if held.Context().Err() == nil {
+		t.Errorf("Expected the context of a broken lock to be cancelled")
+	}
+
+	if err := held.Release(); err == nil {
+		t.Errorf("Expected an error releasing a broken lock")
+	}
+
+	if l, _ := store.Get("environment"); l == nil || l.Holder != "arn:aws:iam::123456789012:user/bob" {
+		t.Errorf("Releasing a broken lock must not delete the new holder's lock")
+	}
+}
+
+// fakeS3 implements the conditional object requests used by the s3 lock
+// store
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func fakeETag(b []byte) string {
+	sum := md5.Sum(b)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:]))
+}
+
+func (s *fakeS3) error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == "GET" && r.URL.Query().Get("prefix") != "" {
+		fmt.Fprint(w, "<ListBucketResult>")
+
+		for key := range s.objects {
+			if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+			}
+		}
+
+		fmt.Fprint(w, "</ListBucketResult>")
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	body, exists := s.objects[key]
+
+	if match := r.Header.Get("If-Match"); match != "" && (!exists || match != fakeETag(body)) {
+		s.error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	switch r.Method {
+	case "PUT":
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			s.error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		s.objects[key] = b
+		w.Header().Set("ETag", fakeETag(b))
+
+	case "GET":
+		if !exists {
+			s.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		w.Header().Set("ETag", fakeETag(body))
+		w.Write(body)
+
+	case "DELETE":
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3LockStore(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	sess := session.New(&aws.Config{
+		Region:           aws.String("ap-southeast-2"),
+		Endpoint:         aws.String(server.URL),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+	})
+
+	store := NewS3LockStore(s3.New(sess), "ap-southeast-2", "bucket", "myproject-prod/locks")
+
+	testLocker(t, store)
+
+	l := &Lock{Name: "environment", Environment: "prod"}
+
+	if err := store.Create(l); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := fake.objects["myproject-prod/locks/environment.json"]; !ok {
+		t.Errorf("Lock not stored under the lock prefix")
+	}
+
+	stale := *l
+	l.ExpiresAt = time.Now().Add(time.Minute)
+
+	if err := store.Update(l); err != nil {
+		t.Fatalf("Unexpected error updating lock: %s", err)
+	}
+
+	if err := store.Delete(&stale); err != ErrLockChanged {
+		t.Errorf("Want ErrLockChanged deleting a stale lock, got %v", err)
+	}
+
+	if err := store.Delete(l); err != nil {
+		t.Errorf("Unexpected error deleting lock: %s", err)
+	}
+}
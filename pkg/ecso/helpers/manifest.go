@@ -0,0 +1,172 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestStatusPending indicates that a package has been uploaded but
+	// not yet deployed
+	ManifestStatusPending = "pending"
+
+	// ManifestStatusDeployed indicates that a package was deployed
+	// successfully
+	ManifestStatusDeployed = "deployed"
+
+	// ManifestStatusFailed indicates that deploying a package failed
+	ManifestStatusFailed = "failed"
+
+	// ManifestStatusPlanned indicates that a package was created by `ecso
+	// service plan`, and has not been applied yet
+	ManifestStatusPlanned = "planned"
+
+	// ManifestStatusAbandoned is reported for planned packages that were
+	// never applied, and that can no longer be applied because a later
+	// version has since been deployed. It is never written to a manifest
+	ManifestStatusAbandoned = "abandoned"
+)
+
+// Manifest records the provenance of a deployment Package. It is uploaded
+// alongside the package templates as manifest.json
+type Manifest struct {
+	Version           string
+	Timestamp         time.Time
+	DeployedBy        string
+	Status            string
+	Git               *GitInfo         `json:",omitempty" yaml:",omitempty"`
+	Images            []*ManifestImage `json:",omitempty" yaml:",omitempty"`
+	TaskDefinitionArn string           `json:",omitempty" yaml:",omitempty"`
+	PromotedFrom      string           `json:",omitempty" yaml:",omitempty"`
+}
+
+// ManifestImage is a container image deployed by a Package
+type ManifestImage struct {
+	Container string
+	Image     string
+	Digest    string `json:",omitempty" yaml:",omitempty"`
+}
+
+// GitInfo describes the state of the git working copy that a Package was
+// created from
+type GitInfo struct {
+	Commit string
+	Branch string
+	Dirty  bool
+}
+
+// NewManifest creates a pending Manifest for the given version
+func NewManifest(version, deployedBy string, t time.Time) *Manifest {
+	return &Manifest{
+		Version:    version,
+		Timestamp:  t.UTC(),
+		DeployedBy: deployedBy,
+		Status:     ManifestStatusPending,
+		Images:     make([]*ManifestImage, 0),
+	}
+}
+
+// NewPromotedManifest creates a pending Manifest for a version promoted from
+// another environment. The git info, images and task definition are those of
+// the source version, rather than of the working copy doing the promotion
+func NewPromotedManifest(source *Manifest, promotedFrom, deployedBy string, t time.Time) *Manifest {
+	m := NewManifest(source.Version, deployedBy, t)
+	m.Images = append(m.Images, source.Images...)
+	m.TaskDefinitionArn = source.TaskDefinitionArn
+	m.PromotedFrom = promotedFrom
+
+	if source.Git != nil {
+		git := *source.Git
+		m.Git = &git
+	}
+
+	return m
+}
+
+// AddImage records a container image in the manifest. If the image reference
+// is pinned to a digest, the digest is recorded separately
+func (m *Manifest) AddImage(container, image string) {
+	img := &ManifestImage{
+		Container: container,
+		Image:     image,
+	}
+
+	if i := strings.Index(image, "@"); i >= 0 {
+		img.Digest = image[i+1:]
+	}
+
+	m.Images = append(m.Images, img)
+}
+
+// ImageReference returns the image that the manifest recorded for a
+// container, preferring the digest pinned form if a digest is known
+func (m *Manifest) ImageReference(container string) (string, bool) {
+	for _, img := range m.Images {
+		if img.Container != container {
+			continue
+		}
+
+		if img.Digest == "" || strings.Contains(img.Image, "@") {
+			return img.Image, true
+		}
+
+		return fmt.Sprintf("%s@%s", imageRepository(img.Image), img.Digest), true
+	}
+
+	return "", false
+}
+
+// imageRepository strips any tag or digest from an image reference
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image
+}
+
+// GetGitInfo returns details of the git working copy at dir. If dir is not
+// part of a git repository, or git is not installed, nil is returned
+func GetGitInfo(dir string) *GitInfo {
+	commit, err := git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil
+	}
+
+	branch, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil
+	}
+
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return nil
+	}
+
+	return &GitInfo{
+		Commit: commit,
+		Branch: branch,
+		Dirty:  status != "",
+	}
+}
+
+func git(dir string, args ...string) (string, error) {
+	var out bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManifestAddImage(t *testing.T) {
+	m := NewManifest("v1", "arn", time.Now())
+	m.AddImage("web", "nginx:latest")
+	m.AddImage("app", "repo/app@sha256:abc123")
+
+	if m.Images[0].Digest != "" {
+		t.Errorf("Want empty digest, got %s", m.Images[0].Digest)
+	}
+
+	if m.Images[1].Digest != "sha256:abc123" {
+		t.Errorf("Want sha256:abc123, got %s", m.Images[1].Digest)
+	}
+}
+
+func TestNewPromotedManifest(t *testing.T) {
+	source := NewManifest("v1", "arn:aws:iam::123:user/alice", time.Now().Add(-time.Hour))
+	source.Status = ManifestStatusDeployed
+	source.Git = &GitInfo{Commit: "abc123", Branch: "master"}
+	source.TaskDefinitionArn = "arn:aws:ecs:ap-southeast-2:123:task-definition/my-project-dev-web:3"
+	source.AddImage("web", "repo/web@sha256:abc123")
+
+	m := NewPromotedManifest(source, "dev@v1", "arn:aws:iam::123:user/bob", time.Now())
+
+	if m.Version != "v1" || m.Status != ManifestStatusPending || m.PromotedFrom != "dev@v1" {
+		t.Errorf("Unexpected manifest %+v", m)
+	}
+
+	if m.DeployedBy != "arn:aws:iam::123:user/bob" {
+		t.Errorf("Want the promoting principal, got %s", m.DeployedBy)
+	}
+
+	if m.Git == nil || m.Git.Commit != "abc123" || m.Git == source.Git {
+		t.Errorf("Want a copy of the source git info, got %+v", m.Git)
+	}
+
+	if m.TaskDefinitionArn != source.TaskDefinitionArn {
+		t.Errorf("Want task definition %s, got %s", source.TaskDefinitionArn, m.TaskDefinitionArn)
+	}
+
+	if ref, ok := m.ImageReference("web"); !ok || ref != "repo/web@sha256:abc123" {
+		t.Errorf("Want the source image, got %s", ref)
+	}
+}
+
+func TestManifestImageReference(t *testing.T) {
+	m := NewManifest("v1", "arn", time.Now())
+	m.AddImage("web", "nginx:latest")
+	m.AddImage("app", "localhost:5000/repo/app@sha256:abc123")
+	m.Images = append(m.Images, &ManifestImage{
+		Container: "worker",
+		Image:     "localhost:5000/repo/worker:1.0",
+		Digest:    "sha256:def456",
+	})
+
+	cases := map[string]string{
+		"web":    "nginx:latest",
+		"app":    "localhost:5000/repo/app@sha256:abc123",
+		"worker": "localhost:5000/repo/worker@sha256:def456",
+	}
+
+	for container, want := range cases {
+		got, ok := m.ImageReference(container)
+		if !ok || got != want {
+			t.Errorf("Want %s for %s container, got %s", want, container, got)
+		}
+	}
+
+	if _, ok := m.ImageReference("missing"); ok {
+		t.Error("Want no image for missing container")
+	}
+}
@@ -0,0 +1,244 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+// DeploymentEventType identifies the point in a deployment's lifecycle that
+// a DeploymentEvent was sent at
+type DeploymentEventType string
+
+const (
+	DeploymentStarted   DeploymentEventType = "started"
+	DeploymentSucceeded DeploymentEventType = "succeeded"
+	DeploymentFailed    DeploymentEventType = "failed"
+)
+
+// DeploymentAction is the kind of deployment that a DeploymentEvent is about
+type DeploymentAction string
+
+const (
+	DeploymentActionDeploy   DeploymentAction = "deploy"
+	DeploymentActionRollback DeploymentAction = "rollback"
+	DeploymentActionPromote  DeploymentAction = "promote"
+)
+
+// notifierTimeout limits how long a notifier waits for a webhook to respond,
+// so that an unresponsive endpoint can't hold up a deployment
+const notifierTimeout = 10 * time.Second
+
+// DeploymentEvent describes a change in the state of a deployment of a
+// service or environment
+type DeploymentEvent struct {
+	Type        DeploymentEventType `json:"type"`
+	Action      DeploymentAction    `json:"action"`
+	Project     string              `json:"project"`
+	Environment string              `json:"environment"`
+
+	// Service is empty for deployments of the environment itself
+	Service string `json:"service,omitempty"`
+	Version string `json:"version"`
+
+	// Source is the environment that a promoted version came from
+	Source string `json:"source,omitempty"`
+
+	// Actor is the ARN of the IAM principal that ran the deployment
+	Actor     string    `json:"actor,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Duration is the time since the deployment started, in seconds
+	Duration float64 `json:"duration,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Message is a human readable description of the event
+func (e *DeploymentEvent) Message() string {
+	var (
+		verb   = map[DeploymentAction]string{DeploymentActionDeploy: "deployment", DeploymentActionRollback: "rollback", DeploymentActionPromote: "promotion"}[e.Action]
+		target = e.Service
+		from   = ""
+	)
+
+	if target == "" {
+		target = fmt.Sprintf("environment %s", e.Environment)
+	}
+
+	if e.Source != "" {
+		from = fmt.Sprintf(" from %s", e.Source)
+	}
+
+	switch e.Type {
+	case DeploymentStarted:
+		return fmt.Sprintf("Commenced %s of %s version %s%s to %s", verb, target, e.Version, from, e.Environment)
+	case DeploymentFailed:
+		return fmt.Sprintf("Failed %s of %s version %s%s to %s. %s", verb, target, e.Version, from, e.Environment, e.Error)
+	default:
+		return fmt.Sprintf("Completed %s of %s version %s%s to %s", verb, target, e.Version, from, e.Environment)
+	}
+}
+
+// Notifier sends deployment events somewhere that people will see them
+type Notifier interface {
+	Notify(ctx context.Context, event *DeploymentEvent) error
+}
+
+// NewSNSNotifier creates a Notifier that publishes events to an SNS topic.
+// The message is the event's human readable message, and each of the event's
+// fields is sent as a message attribute, so that subscriptions can filter on
+// them
+func NewSNSNotifier(snsAPI snsiface.SNSAPI, topicArn string) Notifier {
+	return &snsNotifier{
+		snsAPI:   snsAPI,
+		topicArn: topicArn,
+	}
+}
+
+type snsNotifier struct {
+	snsAPI   snsiface.SNSAPI
+	topicArn string
+}
+
+func (n *snsNotifier) Notify(ctx context.Context, event *DeploymentEvent) error {
+	attributes := make(map[string]*sns.MessageAttributeValue)
+
+	for name, value := range map[string]string{
+		"Type":        string(event.Type),
+		"Action":      string(event.Action),
+		"Project":     event.Project,
+		"Environment": event.Environment,
+		"Service":     event.Service,
+		"Version":     event.Version,
+		"Actor":       event.Actor,
+	} {
+		if value != "" {
+			attributes[name] = &sns.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(value),
+			}
+		}
+	}
+
+	_, err := n.snsAPI.PublishWithContext(ctx, &sns.PublishInput{
+		Message:           aws.String(event.Message()),
+		MessageAttributes: attributes,
+		TopicArn:          aws.String(n.topicArn),
+	})
+
+	return err
+}
+
+// NewWebhookNotifier creates a Notifier that POSTs each event as JSON to a
+// url, with the given additional headers
+func NewWebhookNotifier(url string, headers map[string]string) Notifier {
+	return &webhookNotifier{
+		url:     url,
+		headers: headers,
+		payload: func(event *DeploymentEvent) interface{} { return event },
+	}
+}
+
+// NewSlackNotifier creates a Notifier that posts events to a Slack compatible
+// incoming webhook url
+func NewSlackNotifier(url string) Notifier {
+	return &webhookNotifier{
+		url:     url,
+		payload: slackPayload,
+	}
+}
+
+type webhookNotifier struct {
+	url     string
+	headers map[string]string
+	payload func(*DeploymentEvent) interface{}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event *DeploymentEvent) error {
+	body, err := json.Marshal(n.payload(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifierTimeout)
+	defer cancel()
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func slackPayload(event *DeploymentEvent) interface{} {
+	type field struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+		Short bool   `json:"short"`
+	}
+
+	color := map[DeploymentEventType]string{
+		DeploymentStarted:   "#439FE0",
+		DeploymentSucceeded: "good",
+		DeploymentFailed:    "danger",
+	}[event.Type]
+
+	fields := []field{
+		{"Project", event.Project, true},
+		{"Environment", event.Environment, true},
+	}
+
+	if event.Service != "" {
+		fields = append(fields, field{"Service", event.Service, true})
+	}
+
+	fields = append(fields, field{"Version", event.Version, true})
+
+	if event.Actor != "" {
+		fields = append(fields, field{"Actor", event.Actor, false})
+	}
+
+	if event.Duration > 0 {
+		fields = append(fields, field{"Duration", (time.Duration(event.Duration) * time.Second).String(), true})
+	}
+
+	if event.Error != "" {
+		fields = append(fields, field{"Error", event.Error, false})
+	}
+
+	return map[string]interface{}{
+		"text": event.Message(),
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"color":  color,
+				"fields": fields,
+				"ts":     event.Timestamp.Unix(),
+			},
+		},
+	}
+}
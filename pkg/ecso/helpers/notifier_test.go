@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+var testDeploymentEvent = &DeploymentEvent{
+	Type:        DeploymentFailed,
+	Action:      DeploymentActionPromote,
+	Project:     "myproject",
+	Environment: "prod",
+	Service:     "web",
+	Version:     "1.2.3",
+	Source:      "staging",
+	Actor:       "arn:aws:iam::123456789012:user/deployer",
+	Timestamp:   time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC),
+	Duration:    75,
+	Error:       "Stack update failed",
+}
+
+func TestDeploymentEventMessage(t *testing.T) {
+	tests := []struct {
+		event    DeploymentEvent
+		expected string
+	}{
+		{
+			event:    DeploymentEvent{Type: DeploymentStarted, Action: DeploymentActionDeploy, Environment: "dev", Service: "web", Version: "1.0.0"},
+			expected: "Commenced deployment of web version 1.0.0 to dev",
+		},
+		{
+			event:    DeploymentEvent{Type: DeploymentSucceeded, Action: DeploymentActionRollback, Environment: "dev", Service: "web", Version: "0.9.0"},
+			expected: "Completed rollback of web version 0.9.0 to dev",
+		},
+		{
+			event:    DeploymentEvent{Type: DeploymentSucceeded, Action: DeploymentActionDeploy, Environment: "dev", Version: "20170301"},
+			expected: "Completed deployment of environment dev version 20170301 to dev",
+		},
+		{
+			event:    *testDeploymentEvent,
+			expected: "Failed promotion of web version 1.2.3 from staging to prod. Stack update failed",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.event.Message(); got != test.expected {
+			t.Errorf("Want %q, got %q", test.expected, got)
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		received DeploymentEvent
+		token    string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Token")
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Unexpected error decoding payload: %s", err)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, map[string]string{"X-Token": "secret"})
+
+	if err := notifier.Notify(context.Background(), testDeploymentEvent); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if token != "secret" {
+		t.Errorf("Want header X-Token = secret, got %q", token)
+	}
+
+	if received != *testDeploymentEvent {
+		t.Errorf("Want %#v, got %#v", *testDeploymentEvent, received)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(server.URL, nil).Notify(context.Background(), testDeploymentEvent); err == nil {
+		t.Errorf("Expected an error for a 403 response")
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var payload struct {
+		Text        string
+		Attachments []struct {
+			Color  string
+			Fields []struct {
+				Title string
+				Value string
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("Unexpected error decoding payload: %s", err)
+		}
+	}))
+	defer server.Close()
+
+	if err := NewSlackNotifier(server.URL).Notify(context.Background(), testDeploymentEvent); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if payload.Text != testDeploymentEvent.Message() {
+		t.Errorf("Want text %q, got %q", testDeploymentEvent.Message(), payload.Text)
+	}
+
+	if len(payload.Attachments) != 1 {
+		t.Fatalf("Want 1 attachment, got %d", len(payload.Attachments))
+	}
+
+	if payload.Attachments[0].Color != "danger" {
+		t.Errorf("Want color danger, got %q", payload.Attachments[0].Color)
+	}
+
+	fields := make(map[string]string)
+
+	for _, f := range payload.Attachments[0].Fields {
+		fields[f.Title] = f.Value
+	}
+
+	for title, value := range map[string]string{
+		"Service":  "web",
+		"Version":  "1.2.3",
+		"Duration": "1m15s",
+		"Error":    "Stack update failed",
+	} {
+		if fields[title] != value {
+			t.Errorf("Want field %s = %q, got %q", title, value, fields[title])
+		}
+	}
+}
+
+type notifierSNSMock struct {
+	snsiface.SNSAPI
+	input *sns.PublishInput
+}
+
+func (m *notifierSNSMock) PublishWithContext(ctx aws.Context, input *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error) {
+	m.input = input
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSNSNotifier(t *testing.T) {
+	mock := &notifierSNSMock{}
+
+	if err := NewSNSNotifier(mock, "arn:aws:sns:ap-southeast-2:123456789012:topic").Notify(context.Background(), testDeploymentEvent); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if got := aws.StringValue(mock.input.Message); got != testDeploymentEvent.Message() {
+		t.Errorf("Want message %q, got %q", testDeploymentEvent.Message(), got)
+	}
+
+	for name, value := range map[string]string{
+		"Type":        "failed",
+		"Action":      "promote",
+		"Environment": "prod",
+		"Service":     "web",
+	} {
+		attr, ok := mock.input.MessageAttributes[name]
+		if !ok {
+			t.Errorf("Missing message attribute %s", name)
+			continue
+		}
+
+		if got := aws.StringValue(attr.StringValue); got != value {
+			t.Errorf("Want attribute %s = %q, got %q", name, value, got)
+		}
+	}
+}
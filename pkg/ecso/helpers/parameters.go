@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// GetTemplateParameters returns the names of the parameters declared by a
+// cloudformation template
+func GetTemplateParameters(body []byte) ([]string, error) {
+	var template struct {
+		Parameters map[string]interface{} `yaml:"Parameters"`
+	}
+
+	if err := yaml.Unmarshal(body, &template); err != nil {
+		return nil, fmt.Errorf("Failed to parse template. %s", err.Error())
+	}
+
+	names := make([]string, 0, len(template.Parameters))
+
+	for name := range template.Parameters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ValidateParameters returns an error listing any of params that are not
+// declared by the template
+func ValidateParameters(template []byte, params map[string]string) error {
+	declared, err := GetTemplateParameters(template)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+
+	for _, name := range declared {
+		known[name] = true
+	}
+
+	unknown := make([]string, 0)
+
+	for name := range params {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+
+	return fmt.Errorf("The template does not declare the parameter(s) %s. Declared parameters are %s", strings.Join(unknown, ", "), strings.Join(declared, ", "))
+}
+
+// ValidateTemplateFileParameters returns an error listing any of params that
+// are not declared by the template file
+func ValidateTemplateFileParameters(templateFile string, params map[string]string) error {
+	body, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateParameters(body, params); err != nil {
+		return fmt.Errorf("%s: %s", templateFile, err.Error())
+	}
+
+	return nil
+}
+
+// TemplateFileDeclaresParameter returns true if the template file declares a
+// parameter with the given name
+func TemplateFileDeclaresParameter(templateFile, name string) (bool, error) {
+	body, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return false, err
+	}
+
+	declared, err := GetTemplateParameters(body)
+	if err != nil {
+		return false, err
+	}
+
+	for _, param := range declared {
+		if param == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
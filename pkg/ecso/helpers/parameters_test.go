@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var parametersTestTemplate = []byte(`
+Parameters:
+  Cluster:
+    Type: String
+  DesiredCount:
+    Type: Number
+    Default: 1
+  InstanceType:
+    Type: String
+
+Conditions:
+  HasInstanceType: !Not [!Equals [!Ref InstanceType, ""]]
+
+Resources:
+  Service:
+    Type: AWS::ECS::Service
+    Properties:
+      Cluster: !Ref Cluster
+      DesiredCount: !Ref DesiredCount
+`)
+
+func TestGetTemplateParameters(t *testing.T) {
+	params, err := GetTemplateParameters(parametersTestTemplate)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	want := []string{"Cluster", "DesiredCount", "InstanceType"}
+
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("Want %v, got %v", want, params)
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	if err := ValidateParameters(parametersTestTemplate, map[string]string{"DesiredCount": "2"}); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+
+	err := ValidateParameters(parametersTestTemplate, map[string]string{
+		"DesiredCount": "2",
+		"Memory":       "512",
+		"Cpu":          "256",
+	})
+
+	if err == nil {
+		t.Fatalf("Expected an error for undeclared parameters")
+	}
+
+	if !strings.Contains(err.Error(), "Cpu, Memory") {
+		t.Errorf("Expected error to list undeclared parameters, got %s", err)
+	}
+}
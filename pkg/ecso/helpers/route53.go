@@ -1,16 +1,33 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 )
 
+// RecordSet is a route 53 resource record set, along with the ID of the
+// hosted zone that it belongs to
+type RecordSet struct {
+	*route53.ResourceRecordSet
+	HostedZoneID string
+}
+
 type Route53Helper interface {
-	DeleteResourceRecordSetsByName(name, zone, reason string, w io.Writer) error
+	DeleteResourceRecordSetsByName(ctx context.Context, name, zone, reason string, w io.Writer) error
+
+	// DeleteRecordSets deletes the record sets, in a single change batch per
+	// hosted zone
+	DeleteRecordSets(ctx context.Context, records []*RecordSet, reason string, w io.Writer) error
+
+	// ListRecordSets returns all record sets for name and its subdomains
+	// from the hosted zones named zone
+	ListRecordSets(ctx context.Context, name, zone string) ([]*RecordSet, error)
 }
 
 func NewRoute53Helper(route53API route53iface.Route53API) Route53Helper {
@@ -23,54 +40,115 @@ type route53Helper struct {
 	route53API route53iface.Route53API
 }
 
-func (h *route53Helper) DeleteResourceRecordSetsByName(name, zone, reason string, w io.Writer) error {
-	zones, err := h.route53API.ListHostedZonesByName(&route53.ListHostedZonesByNameInput{
-		DNSName: aws.String(zone),
-	})
-
+func (h *route53Helper) DeleteResourceRecordSetsByName(ctx context.Context, name, zone, reason string, w io.Writer) error {
+	records, err := h.ListRecordSets(ctx, name, zone)
 	if err != nil {
 		return err
 	}
 
-	for _, zone := range zones.HostedZones {
-		resp, err := h.route53API.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
-			HostedZoneId: zone.Id,
+	matches := make([]*RecordSet, 0)
+
+	for _, record := range records {
+		if *record.Name == fqdn(name) {
+			matches = append(matches, record)
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintf(w, "No recordsets matching '%s' found\n", name)
+		return nil
+	}
+
+	return h.DeleteRecordSets(ctx, matches, reason, w)
+}
+
+func (h *route53Helper) DeleteRecordSets(ctx context.Context, records []*RecordSet, reason string, w io.Writer) error {
+	zones := make([]string, 0)
+	changes := make(map[string][]*route53.Change)
+
+	for _, record := range records {
+		if _, ok := changes[record.HostedZoneID]; !ok {
+			zones = append(zones, record.HostedZoneID)
+		}
+
+		changes[record.HostedZoneID] = append(changes[record.HostedZoneID], &route53.Change{
+			Action:            aws.String("DELETE"),
+			ResourceRecordSet: record.ResourceRecordSet,
 		})
 
-		if err != nil {
+		fmt.Fprintf(w, "Deleting recordset %s\n", *record.Name)
+	}
+
+	for _, zone := range zones {
+		if _, err := h.route53API.ChangeResourceRecordSetsWithContext(ctx, &route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(zone),
+			ChangeBatch: &route53.ChangeBatch{
+				Comment: aws.String(reason),
+				Changes: changes[zone],
+			},
+		}); err != nil {
 			return err
 		}
 
-		changes := make([]*route53.Change, 0)
+		fmt.Fprint(w, "Done\n")
+	}
 
-		for _, record := range resp.ResourceRecordSets {
-			if *record.Name == name {
-				changes = append(changes, &route53.Change{
-					Action:            aws.String("DELETE"),
-					ResourceRecordSet: record,
-				})
+	return nil
+}
 
-				fmt.Fprintf(w, "Deleting recordset %s\n", *record.Name)
-			}
+func (h *route53Helper) ListRecordSets(ctx context.Context, name, zone string) ([]*RecordSet, error) {
+	zones, err := h.route53API.ListHostedZonesByNameWithContext(ctx, &route53.ListHostedZonesByNameInput{
+		DNSName: aws.String(zone),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	name = fqdn(name)
+	result := make([]*RecordSet, 0)
+
+	for _, z := range zones.HostedZones {
+		// ListHostedZonesByName returns zones in order, starting from the
+		// given name, so it may include zones that don't match
+		if *z.Name != fqdn(zone) {
+			continue
 		}
 
-		if len(changes) > 0 {
-			if _, err := h.route53API.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
-				HostedZoneId: zone.Id,
-				ChangeBatch: &route53.ChangeBatch{
-					Comment: aws.String(reason),
-					Changes: changes,
-				},
-			}); err != nil {
-				return err
+		params := &route53.ListResourceRecordSetsInput{
+			HostedZoneId:    z.Id,
+			StartRecordName: aws.String(name),
+		}
+
+		// Record sets are ordered by name with the labels reversed, so a
+		// name's subdomains follow it directly
+		err := h.route53API.ListResourceRecordSetsPagesWithContext(ctx, params, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			for _, record := range page.ResourceRecordSets {
+				if *record.Name != name && !strings.HasSuffix(*record.Name, "."+name) {
+					return false
+				}
+
+				result = append(result, &RecordSet{
+					ResourceRecordSet: record,
+					HostedZoneID:      *z.Id,
+				})
 			}
 
-			fmt.Fprint(w, "Done\n")
-		} else {
+			return true
+		})
 
-			fmt.Fprintf(w, "No recordsets matching '%s' found\n", name)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return result, nil
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	return name + "."
 }
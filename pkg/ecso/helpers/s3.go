@@ -2,26 +2,30 @@ package helpers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path"
-	"path/filepath"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/bernos/ecso/pkg/ecso"
 )
 
 type S3Helper interface {
-	EnsureBucket(bucket string, w io.Writer) error
-	CreateBucket(bucket string, w io.Writer) error
-	UploadDir(dir, bucket, prefix string, w io.Writer) error
-	UploadObjectJSON(o interface{}, bucket, key string, w io.Writer) error
-	DownloadObjectJSON(o interface{}, bucket, key string) error
+	EnsureBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error
+	CreateBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error
+	ConfigureBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error
+	CheckBucket(bucket string, config *ecso.BucketConfiguration) (BucketCheckList, error)
+	Sync(ctx context.Context, objects []*SyncObject, bucket string, options *SyncOptions, w io.Writer) (*SyncResult, error)
+	SyncDir(ctx context.Context, dir, bucket, prefix string, options *SyncOptions, w io.Writer) (*SyncResult, error)
+	UploadObjectJSON(ctx context.Context, o interface{}, bucket, key string, w io.Writer) error
+	DownloadObjectJSON(ctx context.Context, o interface{}, bucket, key string) error
+	ListObjects(ctx context.Context, bucket, prefix string) ([]*s3.Object, error)
+	DeletePrefix(ctx context.Context, bucket, prefix string, w io.Writer) error
 }
 
 type s3Helper struct {
@@ -36,7 +40,9 @@ func NewS3Helper(s3Client s3iface.S3API, region string) S3Helper {
 	}
 }
 
-func (h *s3Helper) EnsureBucket(bucket string, w io.Writer) error {
+// EnsureBucket creates the bucket with the given configuration if it does not
+// exist. The configuration of existing buckets is not changed
+func (h *s3Helper) EnsureBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error {
 	params := &s3.HeadBucketInput{
 		Bucket: aws.String(bucket), // Required
 	}
@@ -45,7 +51,7 @@ func (h *s3Helper) EnsureBucket(bucket string, w io.Writer) error {
 
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFound" {
-			return h.CreateBucket(bucket, w)
+			return h.CreateBucket(bucket, config, w)
 		}
 
 		return err
@@ -54,25 +60,25 @@ func (h *s3Helper) EnsureBucket(bucket string, w io.Writer) error {
 	return nil
 }
 
-func (h *s3Helper) CreateBucket(bucket string, w io.Writer) error {
-	params := &s3.CreateBucketInput{
-		Bucket: aws.String(bucket), // Required
-		CreateBucketConfiguration: &s3.CreateBucketConfiguration{
-			LocationConstraint: aws.String(h.region),
-		},
-	}
-
-	fmt.Fprintf(w, "Creating bucket '%s' in region '%s'\n", bucket, h.region)
+// requireBucket returns an error if the bucket does not exist. Only ecso
+// environment up creates the bucket, so that it always has the configuration
+// set in project.json
+func (h *s3Helper) requireBucket(ctx context.Context, bucket string) error {
+	_, err := h.s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
 
-	_, err := h.s3Client.CreateBucket(params)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFound" {
+		return fmt.Errorf("The ecso bucket '%s' does not exist. Run `ecso environment up` first", bucket)
+	}
 
 	return err
 }
 
-func (h *s3Helper) UploadObjectJSON(o interface{}, bucket, key string, w io.Writer) error {
+func (h *s3Helper) UploadObjectJSON(ctx context.Context, o interface{}, bucket, key string, w io.Writer) error {
 	uploader := s3manager.NewUploaderWithClient(h.s3Client)
 
-	if err := h.EnsureBucket(bucket, w); err != nil {
+	if err := h.requireBucket(ctx, bucket); err != nil {
 		return err
 	}
 
@@ -87,20 +93,20 @@ func (h *s3Helper) UploadObjectJSON(o interface{}, bucket, key string, w io.Writ
 		Body:   bytes.NewReader(b),
 	}
 
-	if _, err := uploader.Upload(params); err != nil {
+	if _, err := uploader.UploadWithContext(ctx, params); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (h *s3Helper) DownloadObjectJSON(o interface{}, bucket, key string) error {
+func (h *s3Helper) DownloadObjectJSON(ctx context.Context, o interface{}, bucket, key string) error {
 	params := &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	}
 
-	resp, err := h.s3Client.GetObject(params)
+	resp, err := h.s3Client.GetObjectWithContext(ctx, params)
 	if err != nil {
 		return err
 	}
@@ -110,43 +116,67 @@ func (h *s3Helper) DownloadObjectJSON(o interface{}, bucket, key string) error {
 	return json.NewDecoder(resp.Body).Decode(o)
 }
 
-func (h *s3Helper) UploadDir(dir, bucket, prefix string, w io.Writer) error {
-	uploader := s3manager.NewUploaderWithClient(h.s3Client)
+// ListObjects returns all objects in the bucket with keys beginning with
+// prefix, following pagination
+func (h *s3Helper) ListObjects(ctx context.Context, bucket, prefix string) ([]*s3.Object, error) {
+	objects := make([]*s3.Object, 0)
+
+	params := &s3.ListObjectsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	err := h.s3Client.ListObjectsPagesWithContext(ctx, params, func(page *s3.ListObjectsOutput, lastPage bool) bool {
+		objects = append(objects, page.Contents...)
+		return !lastPage
+	})
 
-	if err := h.EnsureBucket(bucket, w); err != nil {
+	return objects, err
+}
+
+// DeletePrefix deletes all objects in the bucket with keys beginning with
+// prefix. In a versioned bucket this only adds delete markers, and earlier
+// versions of the objects are kept until they are expired
+func (h *s3Helper) DeletePrefix(ctx context.Context, bucket, prefix string, w io.Writer) error {
+	objects, err := h.ListObjects(ctx, bucket, prefix)
+	if err != nil {
 		return err
 	}
 
-	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// DeleteObjects accepts at most 1000 keys per request
+	batchSize := 1000
 
-		if info.IsDir() {
-			return nil
-		}
+	for i := 0; i < len(objects); i += batchSize {
+		end := i + batchSize
 
-		reader, err := os.Open(file)
-		if err != nil {
-			return err
+		if end > len(objects) {
+			end = len(objects)
 		}
 
-		defer reader.Close()
-
-		key := path.Join(prefix, string(file[len(dir):]))
+		ids := make([]*s3.ObjectIdentifier, 0)
 
-		params := &s3manager.UploadInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(key),
-			Body:   reader,
+		for _, o := range objects[i:end] {
+			ids = append(ids, &s3.ObjectIdentifier{Key: o.Key})
 		}
 
-		fmt.Fprintf(w, "Uploading resource '%s' to 's3://%s/%s'\n", file, bucket, prefix)
+		resp, err := h.s3Client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &s3.Delete{
+				Objects: ids,
+				Quiet:   aws.Bool(true),
+			},
+		})
 
-		if _, err := uploader.Upload(params); err != nil {
+		if err != nil {
 			return err
 		}
 
-		return nil
-	})
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("Failed to delete 's3://%s/%s'. %s", bucket, *resp.Errors[0].Key, *resp.Errors[0].Message)
+		}
+	}
+
+	fmt.Fprintf(w, "Deleted %d objects from 's3://%s/%s'\n", len(objects), bucket, prefix)
+
+	return nil
 }
@@ -0,0 +1,308 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+const (
+	// bucketLifecycleRuleID identifies the lifecycle rule that ecso adds to
+	// its bucket
+	bucketLifecycleRuleID = "ecso-expire-noncurrent-versions"
+)
+
+// CreateBucket creates the bucket, and configures it according to config. If
+// config is nil the default configuration is used
+func (h *s3Helper) CreateBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error {
+	params := &s3.CreateBucketInput{
+		Bucket: aws.String(bucket), // Required
+	}
+
+	// us-east-1 is the default location, and must not be given as a
+	// location constraint
+	if h.region != "us-east-1" {
+		params.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(h.region),
+		}
+	}
+
+	fmt.Fprintf(w, "Creating bucket '%s' in region '%s'\n", bucket, h.region)
+
+	if _, err := h.s3Client.CreateBucket(params); err != nil {
+		return err
+	}
+
+	return h.ConfigureBucket(bucket, config, ui.NewPrefixWriter(w, "  "))
+}
+
+// ConfigureBucket enables default encryption, blocks public access, enables
+// versioning, and adds a lifecycle rule that expires old versions of objects.
+// The lifecycle rule replaces any existing lifecycle configuration, so it
+// should only be used for buckets that are managed by ecso
+func (h *s3Helper) ConfigureBucket(bucket string, config *ecso.BucketConfiguration, w io.Writer) error {
+	if config == nil {
+		config = defaultBucketConfiguration()
+	}
+
+	fmt.Fprintf(w, "Enabling default encryption with %s\n", encryptionDescription(config.KMSKeyID))
+
+	encryption := &s3.ServerSideEncryptionByDefault{
+		SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+	}
+
+	if config.KMSKeyID != "" {
+		encryption.SSEAlgorithm = aws.String(s3.ServerSideEncryptionAwsKms)
+		encryption.KMSMasterKeyID = aws.String(config.KMSKeyID)
+	}
+
+	if _, err := h.s3Client.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{
+				{ApplyServerSideEncryptionByDefault: encryption},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Blocking public access\n")
+
+	if _, err := h.s3Client.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Enabling versioning\n")
+
+	if _, err := h.s3Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(s3.BucketVersioningStatusEnabled),
+		},
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Expiring old versions of objects after %d days\n", config.NoncurrentVersionExpirationDays)
+
+	_, err := h.s3Client.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					ID:     aws.String(bucketLifecycleRuleID),
+					Status: aws.String(s3.ExpirationStatusEnabled),
+					Filter: &s3.LifecycleRuleFilter{Prefix: aws.String("")},
+					NoncurrentVersionExpiration: &s3.NoncurrentVersionExpiration{
+						NoncurrentDays: aws.Int64(int64(config.NoncurrentVersionExpirationDays)),
+					},
+					AbortIncompleteMultipartUpload: &s3.AbortIncompleteMultipartUpload{
+						DaysAfterInitiation: aws.Int64(ecso.DefaultIncompleteUploadExpirationDays),
+					},
+				},
+			},
+		},
+	})
+
+	return err
+}
+
+// CheckBucket compares the settings of an existing bucket with config
+func (h *s3Helper) CheckBucket(bucket string, config *ecso.BucketConfiguration) (BucketCheckList, error) {
+	if config == nil {
+		config = defaultBucketConfiguration()
+	}
+
+	state := &bucketState{}
+
+	encryption, err := h.s3Client.GetBucketEncryption(&s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+
+	if err != nil {
+		if !isAWSErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			return nil, err
+		}
+	} else if encryption.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range encryption.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil {
+				state.sseAlgorithm = aws.StringValue(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+				state.kmsKeyID = aws.StringValue(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)
+			}
+		}
+	}
+
+	publicAccess, err := h.s3Client.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+	})
+
+	if err != nil {
+		if !isAWSErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+			return nil, err
+		}
+	} else if c := publicAccess.PublicAccessBlockConfiguration; c != nil {
+		state.publicAccessBlocked = aws.BoolValue(c.BlockPublicAcls) &&
+			aws.BoolValue(c.BlockPublicPolicy) &&
+			aws.BoolValue(c.IgnorePublicAcls) &&
+			aws.BoolValue(c.RestrictPublicBuckets)
+	}
+
+	versioning, err := h.s3Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state.versioning = aws.StringValue(versioning.Status)
+
+	lifecycle, err := h.s3Client.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+
+	switch {
+	case err != nil && !isAWSErrorCode(err, "NoSuchLifecycleConfiguration"):
+		return nil, err
+	case err == nil:
+		for _, rule := range lifecycle.Rules {
+			if aws.StringValue(rule.Status) == s3.ExpirationStatusEnabled && rule.NoncurrentVersionExpiration != nil {
+				state.noncurrentExpirationDays = int(aws.Int64Value(rule.NoncurrentVersionExpiration.NoncurrentDays))
+			}
+		}
+	}
+
+	return checkBucket(config, state), nil
+}
+
+// bucketState holds the settings of an existing bucket that are audited by
+// CheckBucket
+type bucketState struct {
+	sseAlgorithm             string
+	kmsKeyID                 string
+	publicAccessBlocked      bool
+	versioning               string
+	noncurrentExpirationDays int
+}
+
+func checkBucket(config *ecso.BucketConfiguration, state *bucketState) BucketCheckList {
+	var (
+		checks         = make(BucketCheckList, 0)
+		encryption     = "none"
+		publicAccess   = "not blocked"
+		versioning     = state.versioning
+		expiration     = "never"
+		wantExpiration = fmt.Sprintf("%d days", config.NoncurrentVersionExpirationDays)
+	)
+
+	switch state.sseAlgorithm {
+	case s3.ServerSideEncryptionAwsKms:
+		encryption = encryptionDescription(state.kmsKeyID)
+		if state.kmsKeyID == "" {
+			encryption = "KMS key aws/s3"
+		}
+	case s3.ServerSideEncryptionAes256:
+		encryption = encryptionDescription("")
+	}
+
+	if state.publicAccessBlocked {
+		publicAccess = "blocked"
+	}
+
+	if versioning == "" {
+		versioning = "Disabled"
+	}
+
+	if state.noncurrentExpirationDays > 0 {
+		expiration = fmt.Sprintf("%d days", state.noncurrentExpirationDays)
+	}
+
+	add := func(setting, expected, actual string, ok bool) {
+		checks = append(checks, &BucketCheck{
+			Setting:  setting,
+			Expected: expected,
+			Actual:   actual,
+			OK:       ok,
+		})
+	}
+
+	// Encryption with any KMS key is stronger than S3 managed keys, so only
+	// require a specific key if one is configured
+	encryptionOK := state.sseAlgorithm != ""
+	if config.KMSKeyID != "" {
+		encryptionOK = state.sseAlgorithm == s3.ServerSideEncryptionAwsKms && state.kmsKeyID == config.KMSKeyID
+	}
+
+	add("Default encryption", encryptionDescription(config.KMSKeyID), encryption, encryptionOK)
+	add("Public access", "blocked", publicAccess, state.publicAccessBlocked)
+	add("Versioning", s3.BucketVersioningStatusEnabled, versioning, state.versioning == s3.BucketVersioningStatusEnabled)
+	add("Noncurrent version expiration", wantExpiration, expiration, state.noncurrentExpirationDays == config.NoncurrentVersionExpirationDays)
+
+	return checks
+}
+
+// BucketCheck is the result of comparing a single setting of a bucket with
+// the expected configuration
+type BucketCheck struct {
+	Setting  string
+	Expected string
+	Actual   string
+	OK       bool
+}
+
+// BucketCheckList is the result of auditing a bucket
+type BucketCheckList []*BucketCheck
+
+// OK returns true if all of the checks passed
+func (l BucketCheckList) OK() bool {
+	for _, c := range l {
+		if !c.OK {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l BucketCheckList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("SETTING|EXPECTED|ACTUAL|STATUS"))
+
+	for _, c := range l {
+		status := "OK"
+
+		if !c.OK {
+			status = "FAIL"
+		}
+
+		tw.Write([]byte(fmt.Sprintf("%s|%s|%s|%s", c.Setting, c.Expected, c.Actual, status)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+func defaultBucketConfiguration() *ecso.BucketConfiguration {
+	return (&ecso.Environment{}).GetBucketConfiguration()
+}
+
+func encryptionDescription(kmsKeyID string) string {
+	if kmsKeyID != "" {
+		return fmt.Sprintf("KMS key %s", kmsKeyID)
+	}
+
+	return "S3 managed keys"
+}
@@ -0,0 +1,123 @@
+package helpers
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+func TestCheckBucket(t *testing.T) {
+	hardened := &bucketState{
+		sseAlgorithm:             s3.ServerSideEncryptionAes256,
+		publicAccessBlocked:      true,
+		versioning:               "Enabled",
+		noncurrentExpirationDays: 90,
+	}
+
+	tests := []struct {
+		name   string
+		config *ecso.BucketConfiguration
+		state  *bucketState
+		failed []string
+	}{
+		{
+			name:   "hardened bucket",
+			config: &ecso.BucketConfiguration{NoncurrentVersionExpirationDays: 90},
+			state:  hardened,
+		},
+		{
+			name:   "legacy bucket",
+			config: &ecso.BucketConfiguration{NoncurrentVersionExpirationDays: 90},
+			state:  &bucketState{},
+			failed: []string{"Default encryption", "Public access", "Versioning", "Noncurrent version expiration"},
+		},
+		{
+			name:   "wrong kms key",
+			config: &ecso.BucketConfiguration{KMSKeyID: "alias/ecso", NoncurrentVersionExpirationDays: 90},
+			state:  hardened,
+			failed: []string{"Default encryption"},
+		},
+		{
+			name:   "different expiration",
+			config: &ecso.BucketConfiguration{NoncurrentVersionExpirationDays: 30},
+			state:  hardened,
+			failed: []string{"Noncurrent version expiration"},
+		},
+	}
+
+	for _, test := range tests {
+		checks := checkBucket(test.config, test.state)
+
+		failed := make([]string, 0)
+
+		for _, c := range checks {
+			if !c.OK {
+				failed = append(failed, c.Setting)
+			}
+		}
+
+		if len(failed) != len(test.failed) {
+			t.Errorf("%s: want failed checks %v, got %v", test.name, test.failed, failed)
+			continue
+		}
+
+		for i := range failed {
+			if failed[i] != test.failed[i] {
+				t.Errorf("%s: want failed checks %v, got %v", test.name, test.failed, failed)
+			}
+		}
+
+		if checks.OK() != (len(test.failed) == 0) {
+			t.Errorf("%s: unexpected OK() result", test.name)
+		}
+	}
+}
+
+type bucketS3Mock struct {
+	s3iface.S3API
+
+	encryption   *s3.PutBucketEncryptionInput
+	publicAccess *s3.PutPublicAccessBlockInput
+}
+
+func (m *bucketS3Mock) PutBucketEncryption(input *s3.PutBucketEncryptionInput) (*s3.PutBucketEncryptionOutput, error) {
+	m.encryption = input
+	return &s3.PutBucketEncryptionOutput{}, nil
+}
+
+func (m *bucketS3Mock) PutPublicAccessBlock(input *s3.PutPublicAccessBlockInput) (*s3.PutPublicAccessBlockOutput, error) {
+	m.publicAccess = input
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+func (m *bucketS3Mock) PutBucketVersioning(input *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (m *bucketS3Mock) PutBucketLifecycleConfiguration(input *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func TestConfigureBucket(t *testing.T) {
+	mock := &bucketS3Mock{}
+
+	config := &ecso.BucketConfiguration{KMSKeyID: "alias/ecso", NoncurrentVersionExpirationDays: 90}
+
+	if err := NewS3Helper(mock, "ap-southeast-2").ConfigureBucket("bucket", config, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := mock.encryption.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault
+
+	if aws.StringValue(rule.SSEAlgorithm) != s3.ServerSideEncryptionAwsKms || aws.StringValue(rule.KMSMasterKeyID) != "alias/ecso" {
+		t.Errorf("Unexpected encryption rule %s", rule)
+	}
+
+	if c := mock.publicAccess.PublicAccessBlockConfiguration; !aws.BoolValue(c.BlockPublicAcls) || !aws.BoolValue(c.RestrictPublicBuckets) {
+		t.Errorf("Want all public access to be blocked, got %s", c)
+	}
+}
@@ -0,0 +1,169 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// NewS3LockStore creates a LockStore that keeps each lock as a json object
+// under prefix in an S3 bucket. S3 conditional writes ensure that only one
+// process can create a lock, and that a lock is only changed by the process
+// that last read it
+func NewS3LockStore(s3API s3iface.S3API, region, bucket, prefix string) LockStore {
+	return &s3LockStore{
+		s3API:  s3API,
+		s3:     NewS3Helper(s3API, region),
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+type s3LockStore struct {
+	s3API  s3iface.S3API
+	s3     S3Helper
+	bucket string
+	prefix string
+}
+
+func (s *s3LockStore) key(name string) string {
+	return path.Join(s.prefix, name+".json")
+}
+
+// put writes a lock, with a conditional header that must hold for the write
+// to succeed
+func (s *s3LockStore) put(l *Lock, header, value string) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	req, out := s.s3API.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key(l.Name)),
+		Body:        bytes.NewReader(b),
+		ContentType: aws.String("application/json"),
+	})
+
+	req.HTTPRequest.Header.Set(header, value)
+
+	if err := req.Send(); err != nil {
+		return err
+	}
+
+	l.ETag = aws.StringValue(out.ETag)
+
+	return nil
+}
+
+func (s *s3LockStore) Create(l *Lock) error {
+	err := s.put(l, "If-None-Match", "*")
+	if isConditionFailed(err) {
+		return ErrLockExists
+	}
+
+	return err
+}
+
+func (s *s3LockStore) Get(name string) (*Lock, error) {
+	resp, err := s.s3API.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+
+	if isAWSErrorCode(err, s3.ErrCodeNoSuchKey) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	l := &Lock{}
+
+	if err := json.NewDecoder(resp.Body).Decode(l); err != nil {
+		return nil, err
+	}
+
+	l.ETag = aws.StringValue(resp.ETag)
+
+	return l, nil
+}
+
+func (s *s3LockStore) Update(l *Lock) error {
+	err := s.put(l, "If-Match", l.ETag)
+	if isConditionFailed(err) || isAWSErrorCode(err, s3.ErrCodeNoSuchKey) {
+		return ErrLockChanged
+	}
+
+	return err
+}
+
+// Delete is not bound to the command's context, so that locks are released
+// when a command is interrupted
+func (s *s3LockStore) Delete(l *Lock) error {
+	req, _ := s.s3API.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(l.Name)),
+	})
+
+	if l.ETag != "" {
+		req.HTTPRequest.Header.Set("If-Match", l.ETag)
+	}
+
+	err := req.Send()
+	if isConditionFailed(err) || isAWSErrorCode(err, s3.ErrCodeNoSuchKey) {
+		return ErrLockChanged
+	}
+
+	return err
+}
+
+// List returns all of the locks in the store. Like the store's other methods,
+// it does not take a context, as locks must still be released once a command
+// has been cancelled
+func (s *s3LockStore) List() ([]*Lock, error) {
+	objects, err := s.s3.ListObjects(context.Background(), s.bucket, s.prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]*Lock, 0)
+
+	for _, o := range objects {
+		name := strings.TrimSuffix(strings.TrimPrefix(aws.StringValue(o.Key), s.prefix+"/"), ".json")
+
+		l, err := s.Get(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// The lock may have been released since the bucket was listed
+		if l != nil {
+			locks = append(locks, l)
+		}
+	}
+
+	return locks, nil
+}
+
+// isConditionFailed returns true if a conditional s3 request failed because
+// its condition did not hold, or because another conditional request for the
+// same object was in progress
+func isConditionFailed(err error) bool {
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		return reqErr.StatusCode() == http.StatusPreconditionFailed || reqErr.StatusCode() == http.StatusConflict
+	}
+
+	return false
+}
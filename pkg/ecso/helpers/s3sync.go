@@ -0,0 +1,307 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	// defaultSyncConcurrency is the number of objects uploaded at once if
+	// SyncOptions.Concurrency is not set
+	defaultSyncConcurrency = 8
+
+	// syncHashMetadataKey is the user metadata that the md5 of an object's
+	// content is stored in. Objects uploaded in multiple parts or encrypted
+	// with KMS have an ETag that is not the md5 of their content, so this is
+	// used instead
+	syncHashMetadataKey = "Ecso-Md5"
+)
+
+// SyncOptions control how objects are synced to S3
+type SyncOptions struct {
+	// Delete removes objects under the synced prefix that have no local
+	// file. It is only used by SyncDir
+	Delete bool
+
+	// Concurrency is the number of objects uploaded at once
+	Concurrency int
+}
+
+// SyncObject is the content to be stored at a key in S3
+type SyncObject struct {
+	Key  string
+	Body []byte
+
+	// Source describes where the content came from, for logging
+	Source string
+}
+
+// SyncResult lists the keys affected by a sync
+type SyncResult struct {
+	Uploaded  []string
+	Unchanged []string
+	Deleted   []string
+}
+
+func (r *SyncResult) String() string {
+	return fmt.Sprintf("%d uploaded, %d unchanged, %d deleted", len(r.Uploaded), len(r.Unchanged), len(r.Deleted))
+}
+
+// remoteObject is the state of an object already in S3
+type remoteObject struct {
+	etag string
+	hash string
+}
+
+// matches returns true if the remote object has the same content as body
+func (o *remoteObject) matches(hash string) bool {
+	if o == nil {
+		return false
+	}
+
+	if o.hash != "" {
+		return o.hash == hash
+	}
+
+	return strings.Trim(o.etag, `"`) == hash
+}
+
+// SyncDir uploads each file in dir that is missing or different in S3 to the
+// same relative path under prefix. If options.Delete is set, objects under
+// prefix that have no file in dir are deleted
+func (h *s3Helper) SyncDir(ctx context.Context, dir, bucket, prefix string, options *SyncOptions, w io.Writer) (*SyncResult, error) {
+	objects := make([]*SyncObject, 0)
+
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		body, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, &SyncObject{
+			Key:    path.Join(prefix, filepath.ToSlash(rel)),
+			Body:   body,
+			Source: file,
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.requireBucket(ctx, bucket); err != nil {
+		return nil, err
+	}
+
+	listed, err := h.ListObjects(ctx, bucket, prefix+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	remote := make(map[string]*remoteObject)
+
+	for _, o := range listed {
+		remote[aws.StringValue(o.Key)] = &remoteObject{etag: aws.StringValue(o.ETag)}
+	}
+
+	result, err := h.sync(ctx, objects, bucket, options, func(key, hash string) (*remoteObject, error) {
+		o, ok := remote[key]
+		if !ok || o.matches(hash) {
+			return o, nil
+		}
+
+		// The listed ETag may not be the md5 of the content, so the hash in
+		// the object's metadata is checked before uploading it again
+		return h.headObject(ctx, bucket, key)
+	}, w)
+
+	if err != nil || options == nil || !options.Delete {
+		return result, err
+	}
+
+	var (
+		local   = make(map[string]bool)
+		orphans = make([]string, 0)
+	)
+
+	for _, o := range objects {
+		local[o.Key] = true
+	}
+
+	for key := range remote {
+		if !local[key] {
+			orphans = append(orphans, key)
+		}
+	}
+
+	sort.Strings(orphans)
+
+	for _, key := range orphans {
+		fmt.Fprintf(w, "Deleting 's3://%s/%s'\n", bucket, key)
+
+		if _, err := h.s3Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return result, err
+		}
+
+		result.Deleted = append(result.Deleted, key)
+	}
+
+	return result, nil
+}
+
+// Sync uploads each of the objects that is missing or different in S3
+func (h *s3Helper) Sync(ctx context.Context, objects []*SyncObject, bucket string, options *SyncOptions, w io.Writer) (*SyncResult, error) {
+	if err := h.requireBucket(ctx, bucket); err != nil {
+		return nil, err
+	}
+
+	return h.sync(ctx, objects, bucket, options, func(key, hash string) (*remoteObject, error) {
+		return h.headObject(ctx, bucket, key)
+	}, w)
+}
+
+func (h *s3Helper) sync(ctx context.Context, objects []*SyncObject, bucket string, options *SyncOptions, remote func(key, hash string) (*remoteObject, error), w io.Writer) (*SyncResult, error) {
+	concurrency := defaultSyncConcurrency
+
+	if options != nil && options.Concurrency > 0 {
+		concurrency = options.Concurrency
+	}
+
+	var (
+		uploader = s3manager.NewUploaderWithClient(h.s3Client)
+		result   = &SyncResult{}
+		queue    = make(chan *SyncObject)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	worker := func() {
+		defer wg.Done()
+
+		for o := range queue {
+			uploaded, err := h.syncObject(ctx, uploader, o, bucket, remote)
+
+			mu.Lock()
+
+			switch {
+			case err != nil:
+				if firstErr == nil {
+					firstErr = fmt.Errorf("Failed to upload '%s' to 's3://%s/%s'. %s", o.Source, bucket, o.Key, err.Error())
+				}
+			case uploaded:
+				fmt.Fprintf(w, "Uploaded '%s' to 's3://%s/%s'\n", o.Source, bucket, o.Key)
+				result.Uploaded = append(result.Uploaded, o.Key)
+			default:
+				result.Unchanged = append(result.Unchanged, o.Key)
+			}
+
+			mu.Unlock()
+		}
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	for _, o := range objects {
+		queue <- o
+	}
+
+	close(queue)
+	wg.Wait()
+
+	sort.Strings(result.Uploaded)
+	sort.Strings(result.Unchanged)
+
+	return result, firstErr
+}
+
+// syncObject uploads o unless it is unchanged, and returns true if it was
+// uploaded
+func (h *s3Helper) syncObject(ctx context.Context, uploader *s3manager.Uploader, o *SyncObject, bucket string, remote func(key, hash string) (*remoteObject, error)) (bool, error) {
+	sum := md5.Sum(o.Body)
+	hash := hex.EncodeToString(sum[:])
+
+	existing, err := remote(o.Key, hash)
+	if err != nil {
+		return false, err
+	}
+
+	if existing.matches(hash) {
+		return false, nil
+	}
+
+	_, err = uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(o.Key),
+		Body:   bytes.NewReader(o.Body),
+		Metadata: map[string]*string{
+			syncHashMetadataKey: aws.String(hash),
+		},
+	})
+
+	return err == nil, err
+}
+
+// headObject returns the state of the object at key, or nil if there is no
+// such object
+func (h *s3Helper) headObject(ctx context.Context, bucket, key string) (*remoteObject, error) {
+	resp, err := h.s3Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFound" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	o := &remoteObject{etag: aws.StringValue(resp.ETag)}
+
+	for k, v := range resp.Metadata {
+		if strings.EqualFold(k, syncHashMetadataKey) {
+			o.hash = aws.StringValue(v)
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,180 @@
+package helpers
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type syncS3Mock struct {
+	s3iface.S3API
+
+	objects       map[string]string
+	metadata      map[string]map[string]*string
+	deleted       []string
+	missingBucket bool
+}
+
+func (m *syncS3Mock) HeadBucketWithContext(aws.Context, *s3.HeadBucketInput, ...request.Option) (*s3.HeadBucketOutput, error) {
+	if m.missingBucket {
+		return nil, awserr.New("NotFound", "not found", nil)
+	}
+
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (m *syncS3Mock) ListObjectsPagesWithContext(ctx aws.Context, input *s3.ListObjectsInput, fn func(*s3.ListObjectsOutput, bool) bool, opts ...request.Option) error {
+	page := &s3.ListObjectsOutput{}
+
+	for key, etag := range m.objects {
+		page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key), ETag: aws.String(etag)})
+	}
+
+	fn(page, true)
+
+	return nil
+}
+
+func (m *syncS3Mock) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	etag, ok := m.objects[*input.Key]
+	if !ok {
+		return nil, awserr.New("NotFound", "not found", nil)
+	}
+
+	return &s3.HeadObjectOutput{ETag: aws.String(etag), Metadata: m.metadata[*input.Key]}, nil
+}
+
+func (m *syncS3Mock) DeleteObjectWithContext(ctx aws.Context, input *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	m.deleted = append(m.deleted, *input.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func etag(body string) string {
+	sum := md5.Sum([]byte(body))
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:]))
+}
+
+func TestSyncDirSkipsUnchangedAndDeletesOrphans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "lambda"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, body := range map[string]string{"a.txt": "a", "lambda/b.txt": "b"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mock := &syncS3Mock{
+		objects: map[string]string{
+			"prefix/a.txt":        etag("a"),
+			"prefix/lambda/b.txt": etag("b"),
+			"prefix/removed.txt":  etag("removed"),
+		},
+	}
+
+	h := &s3Helper{s3Client: mock, region: "test-region"}
+
+	result, err := h.SyncDir(context.Background(), dir, "bucket", "prefix", &SyncOptions{Delete: true}, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Uploaded) != 0 {
+		t.Errorf("Expected no uploads, got %v", result.Uploaded)
+	}
+
+	if want := []string{"prefix/a.txt", "prefix/lambda/b.txt"}; !reflect.DeepEqual(result.Unchanged, want) {
+		t.Errorf("Want unchanged %v, got %v", want, result.Unchanged)
+	}
+
+	if want := []string{"prefix/removed.txt"}; !reflect.DeepEqual(mock.deleted, want) || !reflect.DeepEqual(result.Deleted, want) {
+		t.Errorf("Want deleted %v, got %v", want, mock.deleted)
+	}
+}
+
+func TestSyncDirChecksHashOfObjectsWithOtherETags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := md5.Sum([]byte("a"))
+
+	// Objects encrypted with KMS have an ETag that is not the md5 of their
+	// content, even when uploaded in a single part
+	mock := &syncS3Mock{
+		objects: map[string]string{
+			"prefix/a.txt": `"0123456789abcdef0123456789abcdef"`,
+		},
+		metadata: map[string]map[string]*string{
+			"prefix/a.txt": {syncHashMetadataKey: aws.String(hex.EncodeToString(sum[:]))},
+		},
+	}
+
+	h := &s3Helper{s3Client: mock, region: "test-region"}
+
+	result, err := h.SyncDir(context.Background(), dir, "bucket", "prefix", nil, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"prefix/a.txt"}; !reflect.DeepEqual(result.Unchanged, want) || len(result.Uploaded) != 0 {
+		t.Errorf("Want unchanged %v, got %v, uploaded %v", want, result.Unchanged, result.Uploaded)
+	}
+}
+
+func TestRemoteObjectMatches(t *testing.T) {
+	sum := md5.Sum([]byte("body"))
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		object *remoteObject
+		want   bool
+	}{
+		{nil, false},
+		{&remoteObject{etag: etag("body")}, true},
+		{&remoteObject{etag: etag("other")}, false},
+		{&remoteObject{etag: `"abc-2"`, hash: hash}, true},
+		{&remoteObject{etag: `"abc-2"`}, false},
+	}
+
+	for i, test := range tests {
+		if got := test.object.matches(hash); got != test.want {
+			t.Errorf("%d: want %t, got %t", i, test.want, got)
+		}
+	}
+}
+
+func TestSyncRequiresBucket(t *testing.T) {
+	h := &s3Helper{s3Client: &syncS3Mock{missingBucket: true}, region: "test-region"}
+
+	_, err := h.Sync(context.Background(), []*SyncObject{{Key: "a.txt", Body: []byte("a")}}, "bucket", nil, ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "ecso environment up") {
+		t.Errorf("Want an error asking for the environment to be created, got %v", err)
+	}
+}
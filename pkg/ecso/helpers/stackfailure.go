@@ -0,0 +1,293 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// StackFailure describes the event that caused a stack operation to fail
+type StackFailure struct {
+	StackName    string
+	LogicalID    string
+	PhysicalID   string
+	ResourceType string
+	Status       string
+	Reason       string
+	Timestamp    time.Time
+	Hint         string
+}
+
+func (f *StackFailure) Error() string {
+	return fmt.Sprintf("%s (%s) in stack %s failed with %s. %s", f.LogicalID, f.ResourceType, f.StackName, f.Status, f.Reason)
+}
+
+func (f *StackFailure) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "Stack:    %s\n", f.StackName)
+	fmt.Fprintf(&buf, "Resource: %s (%s)\n", f.LogicalID, f.ResourceType)
+	fmt.Fprintf(&buf, "Status:   %s\n", f.Status)
+	fmt.Fprintf(&buf, "Reason:   %s\n", f.Reason)
+
+	if f.Hint != "" {
+		fmt.Fprintf(&buf, "Hint:     %s\n", f.Hint)
+	}
+
+	n, err := w.Write(buf.Bytes())
+
+	return int64(n), err
+}
+
+type failureHint struct {
+	pattern *regexp.Regexp
+	hint    string
+}
+
+// failureHints map the reasons given for well known failures to advice on
+// how to fix them
+var failureHints = []failureHint{
+	{
+		regexp.MustCompile(`(?i)priority '?\d+'? is currently in use`),
+		"Another listener rule on the load balancer already uses this priority. Give the service a different route priority, or remove the conflicting rule",
+	},
+	{
+		regexp.MustCompile(`(?i)(must (be|have) (no more than|at most|length less than or equal to) \d+|length (must be )?less than or equal to \d+|cannot be longer than \d+|too long)`),
+		"A name derived from the project, environment or service name is too long. Shorten the name, or set an explicit shorter name in the template",
+	},
+	{
+		regexp.MustCompile(`(?i)already exists`),
+		"A resource with the same name already exists outside of this stack. Delete or rename the existing resource, or choose a different name",
+	},
+	{
+		regexp.MustCompile(`(?i)(is not authorized to perform|access ?denied)`),
+		"The AWS credentials used to deploy do not have a permission that the stack requires",
+	},
+	{
+		regexp.MustCompile(`(?i)(limit exceeded|LimitExceeded|maximum number)`),
+		"An AWS account limit has been reached. Remove unused resources, or request a limit increase",
+	},
+	{
+		regexp.MustCompile(`(?i)did not stabilize`),
+		"The service's tasks did not become stable. Check `ecso service events` and `ecso service logs` for containers that failed to start or failed health checks",
+	},
+	{
+		regexp.MustCompile(`(?i)(rate exceeded|throttl)`),
+		"AWS API requests were throttled. Retry the deployment",
+	},
+	{
+		regexp.MustCompile(`(?i)(No export named|Unresolved resource dependencies|does not exist)`),
+		"The template refers to a resource, parameter or export that does not exist. Check that the environment is up to date with `ecso environment up`",
+	},
+}
+
+// hintForReason returns advice for a failure reason, or an empty string if
+// the failure is not a well known one
+func hintForReason(reason string) string {
+	for _, h := range failureHints {
+		if h.pattern.MatchString(reason) {
+			return h.hint
+		}
+	}
+
+	return ""
+}
+
+// DiagnoseFailure finds the event that caused the most recent operation on
+// a stack to fail, following failures of nested stacks down to the resource
+// that actually failed. It returns nil if no failed event could be found
+func (h *cfnHelper) DiagnoseFailure(stackName string) (*StackFailure, error) {
+	events, err := h.getLatestOperationEvents(stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	event := findRootCauseEvent(events)
+	if event == nil {
+		return nil, nil
+	}
+
+	if aws.StringValue(event.ResourceType) == "AWS::CloudFormation::Stack" && aws.StringValue(event.PhysicalResourceId) != "" {
+		nested, err := h.DiagnoseFailure(aws.StringValue(event.PhysicalResourceId))
+		if err == nil && nested != nil {
+			return nested, nil
+		}
+	}
+
+	reason := aws.StringValue(event.ResourceStatusReason)
+
+	return &StackFailure{
+		StackName:    aws.StringValue(event.StackName),
+		LogicalID:    aws.StringValue(event.LogicalResourceId),
+		PhysicalID:   aws.StringValue(event.PhysicalResourceId),
+		ResourceType: aws.StringValue(event.ResourceType),
+		Status:       aws.StringValue(event.ResourceStatus),
+		Reason:       reason,
+		Timestamp:    aws.TimeValue(event.Timestamp),
+		Hint:         hintForReason(reason),
+	}, nil
+}
+
+// getLatestOperationEvents returns the events of the most recent create,
+// update or delete of a stack, oldest first
+func (h *cfnHelper) getLatestOperationEvents(stackName string) ([]*cloudformation.StackEvent, error) {
+	events := make([]*cloudformation.StackEvent, 0)
+
+	params := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
+	}
+
+	err := h.cfnClient.DescribeStackEventsPages(params, func(page *cloudformation.DescribeStackEventsOutput, lastPage bool) bool {
+		for _, event := range page.StackEvents {
+			events = append(events, event)
+
+			if isOperationStartEvent(event) {
+				return false
+			}
+		}
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	return events, nil
+}
+
+// isOperationStartEvent returns true if the event marks the start of a
+// create, update or delete of the stack itself
+func isOperationStartEvent(event *cloudformation.StackEvent) bool {
+	if aws.StringValue(event.PhysicalResourceId) != aws.StringValue(event.StackId) {
+		return false
+	}
+
+	switch aws.StringValue(event.ResourceStatus) {
+	case cloudformation.ResourceStatusCreateInProgress, cloudformation.ResourceStatusUpdateInProgress, cloudformation.ResourceStatusDeleteInProgress:
+		return true
+	default:
+		return false
+	}
+}
+
+// findRootCauseEvent returns the first resource that failed in a list of
+// events, ignoring resources that failed only because the operation was
+// cancelled after another resource failed
+func findRootCauseEvent(events []*cloudformation.StackEvent) *cloudformation.StackEvent {
+	var fallback *cloudformation.StackEvent
+
+	for _, event := range events {
+		if !strings.HasSuffix(aws.StringValue(event.ResourceStatus), "_FAILED") {
+			continue
+		}
+
+		if aws.StringValue(event.PhysicalResourceId) == aws.StringValue(event.StackId) {
+			if fallback == nil {
+				fallback = event
+			}
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(aws.StringValue(event.ResourceStatusReason)), "cancelled") {
+			continue
+		}
+
+		return event
+	}
+
+	return fallback
+}
+
+// formatStackEvent formats a stack event for logging, including the reason
+// for failed events
+func formatStackEvent(event *cloudformation.StackEvent) string {
+	line := fmt.Sprintf("%s: %s", aws.StringValue(event.LogicalResourceId), aws.StringValue(event.ResourceStatus))
+
+	if strings.HasSuffix(aws.StringValue(event.ResourceStatus), "_FAILED") && event.ResourceStatusReason != nil {
+		line = fmt.Sprintf("%s (%s)", line, aws.StringValue(event.ResourceStatusReason))
+	}
+
+	return line
+}
+
+// waitForStack waits for a stack operation to complete. If the operation
+// fails, the root cause of the failure is printed and returned as the error
+func (h *cfnHelper) waitForStack(ctx context.Context, stackName, status string, w io.Writer) error {
+	err := h.waitForStackStatus(ctx, stackName, status)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	failure, diagnoseErr := h.DiagnoseFailure(stackName)
+	if diagnoseErr != nil || failure == nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nThe stack operation failed because:\n\n")
+	failure.WriteTo(w)
+	fmt.Fprintf(w, "\n")
+
+	return failure
+}
+
+// waitForStackStatus polls a stack until the operation in progress on it
+// completes, returning an error if it finishes in any status other than
+// the one wanted
+func (h *cfnHelper) waitForStackStatus(ctx context.Context, stackName, status string) error {
+	params := &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	}
+
+	for {
+		resp, err := h.cfnClient.DescribeStacksWithContext(ctx, params)
+		if err != nil {
+			if status == cloudformation.StackStatusDeleteComplete && strings.Contains(err.Error(), "does not exist") {
+				return nil
+			}
+			return err
+		}
+
+		if len(resp.Stacks) == 0 {
+			return fmt.Errorf("Stack %s was not found", stackName)
+		}
+
+		current := aws.StringValue(resp.Stacks[0].StackStatus)
+
+		if current == status {
+			return nil
+		}
+
+		if !strings.HasSuffix(current, "_IN_PROGRESS") {
+			return fmt.Errorf("Stack %s finished with status %s. %s", stackName, current, aws.StringValue(resp.Stacks[0].StackStatusReason))
+		}
+
+		if err := sleepWithContext(ctx, time.Second*5); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepWithContext pauses for d, returning early with the context's error if
+// ctx is done first
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/bernos/ecso/pkg/ecso/api/mocks"
+)
+
+func stackEvent(stackID, logicalID, physicalID, resourceType, status, reason string) *cloudformation.StackEvent {
+	return &cloudformation.StackEvent{
+		StackId:              aws.String(stackID),
+		StackName:            aws.String(stackID),
+		LogicalResourceId:    aws.String(logicalID),
+		PhysicalResourceId:   aws.String(physicalID),
+		ResourceType:         aws.String(resourceType),
+		ResourceStatus:       aws.String(status),
+		ResourceStatusReason: aws.String(reason),
+	}
+}
+
+func TestDiagnoseFailureFollowsNestedStacks(t *testing.T) {
+	mock := &mocks.CloudFormationAPIMock{}
+
+	// Events are returned newest first
+	mock.DescribeStackEventsReturns("root", &cloudformation.DescribeStackEventsOutput{
+		StackEvents: []*cloudformation.StackEvent{
+			stackEvent("root", "root", "root", "AWS::CloudFormation::Stack", "UPDATE_ROLLBACK_IN_PROGRESS", "The following resource(s) failed to update: [ALB]"),
+			stackEvent("root", "Alarms", "alarms", "AWS::CloudFormation::Stack", "UPDATE_FAILED", "Resource update cancelled"),
+			stackEvent("root", "ALB", "alb", "AWS::CloudFormation::Stack", "UPDATE_FAILED", "Embedded stack alb was not successfully updated"),
+			stackEvent("root", "ALB", "alb", "AWS::CloudFormation::Stack", "UPDATE_IN_PROGRESS", ""),
+			stackEvent("root", "root", "root", "AWS::CloudFormation::Stack", "UPDATE_IN_PROGRESS", "User Initiated"),
+			stackEvent("root", "Old", "old", "AWS::SNS::Topic", "CREATE_FAILED", "From a previous deployment"),
+		},
+	})
+
+	mock.DescribeStackEventsReturns("alb", &cloudformation.DescribeStackEventsOutput{
+		StackEvents: []*cloudformation.StackEvent{
+			stackEvent("alb", "Rule", "", "AWS::ElasticLoadBalancingV2::ListenerRule", "CREATE_FAILED", "Priority '10' is currently in use"),
+			stackEvent("alb", "ALB", "alb", "AWS::CloudFormation::Stack", "UPDATE_IN_PROGRESS", ""),
+		},
+	})
+
+	helper := newCloudFormationHelperWithMocks()
+	helper.cfnClient = mock
+
+	failure, err := helper.DiagnoseFailure("root")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if failure == nil {
+		t.Fatalf("Want a failure")
+	}
+
+	if failure.LogicalID != "Rule" || failure.StackName != "alb" {
+		t.Errorf("Want failure of Rule in stack alb, got %s in %s", failure.LogicalID, failure.StackName)
+	}
+
+	if failure.Hint == "" {
+		t.Errorf("Want a hint for a listener rule priority conflict")
+	}
+}
+
+func TestFindRootCauseEventIgnoresCancellations(t *testing.T) {
+	events := []*cloudformation.StackEvent{
+		stackEvent("s", "A", "a", "AWS::SNS::Topic", "UPDATE_FAILED", "Resource update cancelled"),
+		stackEvent("s", "B", "b", "AWS::IAM::Role", "UPDATE_FAILED", "Role name must have length less than or equal to 64"),
+	}
+
+	event := findRootCauseEvent(events)
+
+	if event == nil || aws.StringValue(event.LogicalResourceId) != "B" {
+		t.Errorf("Want root cause B, got %v", event)
+	}
+}
+
+func TestHintForReason(t *testing.T) {
+	cases := map[string]bool{
+		"Priority '10' is currently in use": true,
+		"1 validation error detected: Value at 'name' failed to satisfy constraint: Member must have length less than or equal to 32": true,
+		"my-bucket already exists":      true,
+		"Something unexpected happened": false,
+	}
+
+	for reason, want := range cases {
+		if got := hintForReason(reason) != ""; got != want {
+			t.Errorf("Want hint %t for reason '%s', got %t", want, reason, got)
+		}
+	}
+}
+
+func TestSleepWithContext(t *testing.T) {
+	if err := sleepWithContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+
+	if err := sleepWithContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Want %v, got %v", context.Canceled, err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected cancelled sleep to return immediately")
+	}
+}
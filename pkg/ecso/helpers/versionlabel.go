@@ -0,0 +1,154 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bernos/ecso/pkg/ecso/util"
+)
+
+const (
+	// VersionStrategyTimestamp labels versions with the time of deployment
+	VersionStrategyTimestamp = "timestamp"
+
+	// VersionStrategyGitSHA labels versions with the short SHA of the git
+	// HEAD commit
+	VersionStrategyGitSHA = "git-sha"
+
+	// VersionStrategyGitDescribe labels versions with the output of
+	// `git describe --tags --always --dirty`
+	VersionStrategyGitDescribe = "git-describe"
+)
+
+var versionLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// VersionLabelData holds the values available to a version label template
+type VersionLabelData struct {
+	Timestamp   string
+	GitSHA      string
+	GitDescribe string
+	GitBranch   string
+}
+
+// NewVersionLabel creates a version label according to strategy. strategy
+// is either one of the VersionStrategy constants, or a text/template that
+// is executed against VersionLabelData, such as "{{.GitDescribe}}-{{.Timestamp}}".
+// An empty strategy defaults to VersionStrategyTimestamp. Git based strategies
+// read from the git repository at dir
+func NewVersionLabel(strategy, dir string, t time.Time) (string, error) {
+	var label string
+
+	switch {
+	case strategy == "" || strategy == VersionStrategyTimestamp:
+		label = util.VersionFromTime(t)
+
+	case strategy == VersionStrategyGitSHA:
+		sha, err := gitShortSHA(dir)
+		if err != nil {
+			return "", err
+		}
+
+		label = sha
+
+	case strategy == VersionStrategyGitDescribe:
+		description, err := gitDescribe(dir)
+		if err != nil {
+			return "", err
+		}
+
+		label = description
+
+	case strings.Contains(strategy, "{{"):
+		l, err := executeVersionTemplate(strategy, dir, t)
+		if err != nil {
+			return "", err
+		}
+
+		label = l
+
+	default:
+		return "", fmt.Errorf("Unknown versioning strategy '%s'", strategy)
+	}
+
+	return label, ValidateVersionLabel(label)
+}
+
+// ValidateVersionLabel checks that label can be used as both an S3 key
+// segment and a cloudformation tag value
+func ValidateVersionLabel(label string) error {
+	if len(label) > 128 {
+		return fmt.Errorf("Version label '%s' is longer than 128 characters", label)
+	}
+
+	if !versionLabelPattern.MatchString(label) {
+		return fmt.Errorf("Version label '%s' is invalid. Version labels must start with a letter or number, and contain only letters, numbers, '.', '_' and '-'", label)
+	}
+
+	return nil
+}
+
+func executeVersionTemplate(text, dir string, t time.Time) (string, error) {
+	tmpl, err := template.New("version").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("Invalid versioning strategy template. %s", err.Error())
+	}
+
+	data := &VersionLabelData{
+		Timestamp: util.VersionFromTime(t),
+	}
+
+	if strings.Contains(text, ".Git") {
+		if data.GitSHA, err = gitShortSHA(dir); err != nil {
+			return "", err
+		}
+
+		if data.GitDescribe, err = gitDescribe(dir); err != nil {
+			return "", err
+		}
+
+		if data.GitBranch, err = git(dir, "rev-parse", "--abbrev-ref", "HEAD"); err != nil {
+			return "", fmt.Errorf("Failed to read git branch. %s", err.Error())
+		}
+
+		data.GitBranch = strings.Replace(data.GitBranch, "/", "-", -1)
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func gitShortSHA(dir string) (string, error) {
+	sha, err := git(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("Failed to read git commit. Git based versioning strategies require a git repository with at least one commit. %s", err.Error())
+	}
+
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+
+	if status != "" {
+		sha = sha + "-dirty"
+	}
+
+	return sha, nil
+}
+
+func gitDescribe(dir string) (string, error) {
+	description, err := git(dir, "describe", "--tags", "--always", "--dirty")
+	if err != nil {
+		return "", fmt.Errorf("Failed to run git describe. Git based versioning strategies require a git repository with at least one commit. %s", err.Error())
+	}
+
+	return description, nil
+}
@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewVersionLabelTimestamp(t *testing.T) {
+	now := time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	for _, strategy := range []string{"", VersionStrategyTimestamp} {
+		label, err := NewVersionLabel(strategy, "", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if label != "2017-03-04_05-06-07" {
+			t.Errorf("Want 2017-03-04_05-06-07, got %s", label)
+		}
+	}
+}
+
+func TestNewVersionLabelGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "ecso-version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed. %s", args, out)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "file"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run("add", "file")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "v1.0.0")
+
+	now := time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	cases := []struct {
+		strategy string
+		want     string
+	}{
+		{VersionStrategyGitSHA, `^[0-9a-f]{7,}$`},
+		{VersionStrategyGitDescribe, `^v1\.0\.0$`},
+		{"{{.GitDescribe}}-{{.Timestamp}}", `^v1\.0\.0-2017-03-04_05-06-07$`},
+	}
+
+	for _, c := range cases {
+		label, err := NewVersionLabel(c.strategy, dir, now)
+		if err != nil {
+			t.Fatalf("%s: %s", c.strategy, err)
+		}
+
+		if !regexp.MustCompile(c.want).MatchString(label) {
+			t.Errorf("%s: want label matching %s, got %s", c.strategy, c.want, label)
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "file"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	label, err := NewVersionLabel(VersionStrategyGitSHA, dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !regexp.MustCompile(`-dirty$`).MatchString(label) {
+		t.Errorf("Want dirty label, got %s", label)
+	}
+}
+
+func TestNewVersionLabelUnknownStrategy(t *testing.T) {
+	if _, err := NewVersionLabel("semver", "", time.Now()); err == nil {
+		t.Error("Want error for unknown strategy")
+	}
+}
+
+func TestValidateVersionLabel(t *testing.T) {
+	for _, label := range []string{"v1.2.3", "2017-03-04_05-06-07", "abc123-dirty"} {
+		if err := ValidateVersionLabel(label); err != nil {
+			t.Errorf("Want %s to be valid, got %s", label, err)
+		}
+	}
+
+	for _, label := range []string{"", "feature/foo", "-v1", "v 1", "../v1", "1.0.0+build.5"} {
+		if err := ValidateVersionLabel(label); err == nil {
+			t.Errorf("Want %s to be invalid", label)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ecso
+
+import "os"
+
+const (
+	// NotifierTypeSNS publishes deployment events to an SNS topic
+	NotifierTypeSNS = "sns"
+
+	// NotifierTypeWebhook POSTs deployment events as JSON to a url
+	NotifierTypeWebhook = "webhook"
+
+	// NotifierTypeSlack posts deployment events to a Slack compatible
+	// incoming webhook
+	NotifierTypeSlack = "slack"
+)
+
+// NotifierConfiguration configures somewhere that an environment's
+// deployment events are sent. If an environment has no notifiers, events are
+// published to the NotificationsTopic output of the environment stack
+type NotifierConfiguration struct {
+	// Type is one of "sns", "webhook" or "slack"
+	Type string
+
+	// URL is the url of a webhook or slack notifier. Environment variables
+	// in the url are expanded, so that secrets can be kept out of the
+	// project file
+	URL string `json:",omitempty"`
+
+	// TopicArn is the topic of an sns notifier. If empty, the environment's
+	// NotificationsTopic is used
+	TopicArn string `json:",omitempty"`
+
+	// Headers are added to the requests of a webhook notifier. Environment
+	// variables in header values are expanded
+	Headers map[string]string `json:",omitempty"`
+
+	// Events limits the notifier to the given event types ("started",
+	// "succeeded" or "failed"). If empty, all events are sent
+	Events []string `json:",omitempty"`
+}
+
+// GetURL returns the notifier's url, with environment variables expanded
+func (n *NotifierConfiguration) GetURL() string {
+	return os.ExpandEnv(n.URL)
+}
+
+// GetHeaders returns the notifier's headers, with environment variables
+// expanded
+func (n *NotifierConfiguration) GetHeaders() map[string]string {
+	headers := make(map[string]string)
+
+	for k, v := range n.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+
+	return headers
+}
+
+// Accepts returns true if events of the given type should be sent to the
+// notifier
+func (n *NotifierConfiguration) Accepts(eventType string) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+
+	for _, e := range n.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -13,6 +13,13 @@ const (
 	projectFilename = "project.json"
 )
 
+var (
+	// GeneratedFilesDir is the path relative to the project dir that
+	// pristine copies of the files generated by ecso are stored, so that
+	// edited files can be upgraded by merging
+	GeneratedFilesDir = filepath.Join(ecsoDotDir, ".generated")
+)
+
 // LoadCurrentProject loads the current ecso project from the project.json
 // file located at the dir given by GetCurrentProjectDir()
 func LoadCurrentProject() (*Project, error) {
@@ -76,12 +83,50 @@ type Project struct {
 	EcsoVersion  string
 	Environments map[string]*Environment
 	Services     map[string]*Service
+
+	RetentionPolicy *RetentionPolicy `json:",omitempty"`
+
+	// Bucket configures the S3 bucket used by all environments. See
+	// Environment.GetBucketConfiguration
+	Bucket *BucketConfiguration `json:",omitempty"`
+
+	// VersionStrategy determines how service version labels are generated
+	// when no label is given to `ecso service up`. See
+	// helpers.NewVersionLabel for the supported strategies
+	VersionStrategy string `json:",omitempty"`
+
+	// TemplatePath lists directories containing service template packs,
+	// which can be used by name with `ecso service add --template`.
+	// Relative paths are relative to the project dir
+	TemplatePath []string `json:",omitempty"`
+}
+
+// RetentionPolicy controls how many deployment package versions ecso keeps
+// in the ecso bucket for each service and environment. When set, old
+// versions are pruned after each successful deployment
+type RetentionPolicy struct {
+	Keep int
 }
 
 func (p *Project) Dir() string {
 	return p.dir
 }
 
+// TemplateSearchPath returns the absolute paths of the project's
+// TemplatePath directories
+func (p *Project) TemplateSearchPath() []string {
+	dirs := make([]string, 0, len(p.TemplatePath))
+
+	for _, dir := range p.TemplatePath {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(p.Dir(), dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
 func (p *Project) DotDir() string {
 	return filepath.Join(p.Dir(), ecsoDotDir)
 }
@@ -0,0 +1,139 @@
+package resources
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/bernos/ecso/pkg/ecso"
+)
+
+var (
+	// LambdaDir is the path relative to the project dir of the environment's
+	// lambda functions. Each dir within it is the source of a single function
+	LambdaDir = filepath.Join(ecso.EnvironmentResourceDir, "lambda")
+
+	// lambdaHashLength is the number of hex characters of the content hash
+	// used in the name of a lambda bundle
+	lambdaHashLength = 16
+)
+
+// LambdaBundle is the zipped source of a lambda function, ready to be
+// uploaded to S3
+type LambdaBundle struct {
+	// Name is the name of the dir that the function was packaged from
+	Name string
+
+	// Filename is the name of the zip, which includes a hash of its
+	// content, so that the function is updated whenever its source changes
+	Filename string
+
+	Body []byte
+}
+
+// ParameterName is the name of the cloudformation parameter that the S3 key
+// of the bundle is passed to the environment stack as. The "dns-cleaner"
+// function's key is passed as "DnsCleanerLambdaS3Key"
+func (b *LambdaBundle) ParameterName() string {
+	words := strings.FieldsFunc(b.Name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return strings.Join(words, "") + "LambdaS3Key"
+}
+
+// PackageLambdas creates a bundle for each lambda function source dir in dir
+func PackageLambdas(dir string) ([]*LambdaBundle, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	bundles := make([]*LambdaBundle, 0)
+
+	for _, info := range infos {
+		if !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		bundle, err := PackageLambda(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, nil
+}
+
+// PackageLambda zips the files in dir. The zip is deterministic, so the
+// bundle's name only changes when the content of one of the files does
+func PackageLambda(dir string) (*LambdaBundle, error) {
+	entries := make([]*zipEntry, 0)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, &zipEntry{name: filepath.ToSlash(name), body: body})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("The lambda function in %s has no files", dir)
+	}
+
+	var buf bytes.Buffer
+
+	if err := writeZip(&buf, entries); err != nil {
+		return nil, err
+	}
+
+	var (
+		name = filepath.Base(dir)
+		hash = sha256.Sum256(buf.Bytes())
+	)
+
+	return &LambdaBundle{
+		Name:     name,
+		Filename: fmt.Sprintf("%s-%s.zip", name, hex.EncodeToString(hash[:])[:lambdaHashLength]),
+		Body:     buf.Bytes(),
+	}, nil
+}
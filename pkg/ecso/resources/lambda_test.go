@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageLambdas(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-lambda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestTemplatePack(t, dir, map[string]string{
+		"dns-cleaner/index.js":         "exports.handler = () => {};",
+		"log_shipper/index.py":         "def handler(event, context): pass",
+		"log_shipper/lib/util.py":      "",
+		"log_shipper/.cache/ignore.me": "",
+		"README.md":                    "",
+	})
+
+	bundles, err := PackageLambdas(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bundles) != 2 {
+		t.Fatalf("Expected 2 bundles, got %d", len(bundles))
+	}
+
+	for i, expect := range []string{"DnsCleanerLambdaS3Key", "LogShipperLambdaS3Key"} {
+		if bundles[i].ParameterName() != expect {
+			t.Errorf("Expected parameter name %s, got %s", expect, bundles[i].ParameterName())
+		}
+
+		if !strings.HasPrefix(bundles[i].Filename, bundles[i].Name+"-") || !strings.HasSuffix(bundles[i].Filename, ".zip") {
+			t.Errorf("Unexpected bundle filename %s", bundles[i].Filename)
+		}
+	}
+
+	// Packaging the same source later must give an identical bundle, but
+	// any change to the source must change the name
+	src := filepath.Join(dir, "dns-cleaner", "index.js")
+	later := time.Now().Add(time.Hour)
+
+	if err := os.Chtimes(src, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	same, err := PackageLambda(filepath.Join(dir, "dns-cleaner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if same.Filename != bundles[0].Filename || !bytes.Equal(same.Body, bundles[0].Body) {
+		t.Errorf("Expected bundle to be deterministic")
+	}
+
+	if err := ioutil.WriteFile(src, []byte("exports.handler = () => 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := PackageLambda(filepath.Join(dir, "dns-cleaner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed.Filename == bundles[0].Filename {
+		t.Errorf("Expected bundle name to change with its content")
+	}
+}
+
+func TestPackageLambdasMissingDir(t *testing.T) {
+	bundles, err := PackageLambdas(filepath.Join(os.TempDir(), "ecso-lambda-does-not-exist"))
+	if err != nil || len(bundles) != 0 {
+		t.Errorf("Expected no bundles and no error, got %v, %v", bundles, err)
+	}
+}
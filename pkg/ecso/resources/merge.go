@@ -0,0 +1,163 @@
+package resources
+
+import (
+	"strings"
+)
+
+const (
+	conflictStartMarker = "<<<<<<< current"
+	conflictBaseMarker  = "||||||| original"
+	conflictSepMarker   = "======="
+	conflictEndMarker   = ">>>>>>> upgrade"
+)
+
+// Merge3 performs a line based three-way merge of the changes made to base
+// by current and upgrade. Where both have changed the same lines differently,
+// the result contains both versions of the lines between conflict markers, in
+// the same style as `git merge` with the diff3 conflict style. The returned
+// bool is true if there were any conflicts
+func Merge3(base, current, upgrade string) (string, bool) {
+	var (
+		o = splitLines(base)
+		a = splitLines(current)
+		b = splitLines(upgrade)
+
+		matchA = matchLines(o, a)
+		matchB = matchLines(o, b)
+
+		out       = make([]string, 0, len(a))
+		conflicts = false
+
+		i, ia, ib = 0, 0, 0
+	)
+
+	for i < len(o) || ia < len(a) || ib < len(b) {
+		// Lines that are unchanged in both versions are copied as is
+		if i < len(o) && matchA[i] == ia && matchB[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+			continue
+		}
+
+		// Otherwise find the next line that is unchanged in both versions.
+		// Everything before it is a chunk that at least one version changed
+		j := i
+		for j < len(o) && (matchA[j] < 0 || matchB[j] < 0) {
+			j++
+		}
+
+		endA, endB := len(a), len(b)
+		if j < len(o) {
+			endA, endB = matchA[j], matchB[j]
+		}
+
+		var (
+			chunkO = o[i:j]
+			chunkA = a[ia:endA]
+			chunkB = b[ib:endB]
+		)
+
+		switch {
+		case equalLines(chunkA, chunkO):
+			out = append(out, chunkB...)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			out = append(out, chunkA...)
+		default:
+			conflicts = true
+			out = append(out, conflictStartMarker+"\n")
+			out = append(out, terminateLines(chunkA)...)
+			out = append(out, conflictBaseMarker+"\n")
+			out = append(out, terminateLines(chunkO)...)
+			out = append(out, conflictSepMarker+"\n")
+			out = append(out, terminateLines(chunkB)...)
+			out = append(out, conflictEndMarker+"\n")
+		}
+
+		i, ia, ib = j, endA, endB
+	}
+
+	return strings.Join(out, ""), conflicts
+}
+
+// splitLines splits s into lines, each of which keeps its trailing newline
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// terminateLines ensures the last line ends with a newline, so that conflict
+// markers always start on a new line
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+
+	terminated := append([]string{}, lines...)
+	terminated[len(terminated)-1] += "\n"
+
+	return terminated
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchLines finds the longest common subsequence of lines in o and x, and
+// returns the index in x of the line matched to each line of o, or -1 if the
+// line is not matched
+func matchLines(o, x []string) []int {
+	// lcs[i][j] is the length of the longest common subsequence of o[i:]
+	// and x[j:]
+	lcs := make([][]int, len(o)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(x)+1)
+	}
+
+	for i := len(o) - 1; i >= 0; i-- {
+		for j := len(x) - 1; j >= 0; j-- {
+			switch {
+			case o[i] == x[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	matches := make([]int, len(o))
+
+	i, j := 0, 0
+
+	for i < len(o) {
+		switch {
+		case j < len(x) && o[i] == x[j]:
+			matches[i] = j
+			i, j = i+1, j+1
+		case j < len(x) && lcs[i][j+1] > lcs[i+1][j]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+
+	tests := []struct {
+		name      string
+		current   string
+		upgrade   string
+		expect    string
+		conflicts bool
+	}{
+		{
+			name:    "no changes",
+			current: base,
+			upgrade: base,
+			expect:  base,
+		},
+		{
+			name:    "only current changed",
+			current: "a\nB\nc\nd\ne\n",
+			upgrade: base,
+			expect:  "a\nB\nc\nd\ne\n",
+		},
+		{
+			name:    "only upgrade changed",
+			current: base,
+			upgrade: "a\nb\nc\nD\ne\nf\n",
+			expect:  "a\nb\nc\nD\ne\nf\n",
+		},
+		{
+			name:    "separate changes",
+			current: "a\nB\nc\nd\ne\n",
+			upgrade: "a\nb\nc\nD\ne\n",
+			expect:  "a\nB\nc\nD\ne\n",
+		},
+		{
+			name:    "same change",
+			current: "a\nB\nc\nd\ne\n",
+			upgrade: "a\nB\nc\nd\ne\n",
+			expect:  "a\nB\nc\nd\ne\n",
+		},
+		{
+			name:      "overlapping changes",
+			current:   "a\nB1\nc\nd\ne\n",
+			upgrade:   "a\nB2\nc\nd\ne\n",
+			expect:    "a\n<<<<<<< current\nB1\n||||||| original\nb\n=======\nB2\n>>>>>>> upgrade\nc\nd\ne\n",
+			conflicts: true,
+		},
+		{
+			name:    "lines deleted and added",
+			current: "a\nc\nd\ne\n",
+			upgrade: "a\nb\nc\nd\ne\nf\n",
+			expect:  "a\nc\nd\ne\nf\n",
+		},
+	}
+
+	for _, test := range tests {
+		merged, conflicts := Merge3(base, test.current, test.upgrade)
+
+		if merged != test.expect {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expect, merged)
+		}
+
+		if conflicts != test.conflicts {
+			t.Errorf("%s: expected conflicts to be %t", test.name, test.conflicts)
+		}
+	}
+}
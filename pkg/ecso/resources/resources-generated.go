@@ -289,7 +289,7 @@ func environmentCloudformationSnsYaml() (*asset, error) {
 	return a, nil
 }
 
-var _environmentCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x59\x4d\x6f\xdb\x38\x13\xbe\xe7\x57\x4c\x8d\x1e\x6b\xe7\x45\x7b\x79\x21\x2c\x0a\x28\x76\xda\xcd\xd6\xf0\x1a\x51\xd2\x02\xbb\xd8\x03\x2d\x8d\x25\x22\x34\x29\x90\xa3\x74\xdd\xc0\xff\x7d\x41\x8a\x72\x24\x4b\xb2\x9d\xb4\x4e\x17\x58\xdd\x44\x0d\xf9\x3c\x9c\x2f\x0e\x47\x13\x34\xb1\xe6\x39\x71\x25\x03\x78\x7f\x06\x00\x70\x93\x71\x03\x84\xab\x5c\x30\x42\x48\x30\x17\x6a\x6d\x80\x41\xc6\xd3\x4c\xac\x81\xdd\x33\x2e\xd8\x42\x20\x5c\x8e\x23\x88\x45\x61\x08\x35\x14\x86\xcb\x14\x98\x84\xb0\x20\x15\xc5\x4c\xd8\xd7\x8f\x5a\x15\xf9\x1b\xf8\xca\x29\x73\x2b\xdb\x09\x99\x32\x64\x20\xe1\x86\x34\x5f\x14\x84\x09\xb0\x58\x2b\x63\x60\x55\x08\xe2\xb9\x40\x08\x4b\x00\x2e\x38\xad\xe1\x0f\x25\xd1\x8c\xce\xce\xe6\x4c\xb3\x15\x12\x6a\x13\x9c\xb9\xb5\xa2\x77\x17\x45\x7c\x87\x34\x63\x2b\x0c\xdc\x88\x7d\x1a\xdb\xb9\xc9\x10\x24\x5b\x21\xa8\x25\x50\x86\x10\xbd\x83\x85\x9b\x03\xa4\xa0\xc8\x85\x62\x09\x68\x34\xaa\xd0\x31\x1a\x20\xb5\x5d\xe5\x66\x9d\x63\x00\x11\x69\x2e\xd3\x0a\xed\x13\xae\xe7\x1a\x97\xfc\xef\x3d\x60\x39\xa3\x0c\xb8\xfc\x5e\xb0\xcf\xa8\x8d\x5d\xb3\x1f\x08\xe5\x3d\xd7\x4a\xae\x50\x12\xdc\x97\xd2\xfb\xd6\x9b\x8f\x7b\xd6\x1a\x67\x4a\x19\x84\xaf\x19\x8f\x33\x2b\x06\x64\x6d\x5f\xb7\xab\xc9\x54\x21\x12\x58\x54\x8e\x80\x49\x9b\x7b\xf8\x25\x0a\x82\xcb\xf1\xdb\x20\xb0\x48\xc1\x55\x52\xc2\x5e\x49\x43\x4c\xc6\x18\x15\x0b\x89\x64\x8e\xa1\x60\x4a\xd1\x36\x0d\xee\xd7\x32\x47\x11\x9a\x72\x43\xbf\x3c\xb2\x2a\x09\x58\x62\xef\x4b\x66\xe1\xf4\xe2\x59\xa4\x10\x58\x9e\x0b\x1e\x33\x2b\x05\xce\xa4\x0b\x26\x2c\xaf\xe3\x14\x75\x88\xd7\xb8\xdc\x6d\xc4\xbf\xf5\xb9\xb4\x27\x66\xa9\xc8\x62\xb5\x40\x6d\x7d\x3b\x56\x92\x18\x97\x0d\x35\x91\x02\x96\x58\x0a\x4e\xd6\xab\x71\x87\xce\xcc\xad\xd0\x34\x96\xfb\x72\x10\x9b\xd6\xb9\x8b\xaa\xcb\xf1\xdb\x2d\xe6\x51\x90\xde\x2d\x1f\xd7\x5f\xb2\x42\x50\x00\xf4\x76\x24\x98\x4e\xb1\x24\x33\x99\x45\x36\xee\x7b\x78\x44\x28\x30\x26\x07\x32\x99\x45\xf0\x4d\x49\x87\x5d\x18\x84\xa5\xd2\x60\x50\xdf\xf3\x18\x6d\x8a\x89\xd5\x3d\xea\xf5\x9e\xc8\x98\x30\x62\x13\x95\x86\xf3\xab\x4f\xb8\xee\x81\x9b\x0b\x64\x06\x21\xd7\xea\x9e\x27\x08\x6b\x55\x68\x48\x18\xb1\x44\xa5\x10\xce\xaf\xe0\x0e\x7b\x10\x5a\x9b\x1c\x0c\x4a\x54\x9b\x4a\x18\xd7\x7b\xe2\xfb\x0e\xd7\x39\xe3\xba\xa6\x52\x64\x71\xd6\x54\xb7\xcf\x33\x7b\xf5\xec\x46\xe7\x2c\x45\x3d\x29\x68\x7d\x29\x93\x5c\x71\x49\x7b\x80\x0b\x2d\xac\x5d\xb7\x53\xb6\xda\x44\x3f\xd7\x72\x91\x8a\xf8\xf2\x29\x9b\xbe\xae\xf2\x5e\x89\x1c\x0a\xa6\x57\xb5\xd0\xab\x65\x91\xb1\x50\x45\xf2\x41\xe9\x95\x0b\xb1\x20\x88\x88\xc5\x77\x5b\xc1\xb9\x56\x39\x6a\xe2\x58\x9b\xec\x16\xf0\x87\xd5\xed\xf5\x34\x80\xd1\x39\x73\xeb\x8f\xd6\x6c\x25\x1a\x62\xf5\x33\x04\x76\x9e\xcb\xc7\x8c\xea\x4e\x14\x78\x75\x8d\xcb\x92\x93\xe3\x60\x07\x5b\x93\x7c\xbc\xb6\x57\xb3\xcf\x07\x19\x04\x1f\x91\x42\xa2\xee\xef\x43\x9b\xe0\x7a\xbe\xfc\x5e\x50\x5e\x90\x19\x8d\x77\x8c\x5b\x3d\xa1\x40\x4d\xe6\x46\xe5\x3c\x7e\x2e\x78\x34\x3b\x04\x5e\x03\x69\x49\x4e\x15\x4b\x2e\x7c\xea\x7b\x2e\x83\x70\x7a\x71\x80\x41\x1d\xc5\x9f\xc3\xb3\xe8\x74\x7e\x63\xe4\x4b\x38\x4d\x3b\x1c\xcb\x69\xad\x71\xbf\x63\x8c\x0b\xcd\x69\xed\x6a\xa9\x13\x06\x8d\xf1\x38\xc3\xd4\x01\xbd\x80\x22\x6c\xb1\x50\x0a\x7e\x9e\x8f\x1b\xf9\xf8\x86\x99\xbb\x09\x2e\xb9\xe4\xd4\xa8\x83\x7e\xf4\x9e\x93\x64\xc8\x52\x94\xf4\x02\x9b\x9d\xaa\xd4\x59\xb0\x59\xae\xd6\x9f\xc3\x01\x33\x55\xa9\x39\x18\x31\x25\xcc\xb6\xce\x39\x9d\xf2\x6c\xf9\x33\xac\xca\x9f\x27\xfa\x4b\x75\xc0\x97\xaa\xf3\x6f\xa7\xf0\xaa\xdd\xaf\x55\xd9\x07\x0f\x30\xb0\x0a\xff\x4d\x71\x39\x08\xe0\xcf\xc1\x9b\xc1\x1b\x3b\x76\x8d\xcb\x41\x00\x83\xc7\x02\x71\x00\x1b\xf8\x0b\x36\xed\x85\xea\x61\xd9\x65\xb0\xfd\xe6\x1c\xee\xc4\x75\xa7\x48\x57\x1a\x6c\x4c\xf3\x41\x23\xcd\x58\x20\x93\xa8\xa7\x6c\xb5\x48\xd8\x09\xe3\x45\x9a\x61\x5c\x42\xfd\x6b\xec\xdd\xb8\x07\x96\x33\xea\x43\x1d\xf2\xb6\xd4\x83\x57\x51\xb1\x80\xd7\x0f\xb5\x7b\xdd\xe6\x7c\x7b\x3d\x3b\x17\x4e\x91\xf5\xfd\x0e\x1f\x1e\x46\x93\x59\xd4\x50\xb4\xbf\xa7\x6d\x36\xa3\x6f\x3c\xef\x2b\x0e\xfa\x77\xe2\xd3\xbb\xab\xaf\x26\x55\xb1\x7a\x6a\x13\xfa\x7a\x6e\xb8\xad\x8e\xff\x13\x86\x6c\xed\xda\x9a\xb3\x5b\xf5\x47\x19\x35\xd4\xd2\x23\x33\x2d\x03\xf6\xd5\x04\x18\x9b\xe0\xf5\x83\xdb\xe2\x35\xa6\x76\x81\xea\x35\x8c\x63\x55\x48\xba\x4a\x36\x81\xaf\xd5\xcf\xfd\x97\xad\x2e\x36\xcd\x3b\xd8\x44\xbb\xdb\xdc\xa9\x5d\xa1\xba\x49\x0c\x93\x12\xef\x05\x4e\xc1\x5a\x73\xc8\xd5\x95\x56\x8f\x27\x2b\x9e\xc3\xe8\x63\x34\x8b\xba\xeb\xd7\x1a\x91\xef\x3e\x96\x8f\x60\xd2\x81\xf6\xa2\xfe\xbf\x6b\x6a\xeb\xfe\x9d\xde\xd6\xf4\x7e\x87\x69\xab\x8e\x53\x56\x11\x69\xca\x65\xfa\x34\xdf\x6b\x14\x53\xfd\xb9\xf5\x72\x7c\xc2\xcb\x02\xc6\xf6\x6c\x70\xf1\xfc\x02\x81\x53\xb5\x0c\x4a\x61\xff\xd6\x92\x6a\x34\x71\x4a\xd1\xfa\x50\x5f\x36\x73\xfd\xa6\x52\xbc\x36\xf2\xc4\xca\xea\xb8\x93\xa1\xd9\x70\xf1\xb2\xf5\xb1\x9f\x5e\x38\xef\x8a\xcc\x14\xf1\xa5\x6f\xf9\xf5\xdf\xb8\x0f\x56\x7b\x9d\xb7\xed\x47\xf8\x36\xca\x4f\xad\x38\x2f\xc7\xd1\xaf\xca\x50\xb3\xd8\x6c\x11\x3a\xae\x96\xde\x69\x03\x57\x05\xf5\x99\xc7\xf2\xed\xfc\xce\x8e\x46\xa3\x3d\x15\x82\xc6\x25\x6a\xf4\xad\x46\xd7\x8d\x75\x93\xac\x72\x81\x1a\x3a\xfb\xcc\x44\xd1\xf0\x96\x3e\xdd\xb4\x2d\xd3\xdd\xff\x70\x22\xfb\x5c\xe1\x10\x55\x59\x9f\x7b\x2a\xc6\x1d\x5e\xe4\x24\xfb\x7f\x00\xdc\x64\xe8\xfa\xfe\x57\x93\x1d\x26\x3b\x17\xf3\x56\xa7\xeb\xd0\x7e\x6b\xcd\xfb\xd1\x73\x36\xb9\x7b\xa8\xb6\x7b\x62\xee\xf3\x35\xc6\x4a\x27\x11\xf6\xf5\x34\x3b\x98\x4d\x66\x11\x68\x37\xcd\x20\x3d\x87\xda\x6e\xdf\xea\x91\xda\x96\x4d\x75\x72\x76\x35\xc9\x0e\x3a\x75\xdf\x2f\x86\x67\xe9\xb1\x9f\x6c\xbb\xb9\x56\x1f\xb9\xd5\x62\x8f\xcb\xdc\x5e\x4f\xab\x9f\x6a\x75\x80\x1f\xce\xeb\x56\x8b\x6d\x11\xb2\x93\xf7\x0e\xa9\xd1\x1d\xf2\x5f\x18\xc5\x19\x08\x95\x1a\x48\x1b\x39\xec\x09\x4c\x5b\x87\x47\x5f\xb7\x65\xca\x0d\xa1\x7c\x8a\xa9\x73\xa5\x09\xfe\xff\x3f\x10\x7e\xe6\x0f\x56\x64\xb5\xea\x3f\x01\x00\x00\xff\xff\x09\x44\x0a\xe7\xe4\x1d\x00\x00")
+var _environmentCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdd\x59\xdd\x6f\xdb\x36\x10\x7f\xf7\x5f\x71\x35\xf6\x18\xbb\x43\xfb\x32\x08\x43\x01\xd7\x4e\xdb\x6c\x59\x66\x44\x49\x0a\xac\xe8\x03\x2d\xd1\x32\x11\x9a\xd4\x48\xca\x99\x5b\xe4\x7f\xdf\x91\x92\x6c\x7d\xfb\x23\x71\x3a\x4c\x4f\x36\xc9\xbb\xfb\xdd\xf1\xee\x78\x3c\x4e\xa8\x0e\x14\x8b\x0d\x93\xc2\x83\x77\x3d\xc0\xef\x66\xc1\x34\x18\xba\x8c\x39\x31\x14\x42\x1a\x73\xb9\xd6\x40\x60\xc1\xa2\x05\x5f\x03\x59\x11\xc6\xc9\x8c\x53\x38\x1f\xfb\x10\xf0\x44\x1b\xaa\x20\xd1\x4c\x44\x40\x04\x8c\x12\x23\xfd\x80\x70\xfb\xf7\xa3\x92\x49\x7c\x06\x0f\xcc\x2c\x1c\x67\x4b\xb0\x90\xda\x68\x08\x99\x36\x8a\xcd\x12\x43\x43\x20\x81\x92\x5a\xc3\x32\xe1\x86\xc5\xc8\x76\x94\x0a\x60\x9c\x99\x35\xfc\x25\x05\xd5\xc3\x5e\x6f\x4a\x14\x59\x52\x94\xa4\xbd\x9e\xe3\xe5\xbf\x7d\x9f\x04\xf7\xd4\x5c\xe1\xb0\xe7\x46\xec\x37\x29\xaa\x73\xb3\xa0\x20\x70\x1a\xe4\x1c\x0c\xfe\xf6\xdf\xc2\xcc\xd1\x80\x91\x90\xa0\x5a\x24\x04\x45\xb5\x4c\x54\x40\x51\x63\xb9\xe1\x72\xb3\x8e\xa9\x07\x3e\x22\x14\x51\x2e\xed\x77\xba\x9e\x2a\x3a\x67\xff\x74\x08\x8b\x89\x59\x00\x13\x4f\x15\x76\x87\x5a\x5a\x9e\xed\x82\xa8\x58\x31\x25\xc5\x92\x0a\x03\xab\x74\x75\x07\xbf\x89\xd0\x63\x4e\x89\xa0\xea\x92\x2c\x67\x21\x71\xca\x74\x70\x47\xe8\xf7\x74\x9d\x5b\x2d\x14\x7a\x10\xa4\xe4\xc0\x1d\x3d\xcc\x13\x11\xd8\xe5\xa8\xa1\x08\x39\x1d\x82\x8f\x6a\xce\xd6\x40\x03\xdd\xa5\xd7\x85\xd0\x86\x88\x80\x4e\x14\x61\x47\x82\x61\x19\x8b\x41\x98\xf2\x78\x22\x22\x9f\xaa\x15\x43\x40\x4c\x07\x12\xed\xb8\x3e\x06\x92\x4e\x79\x0c\xc2\x9c\xc9\x13\x31\xdd\x4d\xc7\x2d\xb2\xc7\x0b\x29\x35\x85\x87\x05\x0b\x16\x76\x19\x8a\xc7\x48\x2d\x46\xa1\x5e\xc8\x84\x87\x30\xcb\xc3\x16\xa3\xab\xe6\x69\xa3\xcf\xbe\xe7\x9d\x8f\xdf\x78\x9e\x95\xe4\x5d\x84\xe5\xcd\xf1\x93\x99\xa0\x46\xef\x03\x41\xa7\x4b\xeb\x30\xf2\x5d\xd2\x7b\x01\xba\xc4\x5c\xf0\xeb\x16\x55\x0a\xc0\x02\x7b\x97\x22\x1b\x5d\xbe\x3f\x0a\x14\x05\x12\xc7\x9c\x05\xc4\x6d\x82\x0b\xc0\x19\xe1\x16\x97\x7a\x16\x5c\xe3\x54\x5b\x9f\x7d\xa3\xdd\xc0\x2c\x14\x91\x2c\x67\x28\x17\x7d\x26\x90\xc2\xa4\xbe\xbb\x35\x13\xa6\x08\x12\x5a\x08\x6e\x6d\x66\xc6\x0a\x9c\x2b\xc7\xa1\xbc\x59\x6e\x66\xa7\x6c\x83\xab\xac\x64\x54\x63\x23\x73\x2f\x91\x99\x5b\x6e\xf9\xcf\x09\x26\x69\x0f\xcc\x9b\x21\x27\x2a\xa2\x59\x7a\xb9\xf2\x6d\x96\x6e\xc1\xe1\x53\x4e\x03\xe3\x84\xe0\x42\xf8\x86\x2b\x5d\x46\x44\x6c\x73\xa9\xf2\xe8\x81\x4d\xf4\x74\xe5\x31\x62\xc8\x44\x46\xa3\xe9\x45\x7b\x7c\x4e\x31\x53\x21\xeb\x58\xc9\x15\x0b\x29\xac\x31\xdf\x42\x88\x74\xa1\x8c\x00\x09\x6d\xe0\xee\xa9\x64\xbf\x9f\x4a\xb5\x89\x9f\x30\xd5\x91\x0f\x90\x67\x8c\x2b\x0a\x26\xa5\x04\x3d\xb1\x64\xee\xec\x54\xe8\xb4\xb3\x1b\x9d\x92\x88\xaa\x49\x62\xd6\xe7\x22\x8c\x25\x13\xa6\x43\x70\xa2\xb8\xdd\xd7\x0d\xc9\xc6\x9a\x34\xa3\xb5\x58\x84\x34\x6c\x7e\xa8\xd2\xd5\xc4\xd8\x02\xe2\x93\x7c\xc8\x65\x62\x85\xa0\x28\x9e\x70\x11\xb3\x0a\x62\x3c\x35\xee\xee\x1e\x49\xb0\x86\x49\xab\xd5\x20\x4d\xa9\x9b\xb9\x11\xe7\xf2\x81\x86\x77\x84\x27\xb4\x90\x15\xec\x37\x68\x5a\x9f\x4e\x04\x5c\x26\xe1\x92\xc4\xbd\xde\x58\x8a\x90\x59\x15\x32\xe2\x5b\x4d\xfd\xeb\xbb\x34\xfb\x7b\xf0\xea\xfc\xef\x84\x70\x0d\x5f\xe0\xd5\x35\x9d\xd7\x6c\x71\x56\x90\x00\x5f\x73\xfa\xb1\x65\xfe\x07\x89\xf7\x20\xcf\x71\x20\x71\xef\x3a\x2f\x09\x52\x20\x23\x8c\xab\x65\x41\xa3\x42\xca\x76\x02\x3e\x48\xb5\x74\xf9\x0c\xf3\x91\x21\xc1\xfd\x66\xe1\x54\xc9\x98\x2a\xc3\xaa\xe6\xb8\xc9\xea\xb8\xdb\xeb\x4b\x0f\x86\xaf\x89\xe3\x3f\x5c\x93\x25\x2f\x2d\x2b\x96\x57\x50\xf9\xce\xb7\xc5\x86\x2b\xb6\x52\xb5\x1c\x26\x87\xc1\x0e\xd6\x88\xb2\xe4\x58\xe7\x66\xbf\x0f\x08\xff\x23\x35\x23\x63\x9a\xe7\x07\xf6\x34\x69\x99\xf9\x33\x31\x71\x62\xf4\x70\x5c\x89\xa4\xad\x6b\xa0\x19\xf4\x8d\x8c\x59\x70\xac\x70\xff\x6a\x97\xf0\x82\x90\xda\xca\x4b\x3c\x68\xde\x67\xe7\xcc\xb1\x08\xf0\xc8\xdb\x81\xa0\x28\x25\x0b\xd9\x2b\xff\x74\x7e\xa3\xc5\x4b\x38\x4d\x3d\xf7\xa5\x64\xb5\xf1\x3c\x49\x05\x89\xc2\x4b\x82\xbb\x66\x9c\x30\x68\x74\x26\x67\x10\x39\x41\x2f\x60\x08\x5b\x99\xa5\x0b\xf1\x57\xe9\xf0\xbb\x21\xfa\x1e\x33\x23\x13\x2e\x7d\x9d\x4e\xe7\x30\x1c\xa0\xd1\x85\x79\x01\x65\x2f\x65\xe4\x76\xb0\x7c\x93\x3b\x2c\x60\x90\x87\xde\x19\x31\xa9\x98\x4d\x51\x79\x3a\xe3\xd9\x5a\x73\x90\xd7\x9a\x07\xfa\x4b\x5e\x4d\xa5\xa6\xcb\xfe\x9d\xc2\xab\xaa\xb3\x79\x8d\x0d\xdf\xa1\x6f\x0d\xfe\x1b\x06\x5a\xdf\x83\x2f\xfd\xb3\xfe\x99\x1d\x43\x3a\xfc\xdb\xdf\x56\xe3\x7d\x78\x84\xaf\xf0\x58\x67\x54\x0c\xcb\xa6\x0d\xeb\xde\xce\x41\x25\xae\x7b\xfb\xa6\xc1\x12\x59\xf3\xcd\xf7\x88\x2d\xdf\x94\x0a\x5e\xa9\x4a\x38\x38\x9e\xb6\xb7\xe8\xff\x8c\x3f\x94\x5a\x28\x59\xb1\x52\x18\x6a\x58\x6f\xeb\xee\x0c\x48\x53\x4f\xa1\xad\x0a\x68\x87\xd4\x71\x0b\xff\x71\x7b\x55\xbb\xd1\xff\x2f\x76\xac\xa3\xd7\xd1\xb6\x6f\x23\x85\x96\x7c\x85\xe1\x8e\xb5\xbd\xf0\xc8\x83\xf6\xb0\x6c\xf7\x7e\xfa\xee\x50\x5e\x63\xa9\x2f\xc5\x63\xfe\x77\x14\x04\x32\x11\xe6\x22\x7c\xf4\xb2\x4b\xce\xeb\x6c\x66\xa3\xce\x63\xf3\x76\xdb\x29\x1d\x93\x80\x36\xee\x78\xed\x26\xe2\x4d\x15\x5b\xe1\x66\xa1\x07\x6e\x28\x5b\x3c\x20\xaf\xc9\x77\x3a\x40\x66\x4f\xab\x69\x0d\xf4\x90\xcb\x80\x94\x77\xbf\x74\x07\x72\x54\x7e\xf5\xa2\xe3\x1a\x8f\x0e\x9b\xbb\x08\xd9\xbb\x5f\x8d\x73\xb1\x8d\x57\xe2\x7f\x17\x07\xd5\xe3\xbf\xb1\x77\x76\xba\x13\xac\xda\x67\x7b\x81\x32\xa0\xd0\x38\x76\x85\xb5\x75\xbe\x93\xdd\x1e\x46\xfe\x47\xac\x97\x9b\x0b\xf8\x02\x90\x27\xd7\x25\x7b\x20\x69\x90\xf6\xac\x71\xdf\xde\x75\x4d\x3d\xcb\x96\x4e\xa7\x2c\x85\xa2\x08\x55\x3b\xcc\x7f\x4a\x15\x61\xfb\xb9\x81\xc6\x3d\x1d\x70\xcc\x74\x83\x2c\x91\xbd\x80\xf3\xe7\x4d\xa6\x74\x71\xf6\xaf\xb6\xaa\xd4\xf6\x2b\xef\xae\x1d\x6a\x4b\xe3\xae\x43\x99\x2e\x2f\x8c\x1c\x58\x1e\xee\x77\xaa\x95\x5b\x74\xd9\xda\xe2\xd8\x0f\xaf\xfe\xab\x4b\xae\x6c\x7b\x2c\x6b\x12\xb7\xb7\x0d\x76\x96\xac\x8d\x2d\x83\xad\xf8\xba\x94\x1f\x5a\x36\x63\xe4\x7c\x92\xda\x94\x2b\xe6\x23\x2f\x04\x95\x87\x83\xfc\x56\xd0\xcb\x64\x65\xcf\x75\x8d\x6d\x99\xd2\x39\x3a\x02\x45\xe7\x54\xd1\xac\x39\xed\xfa\xf7\x8e\xc8\x1a\x17\x47\x8a\x36\x73\x8d\xbf\xa2\x15\xda\x6c\x53\xdf\x99\xe6\x26\x4e\x6f\x97\x2b\xec\x82\x2a\x8a\xb4\xa7\x42\xdc\xe0\x45\x3b\x9e\x8c\x6c\x97\xd8\xbe\x14\x5d\x4c\x2a\x48\x2a\xe5\x45\xad\x5d\xb7\x4b\xdf\xc2\x73\xcf\xf0\x18\x25\xab\x07\x63\xbd\xb1\xe7\xa6\xaf\x69\x20\x55\xe8\x53\xb3\x3f\x32\xfb\xca\xa0\x1c\x99\xa6\xe6\x18\x68\xd5\xe6\xdb\x16\xda\x06\x4d\x7e\x72\x36\x75\xfa\x76\x3a\x75\xdb\xa3\xd4\xf0\x79\xc1\xd6\x3b\x84\xc5\x91\x5b\xc5\x3b\x5c\x06\xcf\xc0\xfc\x79\xb3\x28\xe0\xd9\x71\x21\x8a\x4d\x11\x52\xc9\x7b\xbb\xcc\xe8\x0e\xf9\xcf\xc4\x04\x0b\xb4\x62\xa4\x21\x2a\xe5\xb0\x03\x90\xd6\x0e\x8f\xb6\x96\x91\x7d\x0f\xa4\xe2\x90\xad\x8e\xa5\x32\xf0\xcb\xcf\xc0\x33\xca\x67\x36\x64\xce\x75\xdf\x5b\xd5\x8e\xcb\xd1\x5e\x16\x07\xa4\xa8\x3f\xeb\x6c\x6f\x3b\x8d\x69\xa6\x15\x5b\xef\x5f\x6b\xe6\xe7\xce\x7f\x22\x00\x00")
 
 func environmentCloudformationStackYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -304,7 +304,7 @@ func environmentCloudformationStackYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "environment/cloudformation/stack.yaml", size: 7652, mode: os.FileMode(420), modTime: time.Unix(1508275893, 0)}
+	info := bindataFileInfo{name: "environment/cloudformation/stack.yaml", size: 8831, mode: os.FileMode(420), modTime: time.Unix(1792379698, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -409,7 +409,7 @@ func resourcesGo() (*asset, error) {
 	return a, nil
 }
 
-var _servicesWebCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x59\x4d\x73\x1b\xb9\x11\xbd\xeb\x57\x60\xb9\x7b\x48\xb6\x42\x51\x62\x55\x3e\x16\x37\x7a\xa4\x5d\xb3\x22\xc9\x2c\x0e\x2d\x1f\x52\x2e\x17\x88\x69\x72\x50\xc6\x00\x53\x40\x8f\x14\x5a\xd1\x7f\x4f\x01\x98\x21\x67\x38\x1f\xa2\x1d\x25\xd9\x83\x79\x12\xc1\x87\x7e\x40\xf7\x43\xa3\x1b\x5a\x30\xc3\x32\x40\x30\x96\x9e\x9d\x11\x42\xc8\x4c\x82\x41\xbb\xd2\xb9\xe0\xd4\x0f\xb8\xcf\x15\x58\x6e\x44\x8e\x42\x2b\x4a\x56\x29\x90\xd9\xf2\x8e\xe8\x0d\xc1\x14\x48\x7c\x17\x13\x74\x70\x82\x9a\x58\x50\x09\x61\x92\x99\x8c\x28\x8d\x62\x23\x38\x73\x93\x2c\x41\xbd\x37\xb6\xda\xe5\x40\x49\x8c\x46\xa8\x6d\xe0\xbc\x5f\x44\x03\x5c\xf7\x8b\x88\x60\xca\xd0\xb3\x5d\x47\x31\xe1\xb2\xb0\x08\x86\x08\x4b\x12\xc8\xa5\xde\x41\xd2\xb6\x3f\xfb\x10\x53\x7a\x1d\x4d\x29\x75\xd6\xe9\x3c\x09\x54\x51\x98\x3b\x40\xa7\x58\x06\xd5\xde\xea\x6c\xa8\x4b\xb6\xe1\xbd\x5c\x81\x15\x06\x92\x48\x17\x0a\x87\x58\x8a\x6c\x0d\xc6\xf1\x08\x65\x91\x29\x0e\xb6\x22\xb5\x60\x1e\x04\x07\x47\x68\x0a\x75\x44\x75\xe7\xe7\x05\xaa\x1b\x61\x11\xd4\xe0\x66\x66\x79\x2e\xcb\x18\x90\x1b\xcd\x12\xf2\x86\x49\x47\x66\x88\x2c\x27\x7b\x1a\xd8\x0a\xbf\xc7\x47\x81\xe9\xc0\xde\x16\x0c\xd3\x01\xb2\x9c\x61\xda\x32\xe7\xb7\xd4\xbb\x8c\x21\x32\x6d\x86\x1c\xc8\xb5\x42\x26\xdc\x06\x72\x6d\xd0\xd1\xae\x85\x72\x3a\x08\x84\x37\x6f\x06\x4c\x2f\x75\x81\xb0\x30\x42\x1b\x81\xbb\xa1\x0d\x95\x10\x17\x99\x1e\xef\x99\x42\x02\xd9\x68\x43\x30\x15\xb6\x0a\xdd\x00\xf5\x8a\xd9\xcf\x57\xb0\x11\x4a\x78\x9a\x93\x4e\x18\x32\xfb\x99\x24\xfb\x49\x25\x1d\x9c\xc0\x76\x05\x21\x16\xc6\xbb\xfe\x0a\x24\xeb\xdb\xee\x5b\xfd\x48\x32\xa6\x76\xc4\x02\xd7\x2a\x71\x07\x96\x3c\x32\x81\x9e\xec\xe0\x6b\xae\x95\x02\x5e\x1d\x69\x92\x18\x26\x14\x59\xc3\x46\x1b\x20\x09\x54\x81\x17\x6a\x4b\x58\x6d\x56\x52\xf8\xa1\x70\x7c\x32\x50\xd8\x2d\xea\xc3\xc2\x36\xac\x90\x48\xc9\xe5\x45\x99\x1f\xc0\xd8\x61\x6f\x3d\x04\xc4\xd1\x11\xea\xf1\xcc\x12\xac\x2e\x0c\x87\x2a\xe5\xc5\x01\x4d\xbb\x33\x48\x4c\x69\x7c\x64\xee\x0a\x72\x50\x89\x7d\xa7\xe8\xfe\x0c\x2e\x0b\x79\xf8\x7d\x61\x74\x0e\x06\x85\x63\x20\xb5\x4f\x69\xe7\x8e\x65\x40\xc9\xd3\xd3\x79\xf9\xfd\xdc\x0d\x3c\x3f\x37\xa0\x55\x9e\x22\x3f\x2c\x61\x53\x7d\x6b\x20\x96\x5a\x42\xf9\x73\x69\xc7\x8d\x34\x20\x8d\x5c\x14\xa0\xf5\xa1\x06\xf6\x48\x98\x01\xdd\x1c\x6c\xe0\x17\x92\x71\x70\xb1\x8c\x9d\xba\x60\xdb\xda\xac\xfb\x8c\x4b\x57\xda\xdc\x00\x4b\x5a\x3f\x13\xf2\xab\x00\x99\x50\x32\x62\x88\x46\xac\x0b\x04\x0a\xdc\x9e\xb3\x07\x26\x24\x5b\x0b\x29\x70\x37\xfe\xa2\x15\x8c\xbe\xd9\x72\xaa\x2d\x1e\xf9\xa4\x12\x61\xa4\xd5\x46\x6c\x8b\x70\x36\xda\x6b\xbf\x65\xff\x14\x59\x91\x2d\xc0\x70\x70\xee\x9b\x5e\x5c\xb4\x31\x42\x39\xcc\x5b\x60\x12\xd3\xdd\x1e\x7a\x79\x04\x75\xb9\xa3\x4a\x1d\x9d\x5e\x8a\xaa\xa3\x12\xa4\xf1\x08\xeb\x8e\x1d\xed\x41\x3e\x33\x86\x00\xb9\x3f\x3b\xa0\x2b\x66\xb6\x80\xbf\x19\x5d\xe4\x33\x73\x08\xe6\x7e\xf0\x90\x8b\xbc\x10\x66\xee\xc2\xee\x94\x7f\x24\x75\x91\x7c\x60\xc8\x53\x4a\x3d\xea\x45\x8d\x7b\x54\xd8\xc6\x0f\x71\xb1\x26\x3f\x3d\x95\xea\x7c\x1e\xbb\x3c\x36\xe6\x2d\xe5\xf9\x19\x8d\x13\x7d\xa7\x91\x80\xd2\xc5\x36\xf5\xb9\xcf\xba\x7b\x50\xb9\xa3\x5b\x9f\xe6\x38\x6c\xce\x78\x58\xea\xe4\x3a\x8a\x1b\x3f\xdf\x02\x1a\xc1\xc3\x42\xa2\xc5\xfb\xf7\x28\xa4\xf8\xc2\x5a\x32\x8e\x91\xa1\xb0\x28\x38\x25\x31\xcb\x72\x09\xed\x93\xb1\x00\x23\x74\x42\xc9\xe5\xb4\x19\xd5\xeb\x07\x26\x0b\x6f\x31\x20\x2c\x25\xd3\xe6\x91\x4a\x0d\xd8\x54\x3b\x19\x0e\x9f\xbd\x48\x67\x39\x33\xc2\x6a\xf5\x2e\x07\xc3\x50\x1b\x4a\x6e\xc0\xda\x55\xca\xd4\xde\x48\xdb\x67\xb3\x90\x88\xbb\x04\xe5\xf9\x6a\xa5\x5c\xf3\x08\x88\x0c\x94\xed\x9b\x5a\xba\x2c\x64\x1c\xf7\xa5\x43\x5f\xf7\x4c\x16\x30\x90\x9b\xea\x96\x6a\x49\x6f\xc0\x52\x43\x2a\x21\x21\x96\x05\x5b\x23\x76\xff\x23\xa5\xfa\x0a\x76\xcc\xf3\x62\x5c\x38\x6e\xdb\xd6\x4d\x5b\xb4\xd1\xe2\x3d\xa9\xa1\x5d\x75\x9a\x8a\x6d\xfa\xdf\xd5\x6c\x99\xa4\x3a\xf5\xfa\x97\x6f\x97\xeb\xdf\x2e\x5e\xd4\xe7\x6f\x06\x18\x82\xf9\x2e\x51\x17\xb6\x4c\x9b\xdd\xff\x4b\xa5\x99\x67\xff\x0a\xa1\x86\xe5\xbe\xa6\x56\x5b\x0e\xf8\x2e\xd7\xfa\xd4\xdf\x99\x5c\x6b\x75\x40\x77\xc9\x2b\x99\x8b\xd6\xa1\x68\x11\x6a\x7b\x3f\xa5\xb4\x5e\x3f\xec\x83\xd7\xa3\xda\xfb\x9c\xcf\xab\x6b\xef\x7e\x11\x35\x03\xee\xcb\x97\xa3\x98\x2d\x8c\x46\xcd\xb5\xa4\xe4\xed\x6a\xb5\x68\x2a\xcd\x1d\x97\x7a\x97\x5b\x7d\xde\x22\xe6\x91\x4e\xc0\xd7\x66\xe3\xe9\x2f\xbf\x34\x10\xa1\x28\x8b\x52\xe0\x9f\xe7\x0a\xc1\x3c\x30\x19\x87\xde\xc6\x37\x16\x3d\x50\xdf\xe2\x96\xb5\x15\xc3\xb4\x17\xd6\xbf\xdc\x1a\x6a\x25\x32\xd0\x05\xee\x69\xff\xdc\x01\xdc\xed\xe5\x58\x96\xe9\x47\x2a\xaf\x55\x72\x55\x8d\xdc\x29\xb2\xbf\xc3\x8e\xee\x5b\xb0\x50\xd3\x7e\x4a\x5c\xc3\x77\x8e\x61\x15\x9f\xca\xce\xee\x05\xf1\x75\xb4\x8c\xcd\xd7\x06\xd7\xe9\x7c\x95\x6e\xbe\xaa\x45\xaa\xc0\x87\xb2\xb5\x1a\x39\x92\x4b\xd9\xbc\x07\x4c\xa3\xa1\x3f\xca\x05\x2a\x11\xbd\x87\xba\x6c\x12\x72\x86\xe9\x38\x67\x88\x60\x54\x9f\x7b\x3a\xe6\x93\x43\x62\x68\x69\x65\x20\x93\xbc\x58\x9c\xb7\x59\x82\x97\x37\xda\x3c\x32\x53\x3e\x64\xfd\x48\x56\xa9\xb0\x64\x3e\xbb\xf5\x9d\x20\xd9\x1a\xa6\xd0\x36\x1e\x90\x18\xe7\x60\x6d\xfd\x45\x66\x52\xa8\xd6\xe3\x4c\x69\xad\xff\xa5\xe8\x0f\xb3\x9b\x37\x7f\x3c\x27\x73\x74\xd7\xc4\x9a\x59\x48\x88\x56\x9e\x28\x09\x8d\x3a\x49\x34\x2f\x5c\x47\x05\x09\x49\xc1\x94\xea\xf8\x91\xa4\x88\x39\x9d\x4c\x12\xed\x5a\xba\x47\x7b\xce\x32\xf6\x45\xab\x73\xae\xb3\xc9\xcc\xff\x79\x1d\xc5\x13\xc9\x10\x2c\x4e\x12\x78\x00\xe9\x24\xb1\x2d\x44\x02\x93\x72\x0b\x9f\xe6\xb3\xdb\x4f\x46\x4b\x38\x4f\x31\x93\xf5\x66\xdd\x77\xbf\x5d\x2a\x9c\xcf\x6e\x29\x6d\x74\xc2\x7d\x52\x73\xa0\xda\xc5\x0a\xdc\x8e\x4b\xda\xf1\x4f\x4f\xde\x56\x8c\x8c\x7f\x0e\x99\xb3\x21\x3e\x9f\x23\x26\xcd\x78\x5b\x5b\x64\x7e\x55\x0b\x2d\x05\xdf\x5d\x95\x2e\xa1\xe4\x5f\xad\x78\x3e\x75\x2a\x69\xe4\xee\x49\xdf\x51\x8f\x28\xf9\x47\x37\xc6\xe3\xae\x37\x1b\xe0\x0e\x34\x9a\x49\xa9\x1f\x47\x7f\xea\x87\x2e\x8c\x50\x5c\xe4\x4c\x8e\x28\x79\x22\xa3\xd2\x77\xce\x3e\x19\xf9\x46\xdb\x87\xc1\xc5\x86\xeb\x6c\x44\x3e\x3e\x0f\xd8\x0a\x8a\x0e\x73\x2d\x5a\x7a\xd8\xf1\x88\x7c\xec\x9c\xf6\xdc\x1e\x3e\x72\xa4\x73\x55\xcf\xc3\x41\x70\xe3\x37\xc5\xa7\x66\xfc\x10\x87\xce\x15\x0e\x78\xb9\x7c\x73\x72\x6e\x9e\x5e\x5c\x4e\xc7\x97\x17\xe3\xcb\xbf\x0e\xf9\xfa\xc4\xf0\xed\xf1\xa7\x87\x71\x3f\xe5\x10\x82\x17\xb1\x1e\x0f\x7c\x4a\x67\x05\xa6\xda\x88\x2f\x10\x03\x2f\x5c\x7a\xf4\x39\x66\xae\xb6\x06\xac\x3d\x81\x74\x6f\x28\xd4\x90\x6b\xf8\xf9\xe4\x49\xe1\x3e\x90\x9a\x25\xeb\xea\x3e\xa0\x57\xfb\x47\xc2\x79\xf5\xee\xfd\xab\xd1\x59\xfd\x85\xe4\x3f\xb4\xff\x1a\xab\x5c\x1e\xaf\xf1\x83\xc0\xf4\x15\xd7\x58\xf9\x20\x64\xfd\xd3\xc3\x30\xb4\xe3\xda\x0d\xf2\x9a\x06\x43\x95\xf2\x2a\xde\xac\x76\xfb\xa2\xa9\x8f\x27\x9c\x85\xea\x11\xd7\x1d\xa0\x9f\xfb\x4d\x76\xe4\x20\x3f\x7c\x76\xf6\xae\xc0\xbc\xc0\xea\x0d\xb8\xb3\x28\x6e\xb4\x4d\x4b\xd8\x80\x01\x15\xfe\x31\xe3\x6e\x3f\xb7\x49\xb2\xae\x6e\x49\xf4\x06\xc8\xb6\x71\x83\xd7\x0b\xab\xd6\xf3\x5b\xe7\x4d\xd6\x7a\xd9\x76\x17\xbc\xbb\x01\x7b\x5f\xfd\xeb\x1c\xf5\x87\xe0\x3a\xc7\xa9\x5b\x02\xde\xfe\x1f\x46\x87\xfd\xb3\x7f\x07\x00\x00\xff\xff\xaf\x24\xac\x8f\x36\x1c\x00\x00")
+var _servicesWebCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x59\xdd\x73\xda\x38\x10\x7f\xcf\x5f\xa1\xd0\x3e\xdc\x75\x0a\x24\xcc\xdc\x47\xf5\x46\x21\x6d\x99\x23\x29\x03\x24\x7d\xe8\x64\x32\xc2\x16\x58\x53\xdb\xf2\x49\x72\x72\x24\x97\xff\xfd\x56\x92\x0d\x16\xfe\x80\xe4\x72\x1f\x0f\x65\x26\x19\x90\x57\xbb\xda\xdd\xdf\x7e\x68\x3d\x21\x82\x44\x54\x51\x21\xf1\xd1\x11\x82\x4f\x3f\xa4\x42\xc9\x39\x4f\x98\x87\xcd\x82\xfe\x0c\xa9\xf4\x04\x4b\x14\xe3\x31\x46\xf3\x80\xa2\xfe\xf4\x02\xf1\x25\x52\xf0\x75\x76\x31\x43\x4a\x93\xc3\x7f\x24\x69\xec\x23\x12\x12\x11\xa1\x98\x2b\xb6\x64\x1e\xd1\x9b\x24\x3c\xdb\x30\x9b\xaf\x13\x8a\xd1\x4c\x09\x16\xaf\xac\xcc\xab\xc9\xa0\x41\x16\x3c\x05\x41\x44\x19\x69\x67\x83\x19\xf2\xc2\x54\xc2\x89\x11\x93\xc8\xa7\x49\xc8\xd7\xd4\x2f\xf3\xef\x7f\x99\x61\x7c\x36\xe8\x61\xac\xb9\xe3\x91\x6f\x45\x0d\xec\xde\x06\x71\x31\xd8\x23\xd7\xad\x28\x0d\xb4\xb3\xd2\x9a\x75\x01\x7e\x4c\x50\x7f\xc0\xd3\x58\x35\x49\x49\xa3\x05\x30\x05\x39\x2c\x96\x8a\xc4\x1e\x95\xb9\x50\x49\xc5\x2d\xf3\xa8\x16\x28\xd2\x78\x47\xd4\x85\xd9\x67\x45\x8d\x19\x1c\x2c\x6e\x54\xa6\x9f\x24\x61\xe6\x03\x34\xe6\xc4\x47\xef\xc1\x39\x20\x4c\xa0\x30\xdb\x6c\xc4\xd0\x15\x33\x3a\xde\x31\x15\x34\xe8\x36\x21\x2a\x68\x10\x96\xc0\xe3\x12\x3b\xa3\x52\xed\x31\x9a\x84\x71\xd1\x64\x40\x8f\xc7\x8a\x30\xad\x40\x02\x84\x5a\xec\x82\xc5\x1a\x07\x56\xe0\xf8\x7d\x03\xeb\x29\x4f\x15\x9d\x08\xc6\x05\x53\xeb\x26\x85\x32\x12\xed\x99\x1a\xeb\x89\x34\xa4\x68\xc9\xc1\x8c\x01\xc0\x31\x73\x5d\x83\xe8\x39\x91\xdf\x86\x74\xc9\x62\x66\xc4\x1c\x14\x61\x0a\xf6\x00\xf6\xf2\x4d\x99\x38\x7a\x80\xb4\x21\xb5\xbe\x10\xc6\xf4\x43\x1a\x92\x3a\x75\x3f\xf1\x3b\x14\x91\x78\x0d\x4c\xc1\xb2\xbe\x0e\x58\x74\x47\x98\x32\xc2\xb6\xb6\x86\x6f\x31\xf5\xf2\x90\x46\xbe\x80\x65\xb4\xa0\x40\x44\xe1\x84\xb9\xe3\x41\x3e\x22\x85\x5d\x7e\x6a\x96\x6c\xf8\x44\x34\x56\xd5\xa0\xde\x1e\x6c\x49\xd2\x50\x61\x74\x7a\x92\xe5\x07\x48\x4f\xcd\xd6\xba\xb5\x14\x3b\x21\xd4\x60\x99\x99\xa5\x18\x32\xe9\x71\xd8\xbc\xbe\x80\xa8\x97\x09\xf1\x68\x83\x90\xd1\x30\xe7\x3f\x08\x79\xea\xa3\x73\x92\x98\x6c\x61\xf6\x39\xc0\x2f\x86\xb1\x0e\x82\x0e\xc8\x53\x68\xb1\x46\xd4\x93\x35\xc9\xa3\xa4\x7c\xab\x75\x74\x34\x00\x57\x18\x9f\x4b\x7b\xac\x4b\x49\x8d\x68\x90\x8c\xd1\xf1\x05\x57\xe8\x2b\x3a\x3e\xfb\x3d\x25\xa1\xd4\xdf\xa6\x74\x59\xaf\xd8\x5b\xe0\x88\xae\xd1\xf5\xd1\xd1\x94\x4a\x9e\x0a\xc8\x38\xd8\xb1\x05\xae\xce\xa0\xf0\x6f\xb6\x63\xce\x21\x4d\x20\xcf\xcb\xcf\x60\x97\x3c\x07\x4d\x21\x0e\x36\xcf\x27\x82\x27\x50\x45\x18\x95\x5b\x9e\x05\x41\xfa\x48\x18\x3d\x3c\x74\xb2\xdf\x1d\xbd\xf0\xf8\xe8\x90\xe6\x79\xda\x2a\x95\xfd\x72\x28\xa6\x3c\xa4\xd8\xd1\x59\xaf\x38\x24\x4e\x2e\xb6\xa4\xc5\x25\x87\x76\x27\x30\x2d\xb5\xbb\xe8\xd0\x4f\x42\xb0\xa9\xc6\xf2\x4c\x47\x17\x38\x7e\x57\x59\xfd\x69\x67\xa6\x94\x89\xa0\xc4\x2f\x3d\x46\xe8\x03\xa3\xa1\x0f\xbe\x26\x0a\x50\xb0\x80\xac\x84\x01\x21\x1d\x72\x4b\x58\x48\x16\x2c\x84\xdc\xd3\xbe\xe7\x31\x6d\x3d\x9b\x73\xc0\xa5\xda\xb1\x49\x1e\x84\x00\xae\x25\x5b\xa5\x36\x37\x94\xcf\x7e\x4e\xfe\x60\x51\x1a\x4d\x28\x20\x45\x9b\xaf\x77\x72\x52\xa6\x01\xcb\x00\xcd\x27\x4a\x42\x15\xac\x37\xa4\xa7\x3b\xa4\x3a\x77\xe6\xa9\xb3\xd2\x4a\x83\x3c\x55\x58\x68\xdc\xd1\x45\x85\x46\x1b\x22\x53\x19\xac\x83\xf4\xd7\x0a\xd2\x39\x11\x2b\xaa\x3e\x0a\x9e\x26\x7d\xb1\x75\xe6\x66\xb1\x0a\x95\x53\x9b\x29\xb5\x1f\xd1\xf1\x68\x59\x71\xca\x42\xf4\x55\x3c\x6d\xa3\x8c\xc3\xda\x8a\xfc\x48\x55\x5f\x29\xb4\x09\xc4\x1c\xec\xf0\xb4\xe2\xc4\xe8\x30\x1b\x3c\xc1\x0a\x6d\xfb\xcc\x44\xf1\x05\xbf\x22\x61\x4a\xb3\xa2\xb0\x73\xa2\xca\xb8\xdf\x4d\x23\xe5\x24\xb0\xc9\x4d\xb8\xd2\x30\x75\x39\xe0\x90\xe0\xdf\xe4\xac\x91\x8f\xf7\x64\x35\x17\xdb\xb1\xb4\xa0\x2e\x63\x4c\x97\x7c\x48\xb3\x13\x0e\x7d\xc8\x1a\xa3\xf3\xcb\xf1\x7c\x74\xd5\x1f\x5f\x9e\x95\x28\x81\xc9\x14\x6a\xa0\xf0\x2b\x90\x5a\x8c\xbc\xd9\xf4\xaa\xf2\x39\x98\x71\x3e\x36\xb5\xab\xb8\x66\x43\x64\x10\x50\xef\xdb\x00\x72\x19\x8f\xea\x0e\xfa\x01\x42\x3f\x15\x74\x1e\x08\x2a\x03\xae\x43\xf8\x74\xdb\x39\x98\xb4\xd5\xd7\xed\x75\xa5\xd3\x8c\x13\xbe\x10\xe5\x05\x18\x1b\xaa\xbd\xde\x30\x54\xd6\x25\xc7\xb3\x74\x81\x5e\x3f\x64\x96\x7e\x6c\xeb\xae\xa3\xed\x95\xf2\xa4\xd9\xe1\x94\x46\x5d\x86\x68\xcc\xd3\x55\x60\x3a\x15\xa9\xbb\xd6\xb8\x58\xd2\x1c\x97\x9a\xa3\x76\xa1\xac\x38\x8f\xcf\x29\x44\x9e\x67\x0f\x32\x98\x5c\x5e\x2a\xc8\x7d\xf7\xa4\x94\x74\x67\x0a\xd6\xa4\x82\xab\x09\x9a\x91\x28\x09\x69\x39\x8f\x43\x12\x62\x5c\x5b\xad\xe7\x3a\xe0\xec\x16\x22\xc0\x70\xb4\x14\x10\xe5\x3d\xb7\x00\x6c\x2d\xde\x5c\x29\x06\x3c\x4a\x88\x60\x92\xc7\x9f\xc1\xa6\x44\x71\xa8\x52\x63\x2a\xe5\x3c\x20\xf1\x86\x49\xd9\x66\x7d\xaf\x50\xc8\xab\x02\x75\x7b\xf1\x72\x41\xcd\x20\x59\xcb\xba\xad\x99\xc9\x6c\x7d\xd4\x3f\x2a\x30\x69\x62\xbf\xa1\x92\x16\x39\x15\x4a\x74\x03\x27\x07\x2a\x36\x82\xb3\xeb\x95\xe3\xbb\x7f\x09\xa9\xe6\xbe\xd9\xf6\x92\xb4\x9d\x6a\xd9\xb2\x8c\x9b\x32\x68\xe1\xa0\xa8\x40\xad\xef\x92\x01\x5b\x05\xff\x2c\x66\xb3\x92\x5a\x89\xd7\x9f\x9f\x0f\xd7\x5f\x4f\xf6\xe2\xf3\x23\x74\x09\xe0\xf6\xef\x10\xd5\x6e\x8b\xb8\x58\xff\x57\x28\x8d\x8c\xf4\x27\x00\xd5\x1e\xf7\x25\xb1\x5a\x32\xc0\x77\xb8\xfe\x8f\xe1\x5a\xe8\x5a\xab\x2f\x68\x21\xd1\xde\xda\xb6\xd8\x50\x79\xaf\x7a\x18\x57\x75\xbb\x75\xa8\xbd\x4a\xbc\x4d\xa7\x75\x35\x19\xb8\x0e\x37\x6d\xe6\x8e\xcf\x80\x91\xe2\x1e\x0f\x31\xfa\x34\x9f\x4f\x5c\xa4\xe9\x70\x29\xce\xa4\x36\x1d\x90\x52\xc9\x80\xfb\xd4\xdc\x24\xda\xbd\x77\xef\xea\xfa\xa3\x51\x0c\xe6\x04\x3c\xcd\xec\x24\xa2\xa9\x95\x32\x03\xa9\xac\x07\x26\x2a\xa8\x25\xab\x3f\x6e\x81\x6a\x0e\xb0\x80\x2e\x71\x23\xf6\xa7\x0a\xc2\xf5\x06\x8e\xd9\xa5\x72\x07\xe5\x85\x7b\x47\x7e\xa3\xab\x04\xd9\x6f\x14\x9a\x50\xdf\x99\xce\xdc\xf8\x7a\x3c\xd3\x51\xf6\x14\x37\xd9\x1c\x66\x0f\xf8\x2a\x06\x3c\xee\x6c\x50\xdf\xcb\x9f\x84\x9b\x27\x5d\xe8\x73\xe2\xed\x25\x2b\x5f\xd9\x81\x4b\x36\x6a\xb3\x34\xce\xf8\x6d\x27\x17\xb8\xf3\x0e\xd7\x68\xd9\x95\x56\x8f\x19\xdb\xf0\x0f\x50\x12\xd7\x99\xa7\xb6\x77\xaf\xc6\x4a\x43\x26\x79\xd2\x55\xb2\x68\xe5\x25\x17\x77\x44\x64\x63\xe7\x57\x90\x00\x21\x6d\x8f\xfa\xe7\x66\x6e\x81\x56\x82\xc4\x4a\x3a\x73\x22\xe2\x79\xd0\x44\x16\xc7\x48\xdd\x34\x2e\x8d\x52\x33\x6e\xf5\x73\xdd\x1f\xfa\xe3\xf7\x3f\x76\xd0\x48\xe9\x32\xb1\x20\x92\xfa\x08\x68\xb4\x20\xdf\x4e\x96\x90\xcf\xbd\x54\xdf\xff\xe1\x09\x04\x6a\x86\x8e\x57\x28\x80\xf8\xc4\xdd\x2e\x3c\x95\x1d\x72\x07\x7f\x11\xb9\xe7\x71\xc7\xe3\x51\xb7\x6f\xbe\x42\x4d\xe9\x86\x90\x96\xa5\xea\xfa\xf4\x96\x86\x1a\x12\xab\x94\xf9\xb4\x9b\xa9\x70\x03\xea\xdd\x08\x50\xaf\x13\xa8\x28\x2c\x8e\x96\xcc\xac\xa6\x0a\x85\xb0\x03\x63\x67\x6e\x53\x07\x35\x4d\x54\x28\xac\xd4\x93\xed\x4c\x6c\xfb\xf5\x83\xbd\xb2\x2a\xe2\x7d\xb3\x99\xd3\x01\x9f\xc9\x11\x5d\xd7\xdf\x52\x82\x09\x34\x4b\x7b\x21\x1c\x66\x26\xc1\xe8\xcf\x92\x3f\x1f\x2a\x91\xd4\xd2\x75\xd2\xcc\x7f\x5a\x18\x7d\x7d\xa8\xb9\x09\x02\xdd\xd9\x72\x49\x3d\x4d\xd4\xea\x87\x21\xbf\x6b\xbd\xad\x27\x85\x90\x80\x30\x4c\x48\x08\xd4\x0f\x20\xc1\xaa\xa7\xf9\xa3\x96\x19\x0b\x19\x37\x68\xdf\x80\x53\x5a\xe8\xfa\xb1\x81\x97\x45\xb4\xdd\x2b\x95\xc4\x5b\x8d\x61\x63\xe5\xb6\xc7\xf2\xf2\x8e\x21\xb5\xa9\x6a\xc6\x5c\xd6\x8c\xcf\xf2\x4f\x81\xf9\xd6\x0f\x95\x27\x6c\xb0\x72\x36\x21\xd6\x66\xee\x9d\x9c\xf6\xda\xa7\x27\xed\xd3\x5f\x9a\x6c\x7d\xa0\xfb\x9e\xe1\xc6\x0a\x17\xec\xa5\x35\xf4\xd4\xeb\xe1\x7e\xaa\x02\x48\x8b\xf7\x14\xaa\x50\xaa\xd3\xa3\xc9\x31\xa3\x78\x05\x55\x47\x1e\x20\x74\xc3\xc8\xf6\x90\x0b\xfa\xe6\xe0\x4d\xb6\x1e\x84\x90\x4c\x16\x79\x3d\xc0\xc3\xcd\x48\x7f\x94\xbf\xa5\xfa\x20\x78\x54\x9c\xe7\xfd\x4d\xfe\x2f\x71\xca\xe9\xee\x19\xbf\x40\xb2\x7c\xc1\x33\xe6\x36\xb0\x59\x5f\xbe\x88\xc6\x85\x0a\xf2\x92\x0c\x6d\x97\xf2\x22\xd6\xcc\xb5\xdd\xcb\xea\xfa\x80\x58\xc8\x5f\x39\xe8\x00\x7a\x53\xcf\xf2\xb1\x26\x35\x1d\x1d\x7d\x4e\x55\x92\xaa\xfc\x8d\x45\x65\x53\xec\x5c\x9b\xa0\x4a\x83\xdf\x62\xfb\x5e\x46\x57\x3f\xad\x24\x5a\xe4\x55\x52\x19\x06\x50\x86\x8b\x15\xbc\xd8\x58\x15\x2b\x7c\x7d\x25\x2b\xbf\x22\x82\x02\xaf\x2b\x60\xed\x3b\xba\xa2\x8c\xe2\x6b\x8b\xea\x17\x31\xcd\x2a\x41\x8e\x3d\x84\xff\xbe\xa9\xef\x9e\x41\x6e\xf3\x19\xb6\x6f\xc2\xf2\x3e\xc6\xcf\x05\xd5\x9e\xad\x69\x32\xfe\x17\x7b\x27\x3b\xb0\x90\x20\x00\x00")
 
 func servicesWebCloudformationStackYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -424,7 +424,7 @@ func servicesWebCloudformationStackYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "services/web/cloudformation/stack.yaml", size: 7222, mode: os.FileMode(420), modTime: time.Unix(1518667900, 0)}
+	info := bindataFileInfo{name: "services/web/cloudformation/stack.yaml", size: 8336, mode: os.FileMode(420), modTime: time.Unix(1792379714, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -449,7 +449,7 @@ func servicesWebDockerComposeYaml() (*asset, error) {
 	return a, nil
 }
 
-var _servicesWorkerCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x58\x4b\x6f\xe3\x36\x17\xdd\xe7\x57\xdc\x11\x66\x35\xf8\x9c\x71\xfc\xa1\xd3\x82\x3b\xd7\xce\x4c\x03\x24\x19\xc3\x72\x26\x8b\x22\x0b\x9a\xbe\x96\x88\x50\xa4\xc0\x47\x52\x27\xcd\x7f\x2f\x48\x59\x8e\x64\xc9\xb2\x9b\xba\x8f\xc5\x68\x11\x20\xd2\xb9\xe7\x5c\xde\x7b\xf8\x30\x27\x54\xd3\x0c\x2d\x6a\x43\x4e\x4e\x00\x00\x86\x02\xb5\x35\x33\x95\x73\x46\xc2\x0b\xff\x8c\xd1\x30\xcd\x73\xcb\x95\x24\x30\x4b\x11\x86\xd3\x6b\x50\x4b\xb0\x29\x42\x7c\x1d\x83\xf5\x70\xb0\x0a\x0c\xca\x05\x50\x41\x75\x06\x52\x59\xbe\xe4\x8c\xfa\x20\x03\x56\x6d\xc8\x66\xab\x1c\x09\xc4\x56\x73\x99\x14\x9a\x23\xe1\x8c\x45\xdd\xa1\x27\x69\x86\xa5\xe0\xf9\x28\x06\x56\x44\x78\xc9\x05\xe6\x42\xad\xba\x05\xc6\x68\xb8\xc6\xc5\x48\x39\x69\xbb\x54\x5c\x36\x47\xed\x75\xb8\x34\x96\x4a\x86\xa6\x14\x35\xa8\x1f\x38\x43\x2f\xa8\x9d\xdc\x92\xba\x0e\x71\x85\xd4\x8c\x9a\xfb\x31\x2e\xb9\xe4\x81\xf7\xa0\x12\x5a\x6a\xee\x61\xb1\x09\x82\xa5\xd2\x55\xd1\x8e\x81\x7d\x43\x6d\xba\x65\x1e\x0a\xc4\xd6\x38\x76\x50\x4e\xd1\x28\xa7\x19\x96\x66\x88\x0b\x34\xd9\x82\x0f\x6f\x63\x42\xce\x47\x31\x21\xf1\x16\xdd\x44\xab\x1c\xb5\xe5\x9e\x01\x2a\x4f\xd9\x61\x78\x37\xc5\x65\xf9\x5f\x0d\x51\x6b\x51\x01\xab\xbe\xaa\x61\xb7\x6a\x5c\xa0\xeb\x2f\x6b\xf8\x89\xa0\x0c\x33\x94\x36\xb6\x9a\x5a\x4c\x1a\xe9\xf9\xa7\xb7\x1e\x9c\xc9\x35\xd2\x45\xe3\x33\xc0\x67\x8e\x62\x41\x20\xa2\xd6\x6a\x3e\x77\x16\x09\x32\x73\x4a\x1f\x28\x17\x74\xce\x05\xb7\xab\xde\x93\x92\x18\xbd\x99\x39\x55\xc6\x6e\xd5\xc4\x5b\xdb\x27\x3e\x52\x72\xc9\x13\xa7\x69\xdd\x54\xe5\x73\x45\x7f\xe3\x99\xcb\x26\xa8\x19\xfa\xf2\x0d\xfa\xfd\x26\x86\x4b\x8f\xf9\x05\xa9\xb0\xe9\x6a\x03\x3d\xeb\xf7\x5f\x8d\x1b\x4a\x3d\xf4\xd3\xb7\xb5\xe5\x23\xa1\xdc\xe2\x96\x5a\x96\x12\x12\x50\x7b\xfb\x1e\x50\xd7\x34\x43\x02\xef\x62\x37\x87\xf7\xcf\x6b\xc7\xbc\xf4\xbc\xe9\x7b\xac\xd1\xdb\x10\x51\x73\xf1\xb5\xb2\x80\x52\xb9\x24\x0d\x13\xc5\xf8\x09\x28\xbd\x5d\xab\x61\x5e\xc3\xe4\x94\x15\xa9\x7e\x3c\x1f\xc5\xb5\xcf\x57\x68\x35\x67\x45\x22\xa3\xc9\xcd\x8d\xe5\x82\x3f\xd1\x86\x51\x62\x4b\x2d\x37\x96\x33\x02\x31\xcd\x72\x81\x4d\xef\x4d\x50\x73\xb5\x20\x70\x36\xa8\x97\xf8\xfc\x81\x0a\x17\x18\x0b\x84\x21\x30\xa8\x9b\x36\xd5\x68\x52\xe5\x1b\xdd\xed\xee\x91\xca\x72\xaa\xb9\x51\xf2\x6b\x8e\x9a\x5a\xa5\x09\x5c\xa2\x31\xb3\x94\xca\x0d\x49\xb3\x66\x43\x16\x56\xda\x36\x63\x07\xbd\xca\xc2\x5e\x37\x19\xcf\x50\x9a\x5d\xa1\xeb\x92\x15\x13\xd6\xff\xd3\x62\xdf\x6f\x54\x38\xec\x98\xda\x55\xa6\x75\xfb\xf7\x30\xd5\xac\x72\xea\xc1\x2f\xeb\x9d\xa2\xd6\xbb\x7f\xc8\xa9\x61\x3f\xeb\xb1\xdc\xf5\x9c\xd7\x36\x4d\xdf\x34\x4d\x3b\x9a\xdc\x40\x05\x0d\xdc\x40\xca\x93\xf4\xef\xf5\xec\x7a\x19\x68\xf5\xeb\xa7\xb7\xdb\xf5\xa7\xfe\x5e\x7f\x7e\xd1\x48\x2d\xea\xef\x16\xf5\x6d\xcb\x94\x5e\xfd\x5b\x2e\xcd\x82\xfa\x9f\x30\x6a\x91\xee\x31\xbd\xda\x28\xc0\x77\xbb\x56\x43\xff\x63\x76\x7d\xf5\xe0\xa5\x4a\xcc\x17\xad\x5c\xde\xea\x55\xff\x35\xfc\x0d\x90\xbd\x46\x2d\x81\x6b\xaf\x86\x72\x79\x9a\xd8\x52\x76\xdf\xc8\x75\x8a\x16\xa5\x2f\xf6\x85\x1c\xd3\x95\x21\xf0\xff\x4f\x3f\xd4\x4e\xa0\x53\x25\xda\x4f\xa1\x17\xc3\x2b\x42\xfc\xd7\xbd\x19\x79\x50\x65\xe6\x20\x33\xbd\xf5\x61\xb8\xf7\xfe\xb9\x9e\xdb\x4b\xdd\x92\xd4\xa6\x04\x3e\xd6\xfd\x61\x8c\xcb\x42\x56\x13\x25\x38\x5b\x8d\x15\x73\x59\x38\x4f\xfd\xde\x68\xc2\x73\x4b\x5b\x00\x22\x3f\x11\xc2\xa1\x34\x22\xf0\x6b\x3b\x26\xe0\xce\x97\x4b\x64\x1e\x14\x0d\x85\x50\x8f\xd1\xff\x76\x43\x27\x9a\x4b\xc6\x73\x2a\x22\x02\xcf\x10\xad\x6b\xe7\xf9\x21\x0a\x67\xd5\x8c\x3e\x29\x49\x1f\xcd\x29\x53\x59\x04\x77\x2f\x1d\x5c\x85\xf9\x8b\x58\x63\x0d\x79\x1d\x71\x04\x77\xad\x61\x2f\xcd\xd7\x5b\x85\xf4\xa5\xda\x71\xf6\x2e\xca\xf8\xa6\xfe\x54\xc8\x5f\xfb\xd0\x9a\x61\x47\x95\xd7\x3f\xa4\x7c\x99\x07\xfd\xb3\x41\xef\xac\xdf\x3b\xfb\xb1\xab\xd6\x07\xb6\x6f\x83\x3f\xbc\x8d\x9b\x90\xd7\x16\xec\xc5\x06\x3c\xb2\x01\x19\x3a\x9b\x2a\xcd\x9f\x30\x46\xe6\x34\xb7\xab\x30\x0b\x2f\x64\xa2\xd1\x98\x03\x44\x37\x44\xc5\x26\x31\xc7\x0f\x07\x07\x09\xea\x97\x75\xa1\xe8\x62\x4e\x05\x95\x8c\xcb\x84\x8c\x51\x63\xc2\xfd\xfa\x75\x51\xfe\xa2\xfe\xac\x55\x76\xa9\xe8\xe2\xe7\x00\x42\xfd\x17\xf9\x8f\x91\xe5\x74\x3b\xc7\x5b\x6e\xd3\x23\xe6\x58\xd6\x60\x46\x75\x82\xf6\xf0\x36\x74\x8d\xb8\xe0\x0a\xdd\x3d\x26\x61\xf1\xf3\xf0\x28\xd5\x2c\x47\xbb\x97\xea\xee\x80\xb9\x50\xde\x4c\xf8\x09\xf4\x61\x37\x65\xcb\x1a\x14\x5e\x9f\x9c\x7c\x75\x36\x77\x76\xeb\x62\xa3\xbe\xad\x34\xee\x4e\x2e\x86\x57\xa0\x95\xc0\x9d\x17\x32\xd5\x7d\xba\x42\x79\xc0\xce\x5a\xd3\x9a\xe2\x12\x35\xca\xe2\x7a\xc9\xeb\x30\x1f\xfa\xe8\x43\x41\xa8\xc4\x40\x52\xdb\x73\xeb\xa7\x83\x86\xc8\x8e\xab\x9b\x6e\x45\x64\xe6\x90\xd1\x9d\xfc\x11\x00\x00\xff\xff\x95\xe0\x65\x83\x30\x14\x00\x00")
+var _servicesWorkerCloudformationStackYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x58\x4b\x73\xdb\x36\x10\xbe\xeb\x57\xc0\x9a\x9c\x32\x91\x63\xbb\xd3\xb4\xc3\x1b\x2b\xdb\xa9\x66\x6c\x47\x23\xc9\xce\x21\x93\x03\x04\x41\x22\xc6\x24\xc1\xe2\x61\x47\x56\xfd\xdf\xbb\x00\x48\x89\xe0\x4b\x8a\xeb\x3e\x0e\xd1\xc1\x93\x10\xfb\xf8\xb0\xfb\xed\x62\x81\x31\x16\x38\xa1\x8a\x0a\x19\xf4\x7a\x08\x7e\x61\x4c\x85\x92\x33\x9e\x31\x12\xd8\x0f\xe6\x77\x4e\x25\x11\x2c\x53\x8c\xa7\x01\x9a\x45\x14\x85\x93\x1b\xc4\x97\x48\xc1\x3f\xa7\x37\x53\xa4\x8c\x38\xfc\x45\x92\xa6\x0b\x84\x63\x2c\x12\x94\x72\xc5\x96\x8c\x60\xa3\x24\x61\x6d\x6b\x6c\xb6\xce\x68\x80\xa6\x4a\xb0\x74\xe5\x7c\x0e\x63\x2d\x01\x42\x87\xbf\x14\x40\x16\x0e\x2f\x86\x53\x44\x9c\x86\x71\xb9\xa0\x59\xcc\xd7\xdd\x0e\xc0\x1e\x13\x74\x31\xe4\x3a\x55\x5d\x5e\x74\x32\x07\xa3\xe0\x87\xa5\x52\xe1\x94\x50\x59\x38\x95\x54\x3c\x30\x42\x8d\x43\xa1\xd3\x8a\xab\x1b\xab\xe7\x5c\xcd\xb0\xbc\x3f\xa7\x4b\x96\x32\x6b\xf7\xa0\x10\x2a\xd0\x81\x7d\x14\x4a\x68\xc9\x45\xd9\x69\xc7\xc6\xee\x20\x71\xdd\x6e\x1e\x9c\x44\x65\x1f\x1d\x26\xa7\x4e\xe2\x9c\x49\xc2\x41\x79\x7d\x03\xa1\x97\x19\x26\xb4\xc3\xc9\xe8\xbc\xb0\x3f\x8c\xb9\x5e\xa0\x6b\x9c\xd9\x94\x59\x3d\x1b\x33\xba\x62\x2e\x61\xa5\x58\x3e\x32\x15\x1d\x83\x3f\x85\xe6\x6b\x44\x89\x6c\xc9\xe0\xce\xeb\x12\xeb\x58\x05\xa8\xdf\xcf\xb3\x5a\x40\x1c\xf2\x54\x61\x96\x52\x61\xb0\x76\xc0\x24\x85\x9c\x07\xc9\xc0\xd8\xe1\xf6\x00\x19\x39\x83\x78\xc9\x84\x54\x25\x75\xab\x82\x51\xc6\x85\x42\x09\xce\xb2\x32\xcc\x97\x62\x1f\x83\xb1\x0e\xec\xd6\x57\x1e\xe4\xef\xde\x47\x33\x5f\x6b\xe0\x4e\x7a\x3d\x80\xb3\xb0\x24\x94\x0e\xcb\xad\xa4\xd6\x24\x58\x0c\xd0\x51\x98\x2e\xb6\x5a\x03\x74\x74\xc3\x15\xfa\x82\x8e\x2e\xfe\xd0\x38\x96\xe6\x5f\x13\xba\x6c\xe7\xcf\x3b\xd8\x3c\xfa\x8a\xbe\xee\xb5\xd0\x9c\xd7\xad\x7a\x6f\x42\x25\xd7\x02\x8a\x33\xf0\x18\x1b\x54\x76\x19\x7e\x9e\x06\x01\x34\x8b\x20\x98\x56\x48\x3f\x16\x3c\x83\x2e\xc7\xa8\xdc\xe9\x94\xfb\x90\x83\x91\xff\xcf\x93\xf0\x1a\x49\x8e\xb6\xf4\xc9\x93\xad\x74\x02\x27\xed\x7f\xf4\xe4\xc7\x31\x04\x29\xa1\xa9\x02\xee\x60\x05\x59\xad\xc2\x73\x31\x73\x9b\x93\x99\xa0\x78\x51\x5b\x46\xe8\x92\xd1\x78\x01\x3c\xc3\x0a\x18\x38\xd7\x8a\x06\x40\x80\x63\xfc\x80\x59\x8c\xe7\x2c\x66\x6a\x3d\x78\xe2\x29\xed\xbf\xd8\x72\xc4\xa5\xaa\xc4\xc4\x34\x60\x03\x1c\xf2\xb5\x64\x2b\x2d\xb0\xdf\xfa\x8a\xdf\x35\xfe\xc6\x12\x9d\x8c\x29\xe4\xce\x84\xef\xec\xe4\xa4\x2e\x03\x91\x01\x99\xdf\x29\x8e\x55\xb4\xde\x8a\x9e\x56\x44\xf3\x8c\x4e\x2c\xf7\x85\x89\x14\x3a\x1a\x2d\x1b\xf6\x54\xe2\x6f\xc3\xea\x00\xe5\x16\xd6\xa1\x30\x19\xfa\x48\x55\xa8\xd4\x8e\x80\xb9\x9b\x63\x58\x6d\x08\x08\x10\xc6\x6b\x3c\x5d\xec\xed\x56\xb7\xb5\xdf\xa6\x6e\x16\x1b\xb0\x5b\x69\xcb\xf1\x1b\x7e\x87\x63\x4d\x2b\x8d\xa5\xab\x2a\xaa\x35\x5a\x2f\x91\x6d\x1f\x08\x1a\x43\xd8\x56\x41\x2e\x0e\x9b\xcd\x71\x11\x39\xf3\xe1\xf9\xb9\x26\x63\x1b\xc2\x68\x11\xec\x69\x19\x3e\xcf\x52\xe9\x08\x56\x67\xd6\x84\x6b\x05\xed\x76\xcc\x63\x46\xd6\x01\xba\xbe\xbd\x9a\x8d\xee\xc2\xab\xdb\x8b\x9a\x24\x18\x99\x50\xc2\xc5\xa2\xa1\xb6\xca\x55\x30\x9d\xdc\x35\xae\x43\x18\x67\x57\x86\x8f\xde\xaa\xa3\xeb\x30\xa2\xe4\x7e\x08\x4d\x83\x27\x6d\x40\x2f\xa1\x0c\xb5\xa0\xb3\x48\x50\x19\x71\x53\x4e\xa7\xbb\xb1\xc1\xb6\x90\xd0\x0c\x4f\x8d\x49\xb3\x49\xf8\x8c\x15\x89\x82\xc0\x4a\xed\xcd\x86\x95\xca\xa9\x39\xd5\x73\xf4\x66\x93\x47\xfa\x79\x60\x46\x8e\x01\xa9\xf5\x2c\xab\xe1\x9d\x3d\xa6\x43\xd3\x94\xeb\x55\x64\xc7\x14\x69\xc6\x9f\xb4\x7c\xb4\x79\x29\xb5\x50\xdf\x43\xd3\xf5\x96\xaf\x29\xd4\x28\x71\x40\x86\xe3\xdb\x5b\x05\x7d\xe8\x09\xd7\x1a\xe0\x54\xc1\x37\xa9\x60\xf0\x44\x53\x9c\x64\x31\xad\xf7\x54\x68\x08\x8c\x9b\xa8\x9d\xf9\x09\xb8\x78\x80\x0a\xb0\x16\x9d\x04\xf4\x83\x33\xbf\x19\xef\x22\xde\xdd\xb5\x87\x3c\xc9\xb0\x60\x92\xa7\x9f\x20\xa6\x58\x71\x38\x0e\xae\xa8\x94\xb3\x08\xa7\x5b\x23\xf5\x98\x85\xa4\x74\x68\x36\x15\xea\x6e\xac\xf6\x49\xcd\xa0\x71\xca\x36\xd5\x3c\x64\xee\x20\x6a\x69\x23\xb6\xf6\x3b\x8e\xac\xb2\xa5\x3c\xfd\x7b\x2c\x79\x54\x71\x15\x9c\xcf\xe9\x5e\xee\xfe\x25\xa6\xda\xdb\xc4\x80\x64\x7a\xa0\x8d\x6f\x59\xe7\x4d\x9d\xb4\x00\x14\x95\xa4\x11\x93\x28\x62\xab\xe8\x9f\xe5\x6c\x7e\xbc\x35\xf2\xf5\xc3\xcb\xe9\xfa\xeb\xc9\x5e\x7e\x7e\x84\x13\x1b\xd2\xfe\x83\xa2\x26\x6d\x09\x17\xeb\xff\x8a\xa5\x89\xf5\xfe\x1d\x44\x75\x70\x5f\x93\xab\xb5\x00\xfc\xa0\xeb\xff\xb9\xa3\x6e\x39\x78\xc5\x57\xf2\xa3\xe0\x3a\x6b\xe4\xaa\x59\xb5\x7f\xad\xc8\x5e\xa2\x16\x82\xa5\xb1\xd4\xcd\x7d\x0a\x93\xfb\x1a\xd6\x09\x55\x30\x64\x43\xc4\x46\xe9\x39\x5e\x43\x72\x7f\xfa\xf0\xb3\x77\xb3\x9a\xf0\xb8\x79\x8e\x1c\x85\xd7\x41\x60\x56\xf7\x22\x32\x42\xa5\xca\x81\x3b\xc9\x20\x7f\x06\x18\xbc\xd9\xf8\xd8\xfc\x69\x71\x8c\x55\x14\xa0\xf7\x3e\x3f\xa4\xd4\x89\x45\xe5\x26\xbe\x73\x4e\x74\x62\xef\x09\x7f\xd6\x92\xb0\x69\x1c\xe3\xfa\xa6\x10\xec\x65\xab\x1f\xa0\x2f\x9b\x96\x51\x0f\xe4\x2e\x96\x4b\x4a\x8c\x50\x3f\x8c\x63\xfe\xd8\x7f\xd7\x2e\x3a\x86\x1b\x3f\x61\x19\x8e\x41\x7a\x03\x1e\xdc\xf6\x8c\x7d\xd4\xb7\x77\xb0\x04\xc3\xcd\x0b\x3f\xca\x63\xc2\x13\xb8\xca\x3e\x77\xd8\x72\xe4\x77\xba\x52\xc9\x60\xb7\xe3\x7e\xe9\x02\x5d\xfe\x3d\xd7\x3f\x57\x02\x69\x42\xd5\x72\xa7\x74\x61\x7c\x51\x7e\x4a\xc6\x77\x79\x68\x44\xd8\x11\xe5\xfc\x19\xcb\x84\xf9\xec\xe4\xf4\x6c\x70\x7a\x32\x38\xfd\xa5\x2b\xd6\x07\xa6\xef\x05\x69\x6c\x48\xc1\x5e\x59\x2b\x4f\xc9\x59\x10\x6a\x15\x71\xc1\x9e\xe8\x94\x12\x2d\xe0\xae\x6d\xab\x70\x94\xae\xa0\xcb\xc9\x03\x9c\x6e\x0d\xb9\x43\x62\x4e\xdf\x1e\xac\x14\x63\xd3\xd6\x63\x8e\x17\x73\x38\x8b\x80\x88\xe9\x0a\xac\x14\x8f\x43\xa3\xe2\x3d\xf3\x52\xf0\xe4\x0a\x84\x7e\xb3\x42\x54\xfc\x4d\xfb\xaf\x81\x72\x52\xc5\xf8\x99\xa9\xe8\x15\x31\x16\x31\x98\x61\xb1\xa2\x4a\xbe\xca\x8e\x9d\x2d\x9b\xdd\xd7\x34\xe8\xee\x91\xaf\x12\xcd\x62\xb7\x7b\x4d\x7d\x3d\xa0\x16\x8a\x17\x37\x53\x40\x6f\xdb\x4d\x3e\xb7\xb4\xa6\x5e\xef\x93\x56\x99\x56\x95\x07\x3b\xff\x58\xa9\x3f\x2a\x87\xd7\x48\x80\x48\xeb\x73\x78\xf9\x9c\x2e\x99\x3c\xe0\x64\xf5\x7c\x81\x3a\x70\x24\x75\x0f\xd5\xf6\x79\xd5\xa8\x3e\x1a\x55\x14\x83\x2e\x5a\x79\x67\xae\x3f\x1d\xd4\x9c\xb4\x3c\x49\x76\x7b\x84\x76\x7b\xc8\xee\xf6\xbd\xf0\xec\x79\xb4\xe9\xc6\xb0\x7b\xb9\x2f\xde\xe7\x17\x85\xa3\x56\x6c\x5d\xef\x65\x7f\x01\x4d\x7c\x1d\x0c\x5a\x1a\x00\x00")
 
 func servicesWorkerCloudformationStackYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -464,7 +464,7 @@ func servicesWorkerCloudformationStackYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "services/worker/cloudformation/stack.yaml", size: 5168, mode: os.FileMode(420), modTime: time.Unix(1505192101, 0)}
+	info := bindataFileInfo{name: "services/worker/cloudformation/stack.yaml", size: 6746, mode: os.FileMode(420), modTime: time.Unix(1792382979, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
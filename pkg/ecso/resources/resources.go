@@ -3,7 +3,6 @@ package resources
 //go:generate go-bindata -ignore=.*node_modules -pkg $GOPACKAGE -o resources-generated.go ./...
 
 import (
-	"fmt"
 	"io"
 	"path/filepath"
 	"text/template"
@@ -12,30 +11,9 @@ import (
 )
 
 var (
-	InstanceDrainerLambdaVersion = "1.0.0"
-
-	ServiceDiscoveryLambdaVersion = "1.0.0"
-
-	DNSCleanerLambdaVersion = "1.0.0"
-
 	EnvironmentFiles = environmentFiles()
-
-	WebService = ServiceResources{
-		ComposeFile:            NewTextFile(MustParseTemplateAsset("docker-compose.yaml", "services/web/docker-compose.yaml")),
-		CloudFormationTemplate: NewTextFile(MustParseTemplateAsset("stack.yaml", "services/web/cloudformation/stack.yaml")),
-	}
-
-	WorkerService = ServiceResources{
-		ComposeFile:            NewTextFile(MustParseTemplateAsset("docker-compose.yaml", "services/worker/docker-compose.yaml")),
-		CloudFormationTemplate: NewTextFile(MustParseTemplateAsset("stack.yaml", "services/worker/cloudformation/stack.yaml")),
-	}
 )
 
-type ServiceResources struct {
-	ComposeFile            Resource
-	CloudFormationTemplate Resource
-}
-
 func environmentFiles() []Resource {
 	files := environmentCfnTemplates()
 	files = append(files, environmentLambdas()...)
@@ -43,25 +21,27 @@ func environmentFiles() []Resource {
 	return files
 }
 
+// environmentLambdas are the sources of the environment's lambda functions.
+// They are packaged by PackageLambdas when the environment is deployed
 func environmentLambdas() []Resource {
-	zip := func(name, version string) string {
-		return fmt.Sprintf("%s/lambda/%s-%s.zip", ecso.EnvironmentResourceDir, name, version)
-	}
-
-	src := func(lambda, path string) string {
-		return filepath.Join("environment", "lambda", lambda, path)
+	lambdas := map[string][]string{
+		"dns-cleaner":       {"index.js"},
+		"instance-drainer":  {"index.py"},
+		"service-discovery": {"index.js"},
 	}
 
-	return []Resource{
-		NewZipFile(zip("instance-drainer", InstanceDrainerLambdaVersion),
-			MustParseTemplateAsset("index.py", src("instance-drainer", "index.py"))),
+	files := make([]Resource, 0)
 
-		NewZipFile(zip("service-discovery", ServiceDiscoveryLambdaVersion),
-			MustParseTemplateAsset("index.js", src("service-discovery", "index.js"))),
+	for _, lambda := range []string{"dns-cleaner", "instance-drainer", "service-discovery"} {
+		for _, file := range lambdas[lambda] {
+			src := filepath.Join("environment", "lambda", lambda, file)
+			dst := filepath.Join(LambdaDir, lambda, file)
 
-		NewZipFile(zip("dns-cleaner", DNSCleanerLambdaVersion),
-			MustParseTemplateAsset("index.js", src("dns-cleaner", "index.js"))),
+			files = append(files, NewTextFile(MustParseTemplateAsset(dst, src)))
+		}
 	}
+
+	return files
 }
 
 func environmentCfnTemplates() []Resource {
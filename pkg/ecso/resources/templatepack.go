@@ -0,0 +1,308 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+const (
+	// TemplatePackManifestFile is the name of the manifest file at the root
+	// of a template pack directory
+	TemplatePackManifestFile = "template.json"
+
+	// BuiltInTemplatePackSource is reported as the source of the template
+	// packs that are compiled in to ecso
+	BuiltInTemplatePackSource = "built-in"
+)
+
+var (
+	// BuiltInTemplatePacks are the template packs that are compiled in to
+	// ecso. "web" is used by default for services with a route, and "worker"
+	// for all other services
+	BuiltInTemplatePacks = []*TemplatePack{
+		{
+			Name:        "web",
+			Description: "A service registered with the environment's load balancer",
+			Web:         true,
+			Source:      BuiltInTemplatePackSource,
+			Files: []Resource{
+				NewTextFile(MustParseTemplateAsset("docker-compose.yaml", "services/web/docker-compose.yaml")),
+				NewTextFile(MustParseTemplateAsset(filepath.Join("cloudformation", "stack.yaml"), "services/web/cloudformation/stack.yaml")),
+			},
+		},
+		{
+			Name:        "worker",
+			Description: "A service that is not exposed by the load balancer",
+			Source:      BuiltInTemplatePackSource,
+			Files: []Resource{
+				NewTextFile(MustParseTemplateAsset("docker-compose.yaml", "services/worker/docker-compose.yaml")),
+				NewTextFile(MustParseTemplateAsset(filepath.Join("cloudformation", "stack.yaml"), "services/worker/cloudformation/stack.yaml")),
+			},
+		},
+	}
+
+	// requiredTemplatePackFiles are the files that every template pack must
+	// provide, relative to the service dir
+	requiredTemplatePackFiles = []string{
+		"docker-compose.yaml",
+		filepath.Join("cloudformation", "stack.yaml"),
+	}
+)
+
+// TemplatePack is a set of templates used to create the files for a new
+// service. Packs other than the built-in ones are loaded from a directory
+// containing a template.json manifest. Every other file in the directory is
+// parsed as a text/template and written to the same relative path in the
+// service dir, with the new Service, the Project and the pack's Variables as
+// data
+type TemplatePack struct {
+	// Name identifies the pack. Packs on the project's TemplatePath are
+	// found by name
+	Name string
+
+	Description string `json:",omitempty"`
+
+	// Web packs register the service with the environment's load balancer,
+	// and need a route and port
+	Web bool `json:",omitempty"`
+
+	// Variables are values that the user is prompted for when the pack is
+	// used, and which are available to the templates as .Variables.NAME
+	Variables []*TemplateVariable `json:",omitempty"`
+
+	// Source is the directory the pack was loaded from
+	Source string `json:",omitempty"`
+
+	// Files are the resources written to the service dir
+	Files []Resource `json:"-"`
+}
+
+// TemplateVariable is a value used by the templates in a pack
+type TemplateVariable struct {
+	Name    string
+	Prompt  string `json:",omitempty"`
+	Default string `json:",omitempty"`
+}
+
+// LoadTemplatePack loads a template pack from a directory
+func LoadTemplatePack(dir string) (*TemplatePack, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, TemplatePackManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a template pack. %s", dir, err.Error())
+	}
+
+	pack := &TemplatePack{}
+
+	if err := json.Unmarshal(data, pack); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s. %s", filepath.Join(dir, TemplatePackManifestFile), err.Error())
+	}
+
+	if pack.Name == "" {
+		pack.Name = filepath.Base(dir)
+	}
+
+	pack.Source = dir
+
+	for _, v := range pack.Variables {
+		if v.Name == "" {
+			return nil, fmt.Errorf("Template pack %s has a variable with no name", pack.Name)
+		}
+	}
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == TemplatePackManifestFile {
+			return err
+		}
+
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.New(name).Parse(string(body))
+		if err != nil {
+			return fmt.Errorf("Failed to parse template %s. %s", path, err.Error())
+		}
+
+		pack.Files = append(pack.Files, NewTextFile(tmpl))
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, required := range requiredTemplatePackFiles {
+		if !pack.HasFile(required) {
+			return nil, fmt.Errorf("Template pack %s does not contain %s", pack.Name, required)
+		}
+	}
+
+	return pack, nil
+}
+
+// GetBuiltInTemplatePack returns the built-in template pack with the given
+// name, or nil if there is no such pack
+func GetBuiltInTemplatePack(name string) *TemplatePack {
+	for _, pack := range BuiltInTemplatePacks {
+		if pack.Name == name {
+			return pack
+		}
+	}
+
+	return nil
+}
+
+// FindTemplatePack returns the template pack in dir, if it is a directory,
+// otherwise the built-in pack or first pack on the search path with a
+// matching name
+func FindTemplatePack(nameOrDir string, searchPath []string) (*TemplatePack, error) {
+	if info, err := os.Stat(nameOrDir); err == nil && info.IsDir() {
+		return LoadTemplatePack(nameOrDir)
+	}
+
+	packs, err := ListTemplatePacks(searchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pack := range packs {
+		if pack.Name == nameOrDir {
+			return pack, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No template pack named '%s' was found. Run `ecso templates ls` to list the available packs", nameOrDir)
+}
+
+// ListTemplatePacks returns the built-in template packs, followed by the
+// packs found in each directory of the search path. Packs with the same name
+// as a pack earlier in the list are ignored
+func ListTemplatePacks(searchPath []string) (TemplatePackList, error) {
+	var (
+		packs = append(TemplatePackList{}, BuiltInTemplatePacks...)
+		seen  = make(map[string]bool)
+	)
+
+	for _, pack := range packs {
+		seen[pack.Name] = true
+	}
+
+	for _, dir := range searchPath {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			packDir := filepath.Join(dir, entry.Name())
+
+			if _, err := os.Stat(filepath.Join(packDir, TemplatePackManifestFile)); err != nil {
+				continue
+			}
+
+			pack, err := LoadTemplatePack(packDir)
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[pack.Name] {
+				continue
+			}
+
+			seen[pack.Name] = true
+			packs = append(packs, pack)
+		}
+	}
+
+	return packs, nil
+}
+
+// HasFile returns true if the pack writes a file at the path relative to the
+// service dir
+func (p *TemplatePack) HasFile(name string) bool {
+	for _, f := range p.Files {
+		if filepath.Clean(f.Filename()) == filepath.Clean(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasVariable returns true if the pack declares a variable with the name
+func (p *TemplatePack) HasVariable(name string) bool {
+	for _, v := range p.Variables {
+		if v.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveVariables returns the value of each of the pack's variables, taken
+// from values, or the variable's default if it has no value
+func (p *TemplatePack) ResolveVariables(values map[string]string) map[string]string {
+	resolved := make(map[string]string)
+
+	for _, v := range p.Variables {
+		if value, ok := values[v.Name]; ok {
+			resolved[v.Name] = value
+		} else {
+			resolved[v.Name] = v.Default
+		}
+	}
+
+	return resolved
+}
+
+// TemplatePackList is a list of template packs that can be rendered
+type TemplatePackList []*TemplatePack
+
+func (l TemplatePackList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("NAME|TYPE|SOURCE|DESCRIPTION"))
+
+	for _, pack := range l {
+		kind := "worker"
+
+		if pack.Web {
+			kind = "web"
+		}
+
+		tw.Write([]byte(fmt.Sprintf("%s|%s|%s|%s", pack.Name, kind, pack.Source, pack.Description)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
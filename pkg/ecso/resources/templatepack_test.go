@@ -0,0 +1,147 @@
+package resources
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestTemplatePack(t *testing.T, dir string, files map[string]string) {
+	for name, body := range files {
+		filename := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filename, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadTemplatePack(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-templatepack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	packDir := filepath.Join(dir, "sidecar")
+
+	writeTestTemplatePack(t, packDir, map[string]string{
+		TemplatePackManifestFile:       `{"Description": "Web service with a sidecar", "Web": true, "Variables": [{"Name": "LogLevel", "Default": "info"}]}`,
+		"docker-compose.yaml":          "service: {{.Service}} level: {{.Variables.LogLevel}}",
+		"cloudformation/stack.yaml":    "Parameters: {}",
+		"cloudformation/policies.yaml": "Resources: {}",
+		".git/config":                  "{{ not a template",
+	})
+
+	pack, err := LoadTemplatePack(packDir)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	if pack.Name != "sidecar" || !pack.Web || pack.Source != packDir {
+		t.Errorf("Unexpected pack %+v", pack)
+	}
+
+	if len(pack.Files) != 3 {
+		t.Errorf("Want 3 files, got %d", len(pack.Files))
+	}
+
+	if !pack.HasFile("cloudformation/policies.yaml") {
+		t.Errorf("Expected pack to contain cloudformation/policies.yaml")
+	}
+
+	vars := pack.ResolveVariables(map[string]string{"Unknown": "x"})
+
+	if len(vars) != 1 || vars["LogLevel"] != "info" {
+		t.Errorf("Unexpected variables %v", vars)
+	}
+
+	var buf bytes.Buffer
+
+	for _, f := range pack.Files {
+		if f.Filename() == "docker-compose.yaml" {
+			if err := f.WriteTo(&buf, map[string]interface{}{"Service": "api", "Variables": vars}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if want := "service: api level: info"; buf.String() != want {
+		t.Errorf("Want %q, got %q", want, buf.String())
+	}
+}
+
+func TestLoadTemplatePackRequiresServiceFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-templatepack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestTemplatePack(t, dir, map[string]string{
+		TemplatePackManifestFile: `{"Name": "broken"}`,
+		"docker-compose.yaml":    "",
+	})
+
+	if _, err := LoadTemplatePack(dir); err == nil {
+		t.Errorf("Expected an error for a pack with no cloudformation template")
+	}
+}
+
+func TestListTemplatePacks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-templatepack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestTemplatePack(t, filepath.Join(dir, "house"), map[string]string{
+		TemplatePackManifestFile:    `{}`,
+		"docker-compose.yaml":       "",
+		"cloudformation/stack.yaml": "",
+	})
+
+	writeTestTemplatePack(t, filepath.Join(dir, "web"), map[string]string{
+		TemplatePackManifestFile:    `{}`,
+		"docker-compose.yaml":       "",
+		"cloudformation/stack.yaml": "",
+	})
+
+	writeTestTemplatePack(t, filepath.Join(dir, "not-a-pack"), map[string]string{
+		"README.md": "",
+	})
+
+	packs, err := ListTemplatePacks([]string{dir, filepath.Join(dir, "missing")})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, pack := range packs {
+		names = append(names, pack.Name+":"+pack.Source)
+	}
+
+	want := []string{"web:built-in", "worker:built-in", "house:" + filepath.Join(dir, "house")}
+
+	if len(names) != len(want) {
+		t.Fatalf("Want %v, got %v", want, names)
+	}
+
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Want %v, got %v", want, names)
+		}
+	}
+
+	pack, err := FindTemplatePack("house", []string{dir})
+	if err != nil || pack.Name != "house" {
+		t.Errorf("Expected to find the house pack, got %v, %v", pack, err)
+	}
+}
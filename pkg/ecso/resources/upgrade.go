@@ -0,0 +1,256 @@
+package resources
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bernos/ecso/pkg/ecso"
+	"github.com/bernos/ecso/pkg/ecso/ui"
+)
+
+// UpgradeStatus describes what happened to a file when it was upgraded
+type UpgradeStatus string
+
+const (
+	// UpgradeAdded files did not exist, and have been created
+	UpgradeAdded UpgradeStatus = "added"
+
+	// UpgradeUpdated files had not been edited, and have been replaced
+	// with the new version
+	UpgradeUpdated UpgradeStatus = "updated"
+
+	// UpgradeUnchanged files are the same in the new version, so any
+	// edits have been kept as they are
+	UpgradeUnchanged UpgradeStatus = "unchanged"
+
+	// UpgradeMerged files had been edited, and the changes in the new
+	// version have been merged in without conflicts
+	UpgradeMerged UpgradeStatus = "merged"
+
+	// UpgradeConflict files contain conflict markers where edits and
+	// changes in the new version overlap. They must be resolved by hand
+	UpgradeConflict UpgradeStatus = "conflict"
+
+	// UpgradeUntracked files were generated before ecso recorded pristine
+	// copies, so could not be merged. The new version is written next to
+	// the file with a .upgrade extension, for merging by hand
+	UpgradeUntracked UpgradeStatus = "untracked"
+)
+
+// UpgradeResult is the outcome of upgrading a single file
+type UpgradeResult struct {
+	File   string
+	Status UpgradeStatus
+}
+
+// UpgradeResultList is the outcome of upgrading a set of files
+type UpgradeResultList []*UpgradeResult
+
+// HasConflicts returns true if any file needs to be merged by hand
+func (l UpgradeResultList) HasConflicts() bool {
+	for _, r := range l {
+		if r.Status == UpgradeConflict || r.Status == UpgradeUntracked {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l UpgradeResultList) WriteTo(w io.Writer) (int64, error) {
+	tw := ui.NewTableWriter(w, "|")
+	tw.WriteHeader([]byte("FILE|STATUS"))
+
+	for _, r := range l {
+		tw.Write([]byte(fmt.Sprintf("%s|%s", r.File, r.Status)))
+	}
+
+	n, err := tw.Flush()
+
+	return int64(n), err
+}
+
+// NewTrackingResourceWriter creates a ResourceWriter that writes resources to
+// dir, and also records a pristine copy of each file in the project's
+// GeneratedFilesDir, so that they can later be upgraded by UpgradeResources
+func NewTrackingResourceWriter(projectDir, dir string) ResourceWriter {
+	return &trackingResourceWriter{
+		projectDir: projectDir,
+		dir:        dir,
+	}
+}
+
+type trackingResourceWriter struct {
+	projectDir string
+	dir        string
+}
+
+func (w *trackingResourceWriter) WriteResource(r Resource, data interface{}) error {
+	var buf bytes.Buffer
+
+	if err := r.WriteTo(&buf, data); err != nil {
+		return err
+	}
+
+	filename := filepath.Join(w.dir, r.Filename())
+
+	if err := writeFile(filename, buf.Bytes()); err != nil {
+		return err
+	}
+
+	return writeFile(pristineFilename(w.projectDir, filename), buf.Bytes())
+}
+
+func (w *trackingResourceWriter) WriteResources(data interface{}, rs ...Resource) error {
+	for _, r := range rs {
+		if err := w.WriteResource(r, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpgradeResources writes the current version of each resource to dir,
+// keeping any edits made to the previously generated files. Edits are merged
+// with the changes between the pristine copy recorded when the file was
+// last generated and the current version. If dryRun is true the results are
+// reported, but no files are written
+func UpgradeResources(projectDir, dir string, data interface{}, dryRun bool, rs ...Resource) (UpgradeResultList, error) {
+	results := make(UpgradeResultList, 0, len(rs))
+
+	for _, r := range rs {
+		result, err := upgradeResource(projectDir, dir, r, data, dryRun)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func upgradeResource(projectDir, dir string, r Resource, data interface{}, dryRun bool) (*UpgradeResult, error) {
+	var (
+		filename = filepath.Join(dir, r.Filename())
+		pristine = pristineFilename(projectDir, filename)
+		upgrade  bytes.Buffer
+		result   = &UpgradeResult{}
+	)
+
+	if rel, err := filepath.Rel(projectDir, filename); err == nil {
+		result.File = rel
+	} else {
+		result.File = filename
+	}
+
+	if err := r.WriteTo(&upgrade, data); err != nil {
+		return nil, err
+	}
+
+	current, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := readFile(pristine)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []byte
+
+	switch {
+	case current == nil:
+		result.Status, merged = UpgradeAdded, upgrade.Bytes()
+
+	case bytes.Equal(current, upgrade.Bytes()):
+		result.Status = UpgradeUnchanged
+
+	case isBinary(r):
+		// Binary files can't be merged, and ecso always generates them
+		// with a versioned filename, so keep the existing file
+		result.Status = UpgradeUnchanged
+
+	case base == nil:
+		result.Status = UpgradeUntracked
+
+	case bytes.Equal(base, upgrade.Bytes()):
+		result.Status = UpgradeUnchanged
+
+	case bytes.Equal(base, current):
+		result.Status, merged = UpgradeUpdated, upgrade.Bytes()
+
+	default:
+		body, conflicts := Merge3(string(base), string(current), upgrade.String())
+
+		merged = []byte(body)
+		result.Status = UpgradeMerged
+
+		if conflicts {
+			result.Status = UpgradeConflict
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if merged != nil {
+		if err := writeFile(filename, merged); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Status == UpgradeUntracked {
+		if err := writeFile(filename+".upgrade", upgrade.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, writeFile(pristine, upgrade.Bytes())
+}
+
+// pristineFilename returns the path that the pristine copy of a generated
+// file is recorded at. Paths mirror the file's path relative to the project
+// dir, without the leading .ecso dir
+func pristineFilename(projectDir, filename string) string {
+	rel, err := filepath.Rel(projectDir, filename)
+	if err != nil {
+		rel = filepath.Base(filename)
+	}
+
+	if parts := strings.SplitN(rel, string(filepath.Separator), 2); len(parts) == 2 && parts[0] == filepath.Dir(ecso.GeneratedFilesDir) {
+		rel = parts[1]
+	}
+
+	return filepath.Join(projectDir, ecso.GeneratedFilesDir, rel)
+}
+
+func isBinary(r Resource) bool {
+	_, ok := r.(*zipFile)
+	return ok
+}
+
+// readFile returns the contents of a file, or nil if it does not exist
+func readFile(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+func writeFile(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestUpgradeResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		serviceDir = filepath.Join(dir, "services", "web")
+		data       = struct{ Name string }{"web"}
+	)
+
+	files := func(body map[string]string) []Resource {
+		rs := make([]Resource, 0, len(body))
+		for _, name := range []string{"edited.yaml", "untouched.yaml", "conflict.yaml", "new.yaml"} {
+			if b, ok := body[name]; ok {
+				rs = append(rs, NewTextFile(template.Must(template.New(name).Parse(b))))
+			}
+		}
+		return rs
+	}
+
+	original := files(map[string]string{
+		"edited.yaml":    "name: {{.Name}}\nports: 80\nimage: web\nmemory: 128\n",
+		"untouched.yaml": "a: 1\n",
+		"conflict.yaml":  "cpu: 128\n",
+	})
+
+	if err := NewTrackingResourceWriter(dir, serviceDir).WriteResources(data, original...); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestTemplatePack(t, serviceDir, map[string]string{
+		"edited.yaml":   "name: web\nports: 8080\nimage: web\nmemory: 128\n",
+		"conflict.yaml": "cpu: 256\n",
+	})
+
+	upgraded := files(map[string]string{
+		"edited.yaml":    "name: {{.Name}}\nports: 80\nimage: web\nmemory: 256\n",
+		"untouched.yaml": "a: 2\n",
+		"conflict.yaml":  "cpu: 512\n",
+		"new.yaml":       "b: 1\n",
+	})
+
+	results, err := UpgradeResources(dir, serviceDir, data, false, upgraded...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]UpgradeStatus{
+		"edited.yaml":    UpgradeMerged,
+		"untouched.yaml": UpgradeUpdated,
+		"conflict.yaml":  UpgradeConflict,
+		"new.yaml":       UpgradeAdded,
+	}
+
+	for _, result := range results {
+		name := filepath.Base(result.File)
+		if result.Status != expect[name] {
+			t.Errorf("Expected %s to be %s, got %s", name, expect[name], result.Status)
+		}
+	}
+
+	if !results.HasConflicts() {
+		t.Errorf("Expected results to have conflicts")
+	}
+
+	edited, _ := ioutil.ReadFile(filepath.Join(serviceDir, "edited.yaml"))
+	if string(edited) != "name: web\nports: 8080\nimage: web\nmemory: 256\n" {
+		t.Errorf("Unexpected merge result %q", edited)
+	}
+
+	conflict, _ := ioutil.ReadFile(filepath.Join(serviceDir, "conflict.yaml"))
+	if !strings.Contains(string(conflict), conflictStartMarker) {
+		t.Errorf("Expected conflict markers in %q", conflict)
+	}
+
+	pristine, _ := ioutil.ReadFile(pristineFilename(dir, filepath.Join(serviceDir, "untouched.yaml")))
+	if string(pristine) != "a: 2\n" {
+		t.Errorf("Expected pristine copy to be upgraded, got %q", pristine)
+	}
+}
+
+func TestUpgradeResourcesUntracked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecso-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestTemplatePack(t, dir, map[string]string{
+		"stack.yaml": "edited\n",
+	})
+
+	r := NewTextFile(template.Must(template.New("stack.yaml").Parse("upgraded\n")))
+
+	results, err := UpgradeResources(dir, dir, nil, true, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].Status != UpgradeUntracked {
+		t.Errorf("Expected untracked, got %s", results[0].Status)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "stack.yaml.upgrade")); !os.IsNotExist(err) {
+		t.Errorf("Expected dry run not to write any files")
+	}
+}
@@ -2,15 +2,26 @@ package resources
 
 import (
 	"archive/zip"
+	"bytes"
 	"io"
+	"sort"
 	"text/template"
+	"time"
 )
 
+// zipModified is the modification time recorded for every file in a zip.
+// Using a fixed time means that zipping the same files always produces the
+// same bytes
+var zipModified = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 type zipFile struct {
 	file    string
 	entries []*template.Template
 }
 
+// NewZipFile creates a Resource that renders each of the entries to a file in
+// a zip archive. The archive is deterministic, so rendering the same entries
+// with the same data always produces an identical zip
 func NewZipFile(file string, entries ...*template.Template) Resource {
 	return &zipFile{
 		file:    file,
@@ -23,15 +34,50 @@ func (z *zipFile) Filename() string {
 }
 
 func (z *zipFile) WriteTo(w io.Writer, data interface{}) error {
-	zipWriter := zip.NewWriter(w)
+	entries := make([]*zipEntry, 0, len(z.entries))
 
 	for _, tmpl := range z.entries {
-		f, err := zipWriter.Create(tmpl.Name())
+		var buf bytes.Buffer
+
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return err
+		}
+
+		entries = append(entries, &zipEntry{name: tmpl.Name(), body: buf.Bytes()})
+	}
+
+	return writeZip(w, entries)
+}
+
+type zipEntry struct {
+	name string
+	body []byte
+}
+
+// writeZip writes the entries to a zip archive in name order, with fixed
+// modification times and permissions
+func writeZip(w io.Writer, entries []*zipEntry) error {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	zipWriter := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:   entry.name,
+			Method: zip.Deflate,
+		}
+
+		header.SetModTime(zipModified)
+		header.SetMode(0644)
+
+		f, err := zipWriter.CreateHeader(header)
 		if err != nil {
 			return err
 		}
 
-		if err := tmpl.Execute(f, data); err != nil {
+		if _, err := f.Write(entry.body); err != nil {
 			return err
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/amazon-ecs-cli/ecs-cli/modules/compose/ecs/utils"
 	"github.com/aws/aws-sdk-go/service/ecs"
@@ -21,6 +22,14 @@ func init() {
 	logrus.SetLevel(logrus.PanicLevel)
 }
 
+// ContainerBuild describes how to build the image for a container, as given
+// by the `build` section of a service's compose file
+type ContainerBuild struct {
+	Context    string
+	Dockerfile string
+	Args       map[string]string
+}
+
 // Service is a single deployable ECS service. Services are defined by a
 // docker compose file located in the service source dir, and cloudformation
 // template(s) located under the .ecso project dir
@@ -37,6 +46,15 @@ type Service struct {
 	Port          int
 	Tags          map[string]string
 	Environments  map[string]ServiceConfiguration
+
+	// Template is the name or dir of the template pack that the service
+	// was created from. If empty, the built-in "web" or "worker" pack was
+	// used, depending on whether the service has a Route
+	Template string `json:",omitempty"`
+
+	// TemplateVariables are the values given for the template pack's
+	// variables when the service was created
+	TemplateVariables map[string]string `json:",omitempty"`
 }
 
 // ServiceConfiguration contains environment vars and cloudformation params
@@ -51,6 +69,12 @@ func (s *Service) Dir() string {
 	return filepath.Join(s.project.Dir(), "services", s.Name)
 }
 
+// GetDiscoveryName returns the DNS name that the service is registered under
+// by the environment's service discovery
+func (s *Service) GetDiscoveryName(env *Environment) string {
+	return fmt.Sprintf("%s.%s", s.Name, env.GetServiceDiscoveryDomain())
+}
+
 func (s *Service) GetCloudFormationTemplateDir() string {
 	return filepath.Join(s.Dir(), "cloudformation")
 	// return filepath.Join(s.project.DotDir(), "services", s.Name)
@@ -72,6 +96,12 @@ func (s *Service) GetDeploymentBucketPrefix(env *Environment) string {
 	return path.Join(env.GetBaseBucketPrefix(), "services", s.Name)
 }
 
+// GetTemplatesBucketPrefix is the prefix that the nested templates of the
+// service's stack are uploaded to
+func (s *Service) GetTemplatesBucketPrefix(env *Environment) string {
+	return path.Join(env.GetBaseBucketPrefix(), "templates", "services", s.Name)
+}
+
 func (s *Service) GetCloudWatchLogGroup(env *Environment) string {
 	return env.GetCloudWatchLogGroup()
 }
@@ -84,21 +114,68 @@ func (s *Service) GetECSTaskDefinitionName(env *Environment) string {
 	return fmt.Sprintf("%s-%s-%s", s.project.Name, env.Name, s.Name)
 }
 
+// GetImageRepositoryName returns the name of the ECR repository that images
+// built for the container are pushed to. Repositories are shared by all
+// environments, so that the same image can be promoted between them
+func (s *Service) GetImageRepositoryName(container string) string {
+	return strings.ToLower(path.Join(s.project.Name, s.Name, container))
+}
+
 func (s *Service) GetEnvFile(env *Environment) string {
 	return filepath.Join(path.Dir(s.ComposeFile), fmt.Sprintf(".%s.env", env.Name))
 }
 
 func (s *Service) GetECSTaskDefinition(env *Environment) (*ecs.TaskDefinition, error) {
+	context, p, err := s.parseComposeFile(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ConvertToTaskDefinition(context.ProjectName, context, p.ServiceConfigs)
+}
+
+// GetContainerBuilds returns the build configuration of each container in the
+// service's compose file that has a `build` section, keyed by container name
+func (s *Service) GetContainerBuilds(env *Environment) (map[string]*ContainerBuild, error) {
+	_, p, err := s.parseComposeFile(env)
+	if err != nil {
+		return nil, err
+	}
+
+	builds := make(map[string]*ContainerBuild)
+
+	for name, config := range p.ServiceConfigs.All() {
+		if config.Build.Context == "" {
+			continue
+		}
+
+		context := config.Build.Context
+
+		if !filepath.IsAbs(context) {
+			context = filepath.Join(filepath.Dir(s.ComposeFile), context)
+		}
+
+		builds[name] = &ContainerBuild{
+			Context:    context,
+			Dockerfile: config.Build.Dockerfile,
+			Args:       config.Build.Args,
+		}
+	}
+
+	return builds, nil
+}
+
+func (s *Service) parseComposeFile(env *Environment) (*project.Context, *project.Project, error) {
 	name := s.GetECSTaskDefinitionName(env)
 
 	envLookup, err := s.GetEnvironmentLookup(env)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	resourceLookup, err := s.GetResourceLookup(env)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	context := &project.Context{
@@ -110,10 +187,10 @@ func (s *Service) GetECSTaskDefinition(env *Environment) (*ecs.TaskDefinition, e
 
 	p := project.NewProject(context, nil, nil)
 	if err := p.Parse(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return utils.ConvertToTaskDefinition(name, context, p.ServiceConfigs)
+	return context, p, nil
 }
 
 // SetProject sets the project that the service belongs to
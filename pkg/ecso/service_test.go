@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	lconfig "github.com/docker/libcompose/config"
@@ -100,6 +101,19 @@ func TestGetECSTaskDefinitionName(t *testing.T) {
 		makeTestService().GetECSTaskDefinitionName(env), t)
 }
 
+func TestGetDiscoveryName(t *testing.T) {
+	env := makeTestEnvironment()
+	env.ServiceDiscovery = ServiceDiscoveryCloudMap
+
+	assertEqual("my-service.my-project-test.local",
+		makeTestService().GetDiscoveryName(env), t)
+}
+
+func TestGetImageRepositoryName(t *testing.T) {
+	assertEqual("my-project/my-service/web",
+		makeTestService().GetImageRepositoryName("web"), t)
+}
+
 func TestGetEnvFile(t *testing.T) {
 	env := makeTestEnvironment()
 
@@ -193,6 +207,32 @@ func TestGetECSTaskDefinition(t *testing.T) {
 	}
 }
 
+func TestGetContainerBuilds(t *testing.T) {
+	service := makeTestService()
+	service.ComposeFile = testDir + "/services/my-built-service/docker-compose.yaml"
+
+	builds, err := service.GetContainerBuilds(makeTestEnvironment())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(builds) != 1 {
+		t.Fatalf("Want 1 build, got %d", len(builds))
+	}
+
+	build, ok := builds["app"]
+	if !ok {
+		t.Fatal("Want build for app container")
+	}
+
+	if !strings.HasSuffix(build.Context, filepath.Join("my-built-service", "app")) {
+		t.Errorf("Want context in my-built-service/app, got %s", build.Context)
+	}
+
+	assertEqual("Dockerfile.prod", build.Dockerfile, t)
+	assertEqual("1", build.Args["VERSION"], t)
+}
+
 func TestGetECSTaskDefinitionComposeFileError(t *testing.T) {
 	env := makeTestEnvironment()
 	service := makeTestService()
@@ -15,6 +15,8 @@ const (
 	Warn
 )
 
+// The color package disables all colour codes when stdout is not a terminal,
+// so command results piped to other tools are free of ANSI escape sequences
 var (
 	bold     = color.New(color.Bold).SprintfFunc()
 	warn     = color.New(color.FgRed).SprintfFunc()
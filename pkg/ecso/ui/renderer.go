@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OutputTable renders results as human readable tables and definition lists
+	OutputTable = "table"
+
+	// OutputJSON renders results as indented json
+	OutputJSON = "json"
+
+	// OutputYAML renders results as yaml
+	OutputYAML = "yaml"
+
+	// OutputTemplate renders results using a user supplied go template
+	OutputTemplate = "template"
+)
+
+// Renderer writes the result of a command in a particular output format. The
+// human readable table format is provided by the result's own WriteTo method,
+// so any result type that implements io.WriterTo can be rendered in any of the
+// supported formats
+type Renderer interface {
+	Render(v io.WriterTo) error
+}
+
+// RendererFunc is an adapter to allow the use of ordinary functions as
+// Renderers
+type RendererFunc func(io.WriterTo) error
+
+// Render calls fn
+func (fn RendererFunc) Render(v io.WriterTo) error {
+	return fn(v)
+}
+
+// NewRenderer creates a Renderer that writes to w using the requested output
+// format. If a template is provided and no format is given, the template
+// format is assumed
+func NewRenderer(w io.Writer, format, tmpl string) (Renderer, error) {
+	if format == "" && tmpl != "" {
+		format = OutputTemplate
+	}
+
+	switch format {
+	case "", OutputTable:
+		return NewTableRenderer(w), nil
+	case OutputJSON:
+		return NewJSONRenderer(w), nil
+	case OutputYAML:
+		return NewYAMLRenderer(w), nil
+	case OutputTemplate:
+		return NewTemplateRenderer(w, tmpl)
+	}
+
+	return nil, fmt.Errorf("Unknown output format '%s'. Valid formats are %s, %s, %s and %s", format, OutputTable, OutputJSON, OutputYAML, OutputTemplate)
+}
+
+// NewTableRenderer creates a Renderer that writes results using their own
+// WriteTo method
+func NewTableRenderer(w io.Writer) Renderer {
+	return RendererFunc(func(v io.WriterTo) error {
+		_, err := v.WriteTo(w)
+		return err
+	})
+}
+
+// NewJSONRenderer creates a Renderer that writes results as indented json
+func NewJSONRenderer(w io.Writer) Renderer {
+	return RendererFunc(func(v io.WriterTo) error {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(w, "%s\n", b)
+
+		return err
+	})
+}
+
+// NewYAMLRenderer creates a Renderer that writes results as yaml
+func NewYAMLRenderer(w io.Writer) Renderer {
+	return RendererFunc(func(v io.WriterTo) error {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(b)
+
+		return err
+	})
+}
+
+// NewTemplateRenderer creates a Renderer that executes the go template given
+// by tmpl against each result
+func NewTemplateRenderer(w io.Writer, tmpl string) (Renderer, error) {
+	if tmpl == "" {
+		return nil, fmt.Errorf("A template is required when using the %s output format", OutputTemplate)
+	}
+
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return RendererFunc(func(v io.WriterTo) error {
+		if err := t.Execute(w, v); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprint(w, "\n")
+
+		return err
+	}), nil
+}
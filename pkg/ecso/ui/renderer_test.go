@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type testResult struct {
+	Name string
+	URL  string
+}
+
+func (r *testResult) WriteTo(w io.Writer) (int64, error) {
+	n, err := fmt.Fprintf(w, "%s|%s", r.Name, r.URL)
+	return int64(n), err
+}
+
+func TestRenderer(t *testing.T) {
+	result := &testResult{Name: "web", URL: "http://example.com"}
+
+	cases := []struct {
+		format string
+		tmpl   string
+		want   string
+	}{
+		{"", "", "web|http://example.com"},
+		{OutputTable, "", "web|http://example.com"},
+		{OutputJSON, "", "{\n  \"Name\": \"web\",\n  \"URL\": \"http://example.com\"\n}\n"},
+		{OutputYAML, "", "name: web\nurl: http://example.com\n"},
+		{OutputTemplate, "{{.URL}}", "http://example.com\n"},
+		{"", "{{.Name}}", "web\n"},
+	}
+
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+
+		r, err := NewRenderer(buf, c.format, c.tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.Render(result); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != c.want {
+			t.Errorf("Format %s: want %q, got %q", c.format, c.want, buf.String())
+		}
+	}
+}
+
+func TestRendererUnknownFormat(t *testing.T) {
+	if _, err := NewRenderer(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("Expected an error for unknown output format")
+	}
+}
@@ -48,7 +48,7 @@ func ValidateAny() StringValidator {
 func ValidateNotEmpty(msg string) StringValidator {
 	return StringValidatorFunc(func(v string) error {
 		if v == "" {
-			return fmt.Errorf(msg)
+			return fmt.Errorf("%s", msg)
 		}
 		return nil
 	})